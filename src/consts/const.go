@@ -11,6 +11,9 @@ const (
 	// LongQueryTimeout은 복잡한 쿼리나 대량 데이터 작업 시 타임아웃입니다.
 	LONG_QUERY_TIMEOUT int = 30
 
+	// MIGRATION_TIMEOUT은 스키마 마이그레이션 전체 실행 시 타임아웃입니다.
+	MIGRATION_TIMEOUT int = 300
+
 	//----------------------------------------------------------
 	// ShortWorkTimeout은 간단한 실행 시 타임아웃입니다.
 	SHORT_WORK_TIMEOUT int = 5
@@ -21,3 +24,328 @@ const (
 	// LongWorkTimeout은 복잡한  작업 시 타임아웃입니다.
 	LONG_WORK_TIMEOUT int = 30
 )
+
+// 좌석 배치 관련 상수
+const (
+	// BULK_SEAT_LIMIT는 seat 일괄 생성 요청 하나로 만들 수 있는 최대 seat 수입니다.
+	BULK_SEAT_LIMIT int = 500
+
+	// LAYOUT_FORMAT_VERSION은 배치도 내보내기/가져오기 JSON 문서의 형식 버전입니다.
+	LAYOUT_FORMAT_VERSION int = 1
+
+	// TITLE_MAX_LENGTH는 room_title/seat_title의 최대 글자 수입니다. (VARCHAR(100))
+	TITLE_MAX_LENGTH int = 100
+)
+
+// 목록 페이지 관련 상수
+const (
+	// PAGE_LIMIT_DEFAULT는 cursor만 보내고 limit을 생략했을 때의 페이지 크기입니다.
+	PAGE_LIMIT_DEFAULT int = 100
+
+	// PAGE_LIMIT_MAX는 limit으로 요청할 수 있는 최대 페이지 크기입니다.
+	PAGE_LIMIT_MAX int = 1000
+
+	// FILTER_MAX_CONDITIONS는 filter 조건식 하나에 사용할 수 있는 최대 조건 수입니다.
+	FILTER_MAX_CONDITIONS int = 32
+
+	// FILTER_MAX_DEPTH는 filter 조건식의 최대 괄호 중첩 깊이입니다.
+	FILTER_MAX_DEPTH int = 8
+)
+
+// 회원 상태 상수 (member_table.status)
+const (
+	// MEMBER_STATUS_ACTIVE는 정상 이용 중인 회원입니다.
+	MEMBER_STATUS_ACTIVE int = 0
+
+	// MEMBER_STATUS_SUSPENDED는 이용이 정지된 회원입니다.
+	MEMBER_STATUS_SUSPENDED int = 1
+
+	// MEMBER_STATUS_WITHDRAWN은 탈퇴한 회원입니다.
+	MEMBER_STATUS_WITHDRAWN int = 2
+)
+
+// 상품 유형 상수 (product_table.product_type)
+const (
+	// PRODUCT_TYPE_TIME은 잔여 시간(분)을 차감하는 시간권입니다.
+	PRODUCT_TYPE_TIME int = 0
+
+	// PRODUCT_TYPE_DAY는 만료 시각까지 자유롭게 이용하는 기간권(일일권 등)입니다.
+	PRODUCT_TYPE_DAY int = 1
+
+	// PRODUCT_TYPE_FIXED_SEAT는 지정 좌석을 기간 동안 이용하는 고정석 정기권입니다.
+	PRODUCT_TYPE_FIXED_SEAT int = 2
+)
+
+// 성별 제한 상수 (room_table.gender, seat_table.gender, member_table.gender)
+// room/seat에서는 GENDER_ANY가 제한 없음을, member에서는 미지정을 의미합니다.
+const (
+	// GENDER_ANY는 성별 제한이 없음을 의미합니다.
+	GENDER_ANY int = 0
+
+	// GENDER_MALE은 남성 전용(또는 남성 회원)입니다.
+	GENDER_MALE int = 1
+
+	// GENDER_FEMALE은 여성 전용(또는 여성 회원)입니다.
+	GENDER_FEMALE int = 2
+)
+
+// API 오류 코드 상수
+const (
+	// ERR_GENDER_MISMATCH는 좌석/룸의 성별 제한과 회원 성별이 맞지 않을 때 사용합니다.
+	ERR_GENDER_MISMATCH string = "GENDER_MISMATCH"
+
+	// ERR_UNAUTHENTICATED는 인증 정보가 없거나 유효하지 않을 때 사용합니다. (401)
+	ERR_UNAUTHENTICATED string = "UNAUTHENTICATED"
+
+	// ERR_FORBIDDEN은 인증되었지만 요청한 작업에 대한 권한이 없을 때 사용합니다. (403)
+	ERR_FORBIDDEN string = "FORBIDDEN"
+
+	// ERR_LAYOUT_CONFLICT는 좌석이 서로 겹치거나 room 영역을 벗어날 때 사용합니다. (409)
+	ERR_LAYOUT_CONFLICT string = "LAYOUT_CONFLICT"
+
+	// ERR_INVALID_FIELDS는 필드 값 검사에 실패했을 때 사용합니다. 필드별 원인은 FIELD_* 코드로 전달합니다. (422)
+	ERR_INVALID_FIELDS string = "INVALID_FIELDS"
+
+	// ERR_INVALID_BODY는 요청 본문을 JSON으로 해석할 수 없을 때 사용합니다. (400)
+	ERR_INVALID_BODY string = "INVALID_BODY"
+
+	// ERR_INVALID_PARAMETER는 경로나 쿼리 파라미터 값이 잘못되었을 때 사용합니다. (400)
+	ERR_INVALID_PARAMETER string = "INVALID_PARAMETER"
+
+	// ERR_INVALID_REQUEST는 요청 값이 업무 규칙에 맞지 않을 때 사용합니다. (400)
+	ERR_INVALID_REQUEST string = "INVALID_REQUEST"
+
+	// ERR_MEMBER_INACTIVE는 이용 중 상태가 아닌 회원이 좌석/예약/대기를 요청할 때 사용합니다. (403)
+	ERR_MEMBER_INACTIVE string = "MEMBER_INACTIVE"
+
+	// ERR_PASS_UNAVAILABLE은 사용할 수 있는 이용권이 없거나 잔여 시간/만료 시각/고정석 제한에 걸릴 때 사용합니다. (403)
+	ERR_PASS_UNAVAILABLE string = "PASS_UNAVAILABLE"
+
+	// ERR_NOT_FOUND는 요청한 대상(room, seat, 회원 등)이 없을 때 사용합니다. (404)
+	ERR_NOT_FOUND string = "NOT_FOUND"
+
+	// ERR_DUPLICATE_CODE는 company_code 안에서 room_code/seat_code가 중복될 때 사용합니다. (400)
+	ERR_DUPLICATE_CODE string = "DUPLICATE_CODE"
+
+	// ERR_ROOM_NOT_EMPTY는 좌석이 남아 있는 room을 cascade 없이 삭제하려 할 때 사용합니다. (409)
+	ERR_ROOM_NOT_EMPTY string = "ROOM_NOT_EMPTY"
+
+	// ERR_ROOM_NOT_FOUND는 seat의 room_code가 가리키는 room이 없을 때 사용합니다. (409)
+	ERR_ROOM_NOT_FOUND string = "ROOM_NOT_FOUND"
+
+	// ERR_CONFLICT는 요청이 현재 상태와 충돌할 때 사용합니다. (409)
+	ERR_CONFLICT string = "CONFLICT"
+
+	// ERR_NO_OPEN_SESSION은 좌석에 사용 중인 세션이 없을 때 사용합니다. (409)
+	ERR_NO_OPEN_SESSION string = "NO_OPEN_SESSION"
+
+	// ERR_SEAT_OCCUPIED는 좌석이 이미 사용 중일 때 사용합니다. (409)
+	ERR_SEAT_OCCUPIED string = "SEAT_OCCUPIED"
+
+	// ERR_SEAT_RESERVED는 좌석이 다른 회원에게 예약/대기 배정되어 있거나 예약 시간대가 겹칠 때 사용합니다. (409)
+	ERR_SEAT_RESERVED string = "SEAT_RESERVED"
+
+	// ERR_SEAT_IN_USE는 사용 중인 세션, 확정 예약, 고정석 이용권이 있는 좌석을 삭제하려 할 때 사용합니다. (409)
+	ERR_SEAT_IN_USE string = "SEAT_IN_USE"
+
+	// ERR_UPGRADE_REQUIRED는 지원하지 않는 WebSocket 버전으로 연결할 때 사용합니다. (426)
+	ERR_UPGRADE_REQUIRED string = "UPGRADE_REQUIRED"
+
+	// ERR_PRECONDITION_FAILED는 If-Match 버전이 현재 버전과 다를 때 사용합니다. (412)
+	ERR_PRECONDITION_FAILED string = "PRECONDITION_FAILED"
+
+	// ERR_UNSUPPORTED_MEDIA_TYPE은 PATCH의 Content-Type을 지원하지 않을 때 사용합니다. (415)
+	ERR_UNSUPPORTED_MEDIA_TYPE string = "UNSUPPORTED_MEDIA_TYPE"
+
+	// ERR_PATCH_FAILED는 JSON Patch 연산을 적용할 수 없을 때 사용합니다. (422)
+	ERR_PATCH_FAILED string = "PATCH_FAILED"
+
+	// ERR_PATCH_TEST_FAILED는 JSON Patch의 test 연산이 실패했을 때 사용합니다. (409)
+	ERR_PATCH_TEST_FAILED string = "PATCH_TEST_FAILED"
+
+	// ERR_INTERNAL은 서버 내부 오류입니다. 원인은 서버 로그에만 남기고 응답에는 포함하지 않습니다. (500)
+	ERR_INTERNAL string = "INTERNAL"
+)
+
+// 필드 오류 코드 상수 (ERR_INVALID_FIELDS 응답의 errors[].code)
+// 프론트엔드가 코드별로 메시지를 현지화할 수 있도록 값은 바꾸지 않습니다.
+const (
+	// FIELD_REQUIRED는 필수 필드가 없을 때 사용합니다.
+	FIELD_REQUIRED string = "REQUIRED"
+
+	// FIELD_UNKNOWN은 정의되지 않은 필드일 때 사용합니다.
+	FIELD_UNKNOWN string = "UNKNOWN_FIELD"
+
+	// FIELD_READ_ONLY는 읽기 전용 필드를 바꾸려 할 때 사용합니다.
+	FIELD_READ_ONLY string = "READ_ONLY"
+
+	// FIELD_NULL은 null을 허용하지 않는 필드에 null을 보냈을 때 사용합니다.
+	FIELD_NULL string = "NULL_NOT_ALLOWED"
+
+	// FIELD_NOT_INTEGER는 정수 필드에 정수가 아닌 값을 보냈을 때 사용합니다.
+	FIELD_NOT_INTEGER string = "NOT_INTEGER"
+
+	// FIELD_NOT_STRING은 문자열 필드에 문자열이 아닌 값을 보냈을 때 사용합니다.
+	FIELD_NOT_STRING string = "NOT_STRING"
+
+	// FIELD_INVALID_COLOR는 색상이 #RGB, #RRGGBB, #RRGGBBAA 형식의 16진수가 아닐 때 사용합니다.
+	FIELD_INVALID_COLOR string = "INVALID_COLOR"
+
+	// FIELD_NEGATIVE는 위치/크기/차단기 번호가 음수일 때 사용합니다.
+	FIELD_NEGATIVE string = "NEGATIVE"
+
+	// FIELD_INVALID_FLAG는 0/1만 허용하는 플래그 필드에 다른 값을 보냈을 때 사용합니다.
+	FIELD_INVALID_FLAG string = "INVALID_FLAG"
+
+	// FIELD_INVALID_GENDER는 gender가 GENDER_* 값이 아닐 때 사용합니다.
+	FIELD_INVALID_GENDER string = "INVALID_GENDER"
+
+	// FIELD_TOO_LONG은 제목이 TITLE_MAX_LENGTH보다 길 때 사용합니다.
+	FIELD_TOO_LONG string = "TOO_LONG"
+)
+
+// 좌석 이용 세션 관련 상수
+const (
+	// SEAT_SESSION_EXPIRE_INTERVAL은 예정 종료 시각이 지난 세션을 자동 종료하는 작업의 실행 주기(초)입니다.
+	SEAT_SESSION_EXPIRE_INTERVAL int = 60
+)
+
+// 예약 상태 상수 (reservation_table.status)
+const (
+	// RESERVATION_STATUS_BOOKED는 예약이 확정되어 이용을 기다리는 상태입니다.
+	RESERVATION_STATUS_BOOKED int = 0
+
+	// RESERVATION_STATUS_CANCELLED는 예약이 취소된 상태입니다.
+	RESERVATION_STATUS_CANCELLED int = 1
+
+	// RESERVATION_STATUS_CHECKED_IN은 예약자가 체크인한 상태입니다.
+	RESERVATION_STATUS_CHECKED_IN int = 2
+
+	// RESERVATION_STATUS_NO_SHOW는 예약자가 나타나지 않아 자동 해제된 상태입니다.
+	RESERVATION_STATUS_NO_SHOW int = 3
+
+	// RESERVATION_NO_SHOW_GRACE_MINUTES는 시작 시각 이후 노쇼로 처리하기까지의 유예 시간(분)입니다.
+	RESERVATION_NO_SHOW_GRACE_MINUTES int = 15
+
+	// RESERVATION_NO_SHOW_INTERVAL은 노쇼 자동 해제 작업의 실행 주기(초)입니다.
+	RESERVATION_NO_SHOW_INTERVAL int = 60
+)
+
+// 대기열 상태 상수 (waitlist_table.status)
+const (
+	// WAITLIST_STATUS_WAITING은 좌석 배정을 기다리는 상태입니다.
+	WAITLIST_STATUS_WAITING int = 0
+
+	// WAITLIST_STATUS_PROMOTED는 빈 좌석이 배정되어 체크인을 기다리는 상태입니다.
+	WAITLIST_STATUS_PROMOTED int = 1
+
+	// WAITLIST_STATUS_CANCELLED는 대기를 취소한 상태입니다.
+	WAITLIST_STATUS_CANCELLED int = 2
+
+	// WAITLIST_STATUS_SEATED는 배정된 좌석에 체크인한 상태입니다.
+	WAITLIST_STATUS_SEATED int = 3
+
+	// WAITLIST_STATUS_EXPIRED는 배정된 좌석에 잡아두는 시간 안에 체크인하지 않아 배정이 해제된 상태입니다.
+	WAITLIST_STATUS_EXPIRED int = 4
+
+	// WAITLIST_HOLD_MINUTES는 배정된 좌석을 대기자에게 잡아두는 시간(분)입니다.
+	WAITLIST_HOLD_MINUTES int = 10
+
+	// WAITLIST_HOLD_EXPIRE_INTERVAL은 지난 좌석 배정을 해제하는 작업의 실행 주기(초)입니다.
+	WAITLIST_HOLD_EXPIRE_INTERVAL int = 60
+)
+
+// 인증 역할 상수 (JWT role 클레임, staff_table.role)
+const (
+	// ROLE_OWNER는 회사의 모든 설정을 관리하는 점주 계정입니다.
+	ROLE_OWNER string = "owner"
+
+	// ROLE_STAFF는 좌석/회원 운영을 담당하는 직원 계정입니다.
+	ROLE_STAFF string = "staff"
+
+	// ROLE_KIOSK는 매장에 설치된 키오스크 장비입니다.
+	ROLE_KIOSK string = "kiosk"
+
+	// ROLE_MEMBER는 본인 좌석/예약만 다룰 수 있는 회원입니다.
+	ROLE_MEMBER string = "member"
+)
+
+// 인증 토큰 상수
+const (
+	// TOKEN_TYPE_ACCESS는 API 호출에 사용하는 액세스 토큰입니다.
+	TOKEN_TYPE_ACCESS string = "access"
+
+	// TOKEN_TYPE_REFRESH는 새 토큰을 발급받을 때만 사용하는 리프레시 토큰입니다.
+	TOKEN_TYPE_REFRESH string = "refresh"
+
+	// ACCESS_TOKEN_TTL_MINUTES는 액세스 토큰의 유효 시간(분)입니다.
+	ACCESS_TOKEN_TTL_MINUTES int = 30
+
+	// REFRESH_TOKEN_TTL_HOURS는 리프레시 토큰의 유효 시간(시간)입니다.
+	REFRESH_TOKEN_TTL_HOURS int = 24 * 14
+)
+
+// 작업 큐 상수 (job_table.status 및 재시도 정책)
+const (
+	// JOB_STATUS_PENDING은 실행을 기다리는 작업입니다. (재시도 대기 포함)
+	JOB_STATUS_PENDING int = 0
+
+	// JOB_STATUS_RUNNING은 워커가 임대(lease)하여 실행 중인 작업입니다.
+	JOB_STATUS_RUNNING int = 1
+
+	// JOB_STATUS_DONE은 처리가 완료된 작업입니다.
+	JOB_STATUS_DONE int = 2
+
+	// JOB_STATUS_DEAD는 최대 시도 횟수를 넘겨 더 이상 재시도하지 않는 작업입니다. (dead letter)
+	JOB_STATUS_DEAD int = 3
+
+	// JOB_MAX_ATTEMPTS는 작업 하나의 최대 실행 시도 횟수입니다.
+	JOB_MAX_ATTEMPTS int = 5
+
+	// JOB_VISIBILITY_TIMEOUT은 임대한 작업을 다른 워커가 다시 가져가기까지의 시간(초)입니다.
+	// 워커가 중단되어 완료/실패를 기록하지 못한 작업은 이 시간이 지나면 다시 실행됩니다.
+	JOB_VISIBILITY_TIMEOUT int = 60
+
+	// JOB_RETRY_BASE_DELAY는 첫 재시도까지의 대기 시간(초)이며, 재시도마다 두 배로 늘어납니다.
+	JOB_RETRY_BASE_DELAY int = 5
+
+	// JOB_RETRY_MAX_DELAY는 재시도 대기 시간의 상한(초)입니다.
+	JOB_RETRY_MAX_DELAY int = 600
+
+	// JOB_POLL_INTERVAL은 실행할 작업이 없을 때 큐를 다시 확인하는 주기(초)입니다.
+	JOB_POLL_INTERVAL int = 2
+
+	// JOB_RETENTION_HOURS는 완료된 작업을 보관하는 시간(시간)입니다.
+	JOB_RETENTION_HOURS int = 24 * 7
+
+	// JOB_PRIORITY_LOW는 알림 등 늦게 처리되어도 되는 작업의 우선순위입니다.
+	JOB_PRIORITY_LOW int = 0
+
+	// JOB_PRIORITY_NORMAL은 일반 작업의 우선순위입니다.
+	JOB_PRIORITY_NORMAL int = 5
+
+	// JOB_PRIORITY_HIGH는 먼저 처리해야 하는 작업(예: 전원 제어)의 우선순위입니다.
+	JOB_PRIORITY_HIGH int = 10
+
+	// JOB_WORKER_COUNT는 기본 작업 워커 수입니다. (JOB_WORKERS 환경변수로 변경)
+	JOB_WORKER_COUNT int = 4
+)
+
+// 실시간 이벤트 스트림(/events) 관련 상수
+const (
+	// EVENT_BUFFER_SIZE는 재연결한 클라이언트에게 다시 보내기 위해 보관하는 최근 이벤트 수입니다.
+	EVENT_BUFFER_SIZE int = 1000
+
+	// EVENT_SUBSCRIBER_BUFFER는 구독자별 전송 대기 이벤트 수입니다. 넘치면 연결을 끊습니다.
+	EVENT_SUBSCRIBER_BUFFER int = 256
+
+	// EVENT_HEARTBEAT_INTERVAL은 연결 유지를 위해 heartbeat(SSE 주석, WebSocket ping)를 보내는 주기(초)입니다.
+	EVENT_HEARTBEAT_INTERVAL int = 15
+
+	// EVENT_RETRY_MILLIS는 SSE 클라이언트에게 알려주는 재연결 대기 시간(밀리초)입니다.
+	EVENT_RETRY_MILLIS int = 3000
+
+	// WEBHOOK_TIMEOUT은 webhook 전송 요청 하나의 타임아웃(초)입니다.
+	// 작업 타임아웃(DEFAULT_WORK_TIMEOUT)보다 짧아야 전송 결과를 기록할 수 있습니다.
+	WEBHOOK_TIMEOUT int = 8
+)
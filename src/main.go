@@ -8,12 +8,16 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 
+	"AllinB/src/consts"
+	"AllinB/src/migrations"
+	"AllinB/src/power"
 	"AllinB/src/tables"
 	"AllinB/src/utils"
 )
@@ -75,22 +79,152 @@ func main() {
 	// tables 패키지에 DB 연결 전달
 	utils.DB = db
 
+	// migrate 하위 명령: AllinB migrate up | down [n] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(db, os.Args[2:])
+		return
+	}
+
+	// 서버 시작 전 적용되지 않은 스키마 마이그레이션을 모두 적용합니다.
+	migrateCtx, migrateCancel := context.WithTimeout(context.Background(),
+		time.Duration(consts.MIGRATION_TIMEOUT)*time.Second)
+	if _, err := migrations.Up(migrateCtx, db); err != nil {
+		log.Fatalf("스키마 마이그레이션 실패: %v", err)
+	}
+	migrateCancel()
+
+	// 인증 토큰 서명 키 설정
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if len(jwtSecret) < 32 {
+		log.Fatal("JWT_SECRET 환경변수는 32자 이상이어야 합니다.")
+	}
+	utils.SetJWTSecret([]byte(jwtSecret))
+
+	// 최초 설치 시 BOOTSTRAP_OWNER_* 환경 변수로 점주 계정을 만듭니다.
+	if code := os.Getenv("BOOTSTRAP_OWNER_COMPANY_CODE"); code != "" {
+		companyCode, err := strconv.Atoi(code)
+		if err != nil || companyCode <= 0 {
+			log.Fatalf("잘못된 BOOTSTRAP_OWNER_COMPANY_CODE: %s", code)
+		}
+		loginID, password := os.Getenv("BOOTSTRAP_OWNER_LOGIN_ID"), os.Getenv("BOOTSTRAP_OWNER_PASSWORD")
+		if loginID == "" || len(password) < 8 {
+			log.Fatal("BOOTSTRAP_OWNER_LOGIN_ID와 8자 이상의 BOOTSTRAP_OWNER_PASSWORD가 필요합니다.")
+		}
+		ownerCtx, ownerCancel := context.WithTimeout(context.Background(),
+			time.Duration(consts.DEFAULT_QUERY_TIMEOUT)*time.Second)
+		if err := tables.EnsureOwner(ownerCtx, companyCode, loginID, password); err != nil {
+			log.Fatalf("점주 계정 생성 실패: %v", err)
+		}
+		ownerCancel()
+	}
+
 	// tables 패키지에 작업 큐 함수 전달
 	utils.SetEnqueueJobFunc(utils.EnqueueJob)
 
+	// 전원 제어 드라이버 설정 (POWER_CONTROLLER=modbus이면 Modbus/TCP 장비 사용)
+	switch os.Getenv("POWER_CONTROLLER") {
+	case "modbus":
+		addr := os.Getenv("POWER_CONTROLLER_ADDR")
+		if addr == "" {
+			log.Fatal("POWER_CONTROLLER_ADDR 환경변수가 설정되어 있지 않습니다.")
+		}
+		unitID := 1
+		if v := os.Getenv("POWER_CONTROLLER_UNIT_ID"); v != "" {
+			if unitID, err = strconv.Atoi(v); err != nil || unitID < 0 || unitID > 255 {
+				log.Fatalf("잘못된 POWER_CONTROLLER_UNIT_ID: %s", v)
+			}
+		}
+		log.Printf("Modbus 전원 제어 장비 사용: %s (unit %d)", addr, unitID)
+		power.SetController(power.NewModbusController(addr, byte(unitID)))
+	default:
+		log.Println("전원 제어 시뮬레이터를 사용합니다.")
+		power.SetController(power.NewSimulatedController())
+	}
+
+	// room/seat 핸들러 저장소 설정
+	tables.SetRoomRepository(tables.NewPostgresRoomRepository(utils.DB))
+	tables.SetSeatRepository(tables.NewPostgresSeatRepository(utils.DB))
+
+	// 비동기 작업 처리 함수 등록
+	tables.RegisterSeatSessionJobs()
+	tables.RegisterReservationJobs()
+	tables.RegisterWaitlistJobs()
+	tables.RegisterPowerJobs()
+	tables.RegisterEventJobs()
+	tables.RegisterWebhookJobs()
+
 	// 비동기 작업 큐(worker) 시작
-	utils.StartJobWorker()
+	workerCount := consts.JOB_WORKER_COUNT
+	if v := os.Getenv("JOB_WORKERS"); v != "" {
+		if workerCount, err = strconv.Atoi(v); err != nil || workerCount <= 0 {
+			log.Fatalf("잘못된 JOB_WORKERS: %s", v)
+		}
+	}
+	utils.StartJobWorkers(workerCount)
+
+	// 노쇼 예약 자동 해제 작업을 주기적으로 큐에 넣습니다.
+	utils.ScheduleJob(utils.Job{Name: "ReservationNoShowRelease"},
+		time.Duration(consts.RESERVATION_NO_SHOW_INTERVAL)*time.Second)
+	// 잡아두는 시간이 지난 대기열 좌석 배정 해제 작업을 주기적으로 큐에 넣습니다.
+	utils.ScheduleJob(utils.Job{Name: "WaitlistHoldExpire"},
+		time.Duration(consts.WAITLIST_HOLD_EXPIRE_INTERVAL)*time.Second)
+	// 예정 종료 시각이 지난 세션 자동 종료 작업을 주기적으로 큐에 넣습니다.
+	utils.ScheduleJob(utils.Job{Name: "SeatSessionExpire"},
+		time.Duration(consts.SEAT_SESSION_EXPIRE_INTERVAL)*time.Second)
 
 	// 라우터 초기화
-	r := mux.NewRouter()
+	root := mux.NewRouter()
+	// 로그인/토큰 갱신 라우트는 인증 없이 호출됩니다.
+	tables.RegisterAuthRoutes(root)
+	// 오류 코드 카탈로그(/errors)도 인증 없이 조회합니다.
+	tables.RegisterErrorRoutes(root)
+
+	// 그 외 모든 라우트는 인증된 호출자의 테넌트(company_code)를 확인한 뒤 처리합니다.
+	r := root.PathPrefix("/").Subrouter()
+	r.Use(utils.TenantMiddleware(tables.ResolveTenant))
+
 	// room_table 관련 라우트는 tables/room.go에서 등록합니다.
 	tables.RegisterRoomRoutes(r)
+	// room에 속한 seat 중첩 라우트(/rooms/{room_code}/seats) 등록
+	tables.RegisterRoomSeatRoutes(r)
+	// 배치도 내보내기/가져오기/SVG 라우트 등록
+	tables.RegisterLayoutRoutes(r)
 
 	// seat_table 관련 라우트 등록 필요
 	tables.RegisterSeatRoutes(r)
+	// member_table 관련 라우트 등록
+	tables.RegisterMemberRoutes(r)
+
+	// 상품 및 회원 이용권 라우트 등록
+	tables.RegisterProductRoutes(r)
+	tables.RegisterPassRoutes(r)
+
+	// 좌석 체크인/체크아웃 라우트 등록
+	tables.RegisterSeatSessionRoutes(r)
+
+	// 좌석 예약 라우트 등록
+	tables.RegisterReservationRoutes(r)
+
+	// room 대기열 라우트 등록
+	tables.RegisterWaitlistRoutes(r)
+
+	// 전원 차단기 상태 라우트 등록
+	tables.RegisterBreakerRoutes(r)
+
+	// 키오스크 관리 및 /kiosk 셀프서비스 라우트 등록
+	tables.RegisterKioskRoutes(r)
+
+	// 점주/직원 계정 관리 라우트 등록
+	tables.RegisterStaffRoutes(r)
+
+	// 실시간 이벤트 스트림(SSE/WebSocket) 라우트 등록
+	tables.RegisterEventRoutes(r)
+
+	// webhook 구독 관리 라우트 등록
+	tables.RegisterWebhookRoutes(r)
 
 	// 로깅 미들웨어와 CORS 미들웨어를 함께 적용
-	handler := utils.LoggingMiddleware(utils.CorsMiddleware(r))
+	handler := utils.LoggingMiddleware(utils.CorsMiddleware(root))
 	http.Handle("/", handler)
 
 	log.Println("서버가 :8080 포트에서 실행 중입니다.")
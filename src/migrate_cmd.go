@@ -0,0 +1,66 @@
+// migrate_cmd.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"AllinB/src/consts"
+	"AllinB/src/migrations"
+)
+
+// runMigrateCommand는 migrate 하위 명령을 실행합니다.
+//
+//	migrate up        적용되지 않은 마이그레이션을 모두 적용
+//	migrate down [n]  최근 마이그레이션 n개(기본 1개)를 되돌림
+//	migrate status    마이그레이션별 적용 여부 출력
+func runMigrateCommand(db *sql.DB, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(consts.MIGRATION_TIMEOUT)*time.Second)
+	defer cancel()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		n, err := migrations.Up(ctx, db)
+		if err != nil {
+			log.Fatalf("마이그레이션 실패: %v", err)
+		}
+		log.Printf("마이그레이션 %d개를 적용했습니다.", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				log.Fatalf("잘못된 되돌릴 개수: %s", args[1])
+			}
+		}
+		n, err := migrations.Down(ctx, db, steps)
+		if err != nil {
+			log.Fatalf("마이그레이션 되돌리기 실패: %v", err)
+		}
+		log.Printf("마이그레이션 %d개를 되돌렸습니다.", n)
+	case "status":
+		statuses, err := migrations.GetStatus(ctx, db)
+		if err != nil {
+			log.Fatalf("마이그레이션 상태 조회 실패: %v", err)
+		}
+		for _, s := range statuses {
+			applied := "미적용"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Local().Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		log.Fatalf("알 수 없는 migrate 명령: %s (up, down [n], status)", command)
+	}
+}
@@ -0,0 +1,15 @@
+-- 0001_baseline 되돌리기: 0001이 만든 테이블을 삭제합니다. (데이터가 함께 삭제됩니다)
+-- room_table, seat_table은 마이그레이션 이전부터 있던 테이블이므로 삭제하지 않고,
+-- 0001이 추가한 seat_table.room_code 컬럼만 제거합니다.
+DROP TABLE IF EXISTS job_table;
+DROP TABLE IF EXISTS webhook_delivery_table;
+DROP TABLE IF EXISTS webhook_table;
+DROP TABLE IF EXISTS kiosk_table;
+DROP TABLE IF EXISTS waitlist_table;
+DROP TABLE IF EXISTS reservation_table;
+DROP TABLE IF EXISTS seat_session_table;
+DROP TABLE IF EXISTS pass_table;
+DROP TABLE IF EXISTS product_table;
+DROP TABLE IF EXISTS member_table;
+DROP TABLE IF EXISTS staff_table;
+ALTER TABLE seat_table DROP COLUMN IF EXISTS room_code;
@@ -0,0 +1,225 @@
+-- 0001_baseline: 현재 서버가 사용하는 테이블을 생성합니다.
+-- room_table, seat_table은 마이그레이션 도입 이전부터 있을 수 있으므로 모든 문장은 다시 실행해도 안전해야 합니다.
+
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- room_table: 배치도의 room
+CREATE TABLE IF NOT EXISTS room_table (
+	auto_increment         SERIAL PRIMARY KEY,
+	company_code           INTEGER NOT NULL,
+	room_code              INTEGER NOT NULL,
+	room_title             VARCHAR(100) NOT NULL DEFAULT '',
+	title_background_color VARCHAR(20) NOT NULL DEFAULT '#000000',
+	title_text_color       VARCHAR(20) NOT NULL DEFAULT '#FFFFFF',
+	room_background_color  VARCHAR(20) NOT NULL DEFAULT '#FFFFFF',
+	room_top               INTEGER NOT NULL DEFAULT 0,
+	room_left              INTEGER NOT NULL DEFAULT 0,
+	room_width             INTEGER NOT NULL DEFAULT 100,
+	room_height            INTEGER NOT NULL DEFAULT 100,
+	gender                 INTEGER NOT NULL DEFAULT 0,
+	waiting                INTEGER NOT NULL DEFAULT 0,
+	release                INTEGER NOT NULL DEFAULT 0,
+	hide_title             INTEGER NOT NULL DEFAULT 0,
+	transparent_background INTEGER NOT NULL DEFAULT 0,
+	hide_border            INTEGER NOT NULL DEFAULT 0,
+	kiosk_disabled         INTEGER NOT NULL DEFAULT 0,
+	power_control          INTEGER NOT NULL DEFAULT 0,
+	breaker_number         INTEGER NOT NULL DEFAULT 0
+);
+
+-- seat_table: room 안의 seat
+CREATE TABLE IF NOT EXISTS seat_table (
+	auto_increment         SERIAL PRIMARY KEY,
+	company_code           INTEGER NOT NULL,
+	seat_code              INTEGER NOT NULL,
+	room_code              INTEGER NOT NULL,
+	seat_title             VARCHAR(100) NOT NULL DEFAULT '',
+	title_background_color VARCHAR(20) NOT NULL DEFAULT '#000000',
+	title_text_color       VARCHAR(20) NOT NULL DEFAULT '#FFFFFF',
+	seat_background_color  VARCHAR(20) NOT NULL DEFAULT '#FFFFFF',
+	seat_top               INTEGER NOT NULL DEFAULT 0,
+	seat_left              INTEGER NOT NULL DEFAULT 0,
+	seat_width             INTEGER NOT NULL DEFAULT 100,
+	seat_height            INTEGER NOT NULL DEFAULT 100,
+	gender                 INTEGER NOT NULL DEFAULT 0,
+	waiting                INTEGER NOT NULL DEFAULT 0,
+	release                INTEGER NOT NULL DEFAULT 0,
+	hide_title             INTEGER NOT NULL DEFAULT 0,
+	transparent_background INTEGER NOT NULL DEFAULT 0,
+	hide_border            INTEGER NOT NULL DEFAULT 0,
+	kiosk_disabled         INTEGER NOT NULL DEFAULT 0,
+	power_control          INTEGER NOT NULL DEFAULT 0,
+	breaker_number         INTEGER NOT NULL DEFAULT 0
+);
+-- 이전 버전의 seat_table에는 room_code가 없습니다. 컬럼을 추가한 뒤 기존 seat은
+-- 회사별 미배정 room(room_code 0)에 넣고 NOT NULL로 바꿉니다. 점주가 seat을 원래 room으로 옮겨야 합니다.
+ALTER TABLE seat_table ADD COLUMN IF NOT EXISTS room_code INTEGER;
+INSERT INTO room_table (company_code, room_code, room_title)
+	SELECT DISTINCT s.company_code, 0, '미배정'
+	FROM seat_table s
+	WHERE s.room_code IS NULL
+	  AND NOT EXISTS (SELECT 1 FROM room_table r WHERE r.company_code = s.company_code AND r.room_code = 0);
+UPDATE seat_table SET room_code = 0 WHERE room_code IS NULL;
+ALTER TABLE seat_table ALTER COLUMN room_code SET NOT NULL;
+
+-- staff_table: 점주/직원 계정
+CREATE TABLE IF NOT EXISTS staff_table (
+	staff_id      SERIAL PRIMARY KEY,
+	company_code  INTEGER NOT NULL,
+	login_id      VARCHAR(50) NOT NULL,
+	password_hash VARCHAR(100) NOT NULL,
+	staff_name    VARCHAR(100) NOT NULL DEFAULT '',
+	role          VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'staff')),
+	enabled       INTEGER NOT NULL DEFAULT 1,
+	created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS staff_company_login_idx
+	ON staff_table (company_code, login_id);
+
+-- member_table: 회원
+CREATE TABLE IF NOT EXISTS member_table (
+	member_id     SERIAL PRIMARY KEY,
+	company_code  INTEGER NOT NULL,
+	member_name   VARCHAR(100) NOT NULL,
+	phone         VARCHAR(20) NOT NULL DEFAULT '',
+	gender        INTEGER NOT NULL DEFAULT 0,
+	status        INTEGER NOT NULL DEFAULT 0,
+	password_hash VARCHAR(100) NOT NULL DEFAULT '',
+	created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS member_company_phone_idx
+	ON member_table (company_code, phone) WHERE phone <> '';
+
+-- product_table: 판매 상품
+CREATE TABLE IF NOT EXISTS product_table (
+	product_id   SERIAL PRIMARY KEY,
+	company_code INTEGER NOT NULL,
+	product_name VARCHAR(100) NOT NULL,
+	product_type INTEGER NOT NULL DEFAULT 0,
+	minutes      INTEGER NOT NULL DEFAULT 0 CHECK (minutes >= 0),
+	valid_days   INTEGER NOT NULL DEFAULT 0 CHECK (valid_days >= 0),
+	price        INTEGER NOT NULL DEFAULT 0 CHECK (price >= 0),
+	on_sale      INTEGER NOT NULL DEFAULT 1
+);
+
+-- pass_table: 회원이 구매한 이용권
+CREATE TABLE IF NOT EXISTS pass_table (
+	pass_id           SERIAL PRIMARY KEY,
+	company_code      INTEGER NOT NULL,
+	member_id         INTEGER NOT NULL,
+	product_id        INTEGER NOT NULL,
+	pass_type         INTEGER NOT NULL,
+	seat_code         INTEGER,
+	remaining_minutes INTEGER CHECK (remaining_minutes >= 0),
+	expires_at        TIMESTAMPTZ,
+	purchased_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS pass_member_idx ON pass_table (member_id);
+
+-- seat_session_table: 좌석 이용 세션 (end_time이 NULL이면 사용 중)
+CREATE TABLE IF NOT EXISTS seat_session_table (
+	auto_increment   SERIAL PRIMARY KEY,
+	company_code     INTEGER NOT NULL,
+	seat_code        INTEGER NOT NULL,
+	member_id        INTEGER NOT NULL,
+	pass_id          INTEGER,
+	start_time       TIMESTAMPTZ NOT NULL DEFAULT now(),
+	planned_end_time TIMESTAMPTZ,
+	end_time         TIMESTAMPTZ,
+	CHECK (end_time IS NULL OR end_time >= start_time)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS seat_session_open_company_seat_idx
+	ON seat_session_table (company_code, seat_code) WHERE end_time IS NULL;
+
+-- reservation_table: 좌석 예약 (같은 회사의 같은 좌석에 활성/체크인 예약 시간이 겹치지 않아야 합니다)
+CREATE TABLE IF NOT EXISTS reservation_table (
+	reservation_id SERIAL PRIMARY KEY,
+	company_code   INTEGER NOT NULL,
+	seat_code      INTEGER NOT NULL,
+	member_id      INTEGER NOT NULL,
+	start_time     TIMESTAMPTZ NOT NULL,
+	end_time       TIMESTAMPTZ NOT NULL,
+	status         INTEGER NOT NULL DEFAULT 0,
+	created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CHECK (end_time > start_time),
+	CONSTRAINT reservation_no_overlap EXCLUDE USING gist (
+		company_code WITH =,
+		seat_code WITH =,
+		tstzrange(start_time, end_time) WITH &&
+	) WHERE (status IN (0, 2))
+);
+CREATE INDEX IF NOT EXISTS reservation_member_idx ON reservation_table (member_id);
+
+-- waitlist_table: room 대기열
+CREATE TABLE IF NOT EXISTS waitlist_table (
+	waitlist_id  SERIAL PRIMARY KEY,
+	company_code INTEGER NOT NULL,
+	room_code    INTEGER NOT NULL,
+	member_id    INTEGER NOT NULL,
+	position     INTEGER NOT NULL,
+	status       INTEGER NOT NULL DEFAULT 0,
+	seat_code    INTEGER,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+	promoted_at  TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS waitlist_company_room_member_idx
+	ON waitlist_table (company_code, room_code, member_id) WHERE status = 0;
+CREATE INDEX IF NOT EXISTS waitlist_company_room_position_idx
+	ON waitlist_table (company_code, room_code, position) WHERE status = 0;
+
+-- kiosk_table: 키오스크 장비 (인증 키는 SHA-256 해시로만 저장)
+CREATE TABLE IF NOT EXISTS kiosk_table (
+	kiosk_id     SERIAL PRIMARY KEY,
+	company_code INTEGER NOT NULL,
+	kiosk_name   VARCHAR(100) NOT NULL,
+	key_hash     CHAR(64) NOT NULL,
+	enabled      INTEGER NOT NULL DEFAULT 1,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- webhook_table, webhook_delivery_table: webhook 구독과 전송 시도 기록
+CREATE TABLE IF NOT EXISTS webhook_table (
+	webhook_id   SERIAL PRIMARY KEY,
+	company_code INTEGER NOT NULL,
+	url          TEXT NOT NULL,
+	secret       CHAR(64) NOT NULL,
+	events       TEXT[] NOT NULL DEFAULT '{}',
+	enabled      INTEGER NOT NULL DEFAULT 1,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS webhook_company_idx ON webhook_table (company_code);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_table (
+	delivery_id  BIGSERIAL PRIMARY KEY,
+	webhook_id   INTEGER NOT NULL,
+	company_code INTEGER NOT NULL,
+	job_id       BIGINT NOT NULL,
+	event_type   VARCHAR(50) NOT NULL,
+	attempt      INTEGER NOT NULL,
+	status_code  INTEGER NOT NULL DEFAULT 0,
+	success      INTEGER NOT NULL DEFAULT 0,
+	error        TEXT NOT NULL DEFAULT '',
+	duration_ms  INTEGER NOT NULL DEFAULT 0,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS webhook_delivery_webhook_idx
+	ON webhook_delivery_table (webhook_id, delivery_id DESC);
+
+-- job_table: 비동기 작업 큐 (워커는 SELECT ... FOR UPDATE SKIP LOCKED로 작업을 임대하므로 여러 서버가 공유할 수 있습니다)
+CREATE TABLE IF NOT EXISTS job_table (
+	job_id       BIGSERIAL PRIMARY KEY,
+	name         VARCHAR(100) NOT NULL,
+	data         JSONB NOT NULL DEFAULT '{}',
+	priority     INTEGER NOT NULL DEFAULT 0,
+	status       INTEGER NOT NULL DEFAULT 0,
+	attempts     INTEGER NOT NULL DEFAULT 0,
+	max_attempts INTEGER NOT NULL,
+	run_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+	leased_until TIMESTAMPTZ,
+	last_error   TEXT,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS job_ready_idx
+	ON job_table (priority DESC, run_at, job_id) WHERE status IN (0, 1);
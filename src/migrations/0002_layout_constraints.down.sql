@@ -0,0 +1,34 @@
+-- 0002_layout_constraints 되돌리기
+
+ALTER TABLE room_table
+	DROP CONSTRAINT IF EXISTS room_gender_check,
+	DROP CONSTRAINT IF EXISTS room_waiting_check,
+	DROP CONSTRAINT IF EXISTS room_release_check,
+	DROP CONSTRAINT IF EXISTS room_hide_title_check,
+	DROP CONSTRAINT IF EXISTS room_transparent_background_check,
+	DROP CONSTRAINT IF EXISTS room_hide_border_check,
+	DROP CONSTRAINT IF EXISTS room_kiosk_disabled_check,
+	DROP CONSTRAINT IF EXISTS room_power_control_check,
+	DROP CONSTRAINT IF EXISTS room_breaker_number_check,
+	DROP CONSTRAINT IF EXISTS room_size_check;
+DROP INDEX IF EXISTS room_company_room_code_idx;
+
+ALTER TABLE seat_table
+	DROP CONSTRAINT IF EXISTS seat_gender_check,
+	DROP CONSTRAINT IF EXISTS seat_waiting_check,
+	DROP CONSTRAINT IF EXISTS seat_release_check,
+	DROP CONSTRAINT IF EXISTS seat_hide_title_check,
+	DROP CONSTRAINT IF EXISTS seat_transparent_background_check,
+	DROP CONSTRAINT IF EXISTS seat_hide_border_check,
+	DROP CONSTRAINT IF EXISTS seat_kiosk_disabled_check,
+	DROP CONSTRAINT IF EXISTS seat_power_control_check,
+	DROP CONSTRAINT IF EXISTS seat_breaker_number_check,
+	DROP CONSTRAINT IF EXISTS seat_size_check;
+DROP INDEX IF EXISTS seat_company_room_idx;
+DROP INDEX IF EXISTS seat_company_seat_code_idx;
+
+ALTER TABLE member_table DROP CONSTRAINT IF EXISTS member_gender_check;
+ALTER TABLE product_table DROP CONSTRAINT IF EXISTS product_on_sale_check;
+ALTER TABLE staff_table DROP CONSTRAINT IF EXISTS staff_enabled_check;
+ALTER TABLE kiosk_table DROP CONSTRAINT IF EXISTS kiosk_enabled_check;
+ALTER TABLE webhook_table DROP CONSTRAINT IF EXISTS webhook_enabled_check;
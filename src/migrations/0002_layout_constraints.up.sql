@@ -0,0 +1,40 @@
+-- 0002_layout_constraints: company_code별 room_code/seat_code 중복을 막고 플래그 컬럼 값을 제한합니다.
+-- 기존 데이터가 제약을 어기면 이 마이그레이션은 실패하며, 데이터를 정리한 뒤 다시 실행해야 합니다.
+
+CREATE UNIQUE INDEX IF NOT EXISTS room_company_room_code_idx ON room_table (company_code, room_code);
+ALTER TABLE room_table
+	ADD CONSTRAINT room_gender_check CHECK (gender IN (0, 1, 2)),
+	ADD CONSTRAINT room_waiting_check CHECK (waiting IN (0, 1)),
+	ADD CONSTRAINT room_release_check CHECK (release IN (0, 1)),
+	ADD CONSTRAINT room_hide_title_check CHECK (hide_title IN (0, 1)),
+	ADD CONSTRAINT room_transparent_background_check CHECK (transparent_background IN (0, 1)),
+	ADD CONSTRAINT room_hide_border_check CHECK (hide_border IN (0, 1)),
+	ADD CONSTRAINT room_kiosk_disabled_check CHECK (kiosk_disabled IN (0, 1)),
+	ADD CONSTRAINT room_power_control_check CHECK (power_control IN (0, 1)),
+	ADD CONSTRAINT room_breaker_number_check CHECK (breaker_number >= 0),
+	ADD CONSTRAINT room_size_check CHECK (room_width >= 0 AND room_height >= 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS seat_company_seat_code_idx ON seat_table (company_code, seat_code);
+CREATE INDEX IF NOT EXISTS seat_company_room_idx ON seat_table (company_code, room_code);
+ALTER TABLE seat_table
+	ADD CONSTRAINT seat_gender_check CHECK (gender IN (0, 1, 2)),
+	ADD CONSTRAINT seat_waiting_check CHECK (waiting IN (0, 1)),
+	ADD CONSTRAINT seat_release_check CHECK (release IN (0, 1)),
+	ADD CONSTRAINT seat_hide_title_check CHECK (hide_title IN (0, 1)),
+	ADD CONSTRAINT seat_transparent_background_check CHECK (transparent_background IN (0, 1)),
+	ADD CONSTRAINT seat_hide_border_check CHECK (hide_border IN (0, 1)),
+	ADD CONSTRAINT seat_kiosk_disabled_check CHECK (kiosk_disabled IN (0, 1)),
+	ADD CONSTRAINT seat_power_control_check CHECK (power_control IN (0, 1)),
+	ADD CONSTRAINT seat_breaker_number_check CHECK (breaker_number >= 0),
+	ADD CONSTRAINT seat_size_check CHECK (seat_width >= 0 AND seat_height >= 0);
+
+ALTER TABLE member_table
+	ADD CONSTRAINT member_gender_check CHECK (gender IN (0, 1, 2));
+ALTER TABLE product_table
+	ADD CONSTRAINT product_on_sale_check CHECK (on_sale IN (0, 1));
+ALTER TABLE staff_table
+	ADD CONSTRAINT staff_enabled_check CHECK (enabled IN (0, 1));
+ALTER TABLE kiosk_table
+	ADD CONSTRAINT kiosk_enabled_check CHECK (enabled IN (0, 1));
+ALTER TABLE webhook_table
+	ADD CONSTRAINT webhook_enabled_check CHECK (enabled IN (0, 1));
@@ -0,0 +1,4 @@
+-- 0003_row_version 되돌리기
+
+ALTER TABLE room_table DROP COLUMN IF EXISTS version;
+ALTER TABLE seat_table DROP COLUMN IF EXISTS version;
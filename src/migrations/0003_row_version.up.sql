@@ -0,0 +1,5 @@
+-- 0003_row_version: room/seat 수정 충돌을 막기 위한 행 버전 컬럼을 추가합니다.
+-- 수정할 때마다 1씩 증가하며, API는 auto_increment와 함께 ETag로 내보내고 If-Match로 확인합니다.
+
+ALTER TABLE room_table ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE seat_table ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+-- 0004_seat_room_fk 되돌리기
+
+ALTER TABLE seat_table DROP CONSTRAINT IF EXISTS seat_room_fk;
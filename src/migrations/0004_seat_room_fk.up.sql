@@ -0,0 +1,8 @@
+-- 0004_seat_room_fk: seat이 같은 회사의 room에 속하도록 외래 키를 추가합니다.
+-- 0002의 room_company_room_code_idx(company_code, room_code) 고유 인덱스를 참조합니다.
+-- seat이 남아 있는 room은 삭제할 수 없으며(cascade 삭제는 seat을 먼저 지웁니다), room_code는 변경할 수 없는 컬럼입니다.
+-- 기존 데이터에 room이 없는 seat이 있으면 이 마이그레이션은 실패하며, 데이터를 정리한 뒤 다시 실행해야 합니다.
+
+ALTER TABLE seat_table
+	ADD CONSTRAINT seat_room_fk FOREIGN KEY (company_code, room_code)
+	REFERENCES room_table (company_code, room_code);
@@ -0,0 +1,37 @@
+// controller.go
+package power
+
+import (
+	"context"
+	"errors"
+)
+
+// Controller는 차단기(breaker) 단위로 전원을 제어하는 드라이버 인터페이스입니다.
+// breaker 번호는 room_table/seat_table의 breaker_number 값이며 1부터 시작합니다.
+type Controller interface {
+	// On은 차단기를 켭니다.
+	On(ctx context.Context, breaker int) error
+	// Off는 차단기를 끕니다.
+	Off(ctx context.Context, breaker int) error
+	// Status는 차단기의 현재 상태(켜짐 여부)를 반환합니다.
+	Status(ctx context.Context, breaker int) (bool, error)
+}
+
+// ErrInvalidBreaker는 잘못된 차단기 번호일 때 반환됩니다.
+var ErrInvalidBreaker = errors.New("잘못된 breaker 번호입니다")
+
+// PowerController는 서버 전역에서 사용하는 전원 제어 드라이버입니다.
+var PowerController Controller
+
+// SetController는 전원 제어 드라이버를 설정합니다.
+func SetController(c Controller) {
+	PowerController = c
+}
+
+// Set은 on 값에 따라 차단기를 켜거나 끕니다.
+func Set(ctx context.Context, c Controller, breaker int, on bool) error {
+	if on {
+		return c.On(ctx, breaker)
+	}
+	return c.Off(ctx, breaker)
+}
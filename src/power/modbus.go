@@ -0,0 +1,146 @@
+// modbus.go
+package power
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Modbus 함수 코드
+const (
+	modbusReadCoils       byte = 0x01
+	modbusWriteSingleCoil byte = 0x05
+)
+
+// ModbusController는 Modbus/TCP 릴레이 장비를 제어하는 드라이버입니다.
+// breaker 번호 n은 코일 주소 n-1에 매핑됩니다.
+// 요청마다 새 연결을 맺으므로 로컬 Modbus 시뮬레이터로 대체해 시험할 수 있습니다.
+type ModbusController struct {
+	Addr    string
+	UnitID  byte
+	Timeout time.Duration
+
+	mu            sync.Mutex
+	transactionID uint16
+}
+
+// NewModbusController는 addr(host:port)의 장비를 제어하는 ModbusController를 생성합니다.
+func NewModbusController(addr string, unitID byte) *ModbusController {
+	return &ModbusController{Addr: addr, UnitID: unitID, Timeout: 3 * time.Second}
+}
+
+// On은 차단기를 켭니다. (Write Single Coil, 0xFF00)
+func (c *ModbusController) On(ctx context.Context, breaker int) error {
+	return c.writeCoil(ctx, breaker, true)
+}
+
+// Off는 차단기를 끕니다. (Write Single Coil, 0x0000)
+func (c *ModbusController) Off(ctx context.Context, breaker int) error {
+	return c.writeCoil(ctx, breaker, false)
+}
+
+// Status는 차단기의 현재 상태를 읽습니다. (Read Coils, 1개)
+func (c *ModbusController) Status(ctx context.Context, breaker int) (bool, error) {
+	if breaker <= 0 || breaker > 0x10000 {
+		return false, ErrInvalidBreaker
+	}
+	pdu := make([]byte, 5)
+	pdu[0] = modbusReadCoils
+	binary.BigEndian.PutUint16(pdu[1:3], uint16(breaker-1))
+	binary.BigEndian.PutUint16(pdu[3:5], 1)
+
+	resp, err := c.do(ctx, pdu)
+	if err != nil {
+		return false, err
+	}
+	if len(resp) < 3 || resp[1] < 1 {
+		return false, fmt.Errorf("modbus: 잘못된 Read Coils 응답 길이 %d", len(resp))
+	}
+	return resp[2]&0x01 == 0x01, nil
+}
+
+func (c *ModbusController) writeCoil(ctx context.Context, breaker int, on bool) error {
+	if breaker <= 0 || breaker > 0x10000 {
+		return ErrInvalidBreaker
+	}
+	pdu := make([]byte, 5)
+	pdu[0] = modbusWriteSingleCoil
+	binary.BigEndian.PutUint16(pdu[1:3], uint16(breaker-1))
+	if on {
+		binary.BigEndian.PutUint16(pdu[3:5], 0xFF00)
+	}
+
+	resp, err := c.do(ctx, pdu)
+	if err != nil {
+		return err
+	}
+	// 정상 응답은 요청 PDU를 그대로 돌려줍니다.
+	if len(resp) != len(pdu) || string(resp) != string(pdu) {
+		return fmt.Errorf("modbus: Write Single Coil 응답이 요청과 다릅니다")
+	}
+	return nil
+}
+
+// do는 PDU를 MBAP 헤더로 감싸 전송하고 응답 PDU를 반환합니다.
+func (c *ModbusController) do(ctx context.Context, pdu []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: c.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("modbus: 연결 실패: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	c.transactionID++
+	tid := c.transactionID
+
+	// MBAP 헤더: transaction id(2) + protocol id(2, 항상 0) + length(2) + unit id(1)
+	frame := make([]byte, 7+len(pdu))
+	binary.BigEndian.PutUint16(frame[0:2], tid)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(len(pdu)+1))
+	frame[6] = c.UnitID
+	copy(frame[7:], pdu)
+	if _, err := conn.Write(frame); err != nil {
+		return nil, fmt.Errorf("modbus: 전송 실패: %w", err)
+	}
+
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, fmt.Errorf("modbus: 응답 헤더 수신 실패: %w", err)
+	}
+	if binary.BigEndian.Uint16(header[0:2]) != tid {
+		return nil, fmt.Errorf("modbus: transaction id 불일치")
+	}
+	length := int(binary.BigEndian.Uint16(header[4:6]))
+	if length < 2 || length > 254 {
+		return nil, fmt.Errorf("modbus: 잘못된 응답 길이 %d", length)
+	}
+	resp := make([]byte, length-1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, fmt.Errorf("modbus: 응답 수신 실패: %w", err)
+	}
+
+	if resp[0] == pdu[0]|0x80 {
+		if len(resp) < 2 {
+			return nil, fmt.Errorf("modbus: 장비 예외 응답")
+		}
+		return nil, fmt.Errorf("modbus: 장비 예외 코드 0x%02X", resp[1])
+	}
+	if resp[0] != pdu[0] {
+		return nil, fmt.Errorf("modbus: 예상치 못한 함수 코드 0x%02X", resp[0])
+	}
+	return resp, nil
+}
@@ -0,0 +1,156 @@
+package power
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// modbusHandler는 요청 MBAP 헤더의 transaction id/unit id와 PDU로 응답 프레임 전체를 만듭니다.
+type modbusHandler func(tid uint16, unitID byte, pdu []byte) []byte
+
+// newFakeModbusServer는 연결마다 요청 하나를 읽고 handle의 응답을 돌려주는 Modbus/TCP 서버를 띄우고 주소를 반환합니다.
+func newFakeModbusServer(t *testing.T, handle modbusHandler) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				header := make([]byte, 7)
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				if binary.BigEndian.Uint16(header[2:4]) != 0 {
+					return
+				}
+				pdu := make([]byte, int(binary.BigEndian.Uint16(header[4:6]))-1)
+				if _, err := io.ReadFull(conn, pdu); err != nil {
+					return
+				}
+				conn.Write(handle(binary.BigEndian.Uint16(header[0:2]), header[6], pdu))
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// modbusFrame은 PDU를 MBAP 헤더로 감쌉니다.
+func modbusFrame(tid uint16, unitID byte, pdu []byte) []byte {
+	frame := make([]byte, 7+len(pdu))
+	binary.BigEndian.PutUint16(frame[0:2], tid)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(len(pdu)+1))
+	frame[6] = unitID
+	copy(frame[7:], pdu)
+	return frame
+}
+
+// fakeCoils는 Write Single Coil/Read Coils를 처리하는 릴레이 장비입니다.
+type fakeCoils struct {
+	mu     sync.Mutex
+	coils  map[uint16]bool
+	unitID byte
+}
+
+func (d *fakeCoils) handle(tid uint16, unitID byte, pdu []byte) []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unitID = unitID
+
+	addr := binary.BigEndian.Uint16(pdu[1:3])
+	switch pdu[0] {
+	case modbusWriteSingleCoil:
+		d.coils[addr] = binary.BigEndian.Uint16(pdu[3:5]) == 0xFF00
+		return modbusFrame(tid, unitID, pdu)
+	case modbusReadCoils:
+		var status byte
+		if d.coils[addr] {
+			status = 0x01
+		}
+		return modbusFrame(tid, unitID, []byte{modbusReadCoils, 1, status})
+	}
+	return modbusFrame(tid, unitID, []byte{pdu[0] | 0x80, 0x01})
+}
+
+func TestModbusWriteAndReadCoil(t *testing.T) {
+	device := &fakeCoils{coils: make(map[uint16]bool)}
+	c := NewModbusController(newFakeModbusServer(t, device.handle), 7)
+	ctx := context.Background()
+
+	if err := c.On(ctx, 3); err != nil {
+		t.Fatalf("On: %v", err)
+	}
+	if !device.coils[2] {
+		t.Fatal("breaker 3이 코일 주소 2에 켜지지 않았습니다")
+	}
+	if device.unitID != 7 {
+		t.Fatalf("unit id = %d, want 7", device.unitID)
+	}
+	on, err := c.Status(ctx, 3)
+	if err != nil || !on {
+		t.Fatalf("Status = %v, %v, want true", on, err)
+	}
+
+	if err := c.Off(ctx, 3); err != nil {
+		t.Fatalf("Off: %v", err)
+	}
+	on, err = c.Status(ctx, 3)
+	if err != nil || on {
+		t.Fatalf("Status = %v, %v, want false", on, err)
+	}
+}
+
+func TestModbusErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handle  modbusHandler
+		wantErr string
+	}{
+		{"예외 응답", func(tid uint16, unitID byte, pdu []byte) []byte {
+			return modbusFrame(tid, unitID, []byte{pdu[0] | 0x80, 0x02})
+		}, "예외 코드 0x02"},
+		{"transaction id 불일치", func(tid uint16, unitID byte, pdu []byte) []byte {
+			return modbusFrame(tid+1, unitID, pdu)
+		}, "transaction id 불일치"},
+		{"요청과 다른 Write Single Coil 응답", func(tid uint16, unitID byte, pdu []byte) []byte {
+			echo := append([]byte(nil), pdu...)
+			echo[3], echo[4] = 0, 0
+			return modbusFrame(tid, unitID, echo)
+		}, "응답이 요청과 다릅니다"},
+		{"다른 함수 코드", func(tid uint16, unitID byte, pdu []byte) []byte {
+			return modbusFrame(tid, unitID, []byte{modbusReadCoils, 1, 0})
+		}, "예상치 못한 함수 코드"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewModbusController(newFakeModbusServer(t, tt.handle), 1)
+			err := c.On(context.Background(), 1)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModbusInvalidBreaker(t *testing.T) {
+	c := NewModbusController("127.0.0.1:1", 1)
+	if err := c.On(context.Background(), 0); err != ErrInvalidBreaker {
+		t.Fatalf("On(0) = %v, want %v", err, ErrInvalidBreaker)
+	}
+	if _, err := c.Status(context.Background(), 0x10001); err != ErrInvalidBreaker {
+		t.Fatalf("Status(0x10001) = %v, want %v", err, ErrInvalidBreaker)
+	}
+}
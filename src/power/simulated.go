@@ -0,0 +1,51 @@
+// simulated.go
+package power
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// SimulatedController는 실제 장비 없이 메모리에 차단기 상태를 보관하는 드라이버입니다.
+// 개발 환경이나 장비가 없는 지점에서 사용합니다.
+type SimulatedController struct {
+	mu     sync.Mutex
+	states map[int]bool
+}
+
+// NewSimulatedController는 모든 차단기가 꺼진 상태의 SimulatedController를 생성합니다.
+func NewSimulatedController() *SimulatedController {
+	return &SimulatedController{states: make(map[int]bool)}
+}
+
+// On은 차단기를 켭니다.
+func (c *SimulatedController) On(ctx context.Context, breaker int) error {
+	return c.set(breaker, true)
+}
+
+// Off는 차단기를 끕니다.
+func (c *SimulatedController) Off(ctx context.Context, breaker int) error {
+	return c.set(breaker, false)
+}
+
+// Status는 차단기의 현재 상태를 반환합니다.
+func (c *SimulatedController) Status(ctx context.Context, breaker int) (bool, error) {
+	if breaker <= 0 {
+		return false, ErrInvalidBreaker
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.states[breaker], nil
+}
+
+func (c *SimulatedController) set(breaker int, on bool) error {
+	if breaker <= 0 {
+		return ErrInvalidBreaker
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.states[breaker] = on
+	log.Printf("[전원 시뮬레이터] breaker %d → %v", breaker, on)
+	return nil
+}
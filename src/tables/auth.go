@@ -0,0 +1,296 @@
+// auth.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// 라우트별 허용 역할 목록
+var (
+	ownerOnly   = []string{consts.ROLE_OWNER}
+	staffRoles  = []string{consts.ROLE_OWNER, consts.ROLE_STAFF}
+	memberRoles = []string{consts.ROLE_OWNER, consts.ROLE_STAFF, consts.ROLE_MEMBER}
+	anyRole     = []string{consts.ROLE_OWNER, consts.ROLE_STAFF, consts.ROLE_KIOSK, consts.ROLE_MEMBER}
+)
+
+// allow는 핸들러를 지정한 역할의 호출자만 사용할 수 있도록 감쌉니다.
+func allow(h http.HandlerFunc, roles ...string) http.Handler {
+	return utils.RequireRole(roles...)(h)
+}
+
+// RegisterAuthRoutes는 로그인/토큰 갱신 엔드포인트를 등록합니다.
+// 이 경로들은 인증 없이 호출되므로 TenantMiddleware 밖의 라우터에 등록해야 합니다.
+func RegisterAuthRoutes(r *mux.Router) {
+	r.HandleFunc("/auth/login", StaffLogin).Methods("POST")
+	r.HandleFunc("/auth/kiosk/login", KioskLogin).Methods("POST")
+	r.HandleFunc("/auth/member/login", MemberLogin).Methods("POST")
+	r.HandleFunc("/auth/refresh", RefreshToken).Methods("POST")
+}
+
+// tokenResponse는 로그인/토큰 갱신 응답 본문입니다.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Role         string `json:"role"`
+	CompanyCode  int    `json:"company_code"`
+}
+
+// writeTokens는 claims로 액세스/리프레시 토큰을 발급하여 응답합니다.
+func writeTokens(w http.ResponseWriter, claims utils.Claims) {
+	accessTTL := time.Duration(consts.ACCESS_TOKEN_TTL_MINUTES) * time.Minute
+	refreshTTL := time.Duration(consts.REFRESH_TOKEN_TTL_HOURS) * time.Hour
+
+	claims.TokenType = consts.TOKEN_TYPE_ACCESS
+	access, _, err := utils.IssueToken(claims, accessTTL)
+	if err != nil {
+		log.Printf("토큰 발급 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "토큰 발급 중 오류가 발생했습니다")
+		return
+	}
+	claims.TokenType = consts.TOKEN_TYPE_REFRESH
+	refresh, _, err := utils.IssueToken(claims, refreshTTL)
+	if err != nil {
+		log.Printf("토큰 발급 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "토큰 발급 중 오류가 발생했습니다")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokenResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTTL.Seconds()),
+		Role:         claims.Role,
+		CompanyCode:  claims.CompanyCode,
+	})
+}
+
+// loginFailed는 로그인 실패를 401 problem 문서로 응답합니다.
+// 계정 존재 여부가 드러나지 않도록 실패 사유는 구분하지 않습니다.
+func loginFailed(w http.ResponseWriter) {
+	writeProblem(w, consts.ERR_UNAUTHENTICATED, "로그인 정보가 올바르지 않습니다.")
+}
+
+// staffLoginRequest는 점주/직원 로그인 요청 본문입니다.
+type staffLoginRequest struct {
+	CompanyCode int    `json:"company_code"`
+	LoginID     string `json:"login_id"`
+	Password    string `json:"password"`
+}
+
+// StaffLogin: 점주/직원 계정으로 로그인하여 토큰을 발급합니다.
+func StaffLogin(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var req staffLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+
+	staff, err := authenticateStaff(ctx, req.CompanyCode, req.LoginID, req.Password)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			loginFailed(w)
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	writeTokens(w, utils.Claims{
+		Subject:     strconv.Itoa(staff.StaffID),
+		Role:        staff.Role,
+		CompanyCode: staff.CompanyCode,
+	})
+}
+
+// kioskLoginRequest는 키오스크 로그인 요청 본문입니다.
+type kioskLoginRequest struct {
+	KioskID  int    `json:"kiosk_id"`
+	KioskKey string `json:"kiosk_key"`
+}
+
+// KioskLogin: 키오스크 장비 인증 키로 로그인하여 토큰을 발급합니다.
+func KioskLogin(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var req kioskLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+
+	kiosk, err := authenticateKiosk(ctx, req.KioskID, req.KioskKey)
+	if err != nil {
+		if err == errKioskAuthFailed {
+			loginFailed(w)
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	writeTokens(w, utils.Claims{
+		Subject:     strconv.Itoa(kiosk.KioskID),
+		Role:        consts.ROLE_KIOSK,
+		CompanyCode: kiosk.CompanyCode,
+	})
+}
+
+// memberLoginRequest는 회원 로그인 요청 본문입니다.
+type memberLoginRequest struct {
+	CompanyCode int    `json:"company_code"`
+	Phone       string `json:"phone"`
+	Password    string `json:"password"`
+}
+
+// MemberLogin: 회원 전화번호/비밀번호로 로그인하여 토큰을 발급합니다.
+// 비밀번호가 설정되지 않았거나 정상 이용 중이 아닌 회원은 로그인할 수 없습니다.
+func MemberLogin(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var req memberLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	memberID, err := authenticateMember(ctx, req.CompanyCode, req.Phone, req.Password)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			loginFailed(w)
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	writeTokens(w, utils.Claims{
+		Subject:     strconv.Itoa(memberID),
+		Role:        consts.ROLE_MEMBER,
+		CompanyCode: req.CompanyCode,
+	})
+}
+
+// refreshTokenRequest는 토큰 갱신 요청 본문입니다.
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshToken: 리프레시 토큰으로 새 액세스/리프레시 토큰을 발급합니다.
+// 그 사이 계정이 비활성화/삭제되었으면 갱신을 거부합니다.
+func RefreshToken(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var req refreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	claims, err := utils.ParseToken(req.RefreshToken)
+	if err != nil || claims.TokenType != consts.TOKEN_TYPE_REFRESH {
+		writeProblem(w, consts.ERR_UNAUTHENTICATED, "유효하지 않은 리프레시 토큰입니다.")
+		return
+	}
+
+	role, err := currentRole(ctx, claims)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_UNAUTHENTICATED, "더 이상 사용할 수 없는 계정입니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	claims.Role = role
+	writeTokens(w, claims)
+}
+
+// currentRole은 토큰 주체가 아직 유효한지 확인하고 현재 역할을 반환합니다.
+// 점주/직원은 그 사이 역할이 바뀌었을 수 있으므로 DB의 역할을 사용합니다.
+// 더 이상 로그인할 수 없는 주체이면 sql.ErrNoRows를 반환합니다.
+func currentRole(ctx context.Context, claims utils.Claims) (string, error) {
+	id := claims.SubjectID()
+	switch claims.Role {
+	case consts.ROLE_OWNER, consts.ROLE_STAFF:
+		var role string
+		err := utils.DB.QueryRowContext(ctx, `
+			SELECT role FROM staff_table
+			WHERE staff_id = $1 AND company_code = $2 AND enabled <> 0`,
+			id, claims.CompanyCode).Scan(&role)
+		return role, err
+	case consts.ROLE_KIOSK:
+		var exists bool
+		err := utils.DB.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM kiosk_table
+			WHERE kiosk_id = $1 AND company_code = $2 AND enabled <> 0)`,
+			id, claims.CompanyCode).Scan(&exists)
+		if err == nil && !exists {
+			err = sql.ErrNoRows
+		}
+		return claims.Role, err
+	case consts.ROLE_MEMBER:
+		var exists bool
+		err := utils.DB.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM member_table
+			WHERE member_id = $1 AND company_code = $2 AND status = $3 AND password_hash <> '')`,
+			id, claims.CompanyCode, consts.MEMBER_STATUS_ACTIVE).Scan(&exists)
+		if err == nil && !exists {
+			err = sql.ErrNoRows
+		}
+		return claims.Role, err
+	}
+	return "", sql.ErrNoRows
+}
+
+// callerMemberID는 호출자가 회원이면 회원 본인의 member_id를 반환합니다.
+// 키오스크 호출이면 prepareKioskRequest가 전화번호/비밀번호로 확인한 회원의 member_id를 반환합니다.
+func callerMemberID(r *http.Request) (int, bool) {
+	claims, ok := utils.ClaimsFromContext(r.Context())
+	if !ok {
+		return 0, false
+	}
+	switch claims.Role {
+	case consts.ROLE_MEMBER:
+		return claims.SubjectID(), true
+	case consts.ROLE_KIOSK:
+		return kioskMemberFromContext(r.Context())
+	}
+	return 0, false
+}
+
+// bindCallerMember는 회원 호출자의 요청을 본인 member_id로 고정합니다.
+// 다른 회원의 member_id를 지정했으면 403을 응답하고 false를 반환합니다.
+// 회원이 아닌 호출자의 요청은 그대로 둡니다.
+func bindCallerMember(w http.ResponseWriter, r *http.Request, memberID *int) bool {
+	self, ok := callerMemberID(r)
+	if !ok {
+		return true
+	}
+	if *memberID != 0 && *memberID != self {
+		writeProblem(w, consts.ERR_FORBIDDEN, "본인의 정보만 다룰 수 있습니다.")
+		return false
+	}
+	*memberID = self
+	return true
+}
@@ -0,0 +1,197 @@
+// breaker.go
+package tables
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/power"
+	"AllinB/src/utils"
+)
+
+// BreakerState는 차단기 하나의 실시간 상태와 연결된 seat/room 목록입니다.
+type BreakerState struct {
+	BreakerNumber int    `json:"breaker_number"`
+	On            *bool  `json:"on"`
+	Error         string `json:"error,omitempty"`
+	Occupied      bool   `json:"occupied"`
+	Seats         []int  `json:"seats"`
+	Rooms         []int  `json:"rooms"`
+}
+
+// RegisterBreakerRoutes는 전원 차단기 관련 엔드포인트를 등록합니다. (점주/직원 전용)
+func RegisterBreakerRoutes(r *mux.Router) {
+	r.Handle("/breakers", allow(GetBreakers, staffRoles...)).Methods("GET")
+}
+
+// RegisterPowerJobs는 전원 제어 관련 비동기 작업 처리 함수를 등록합니다.
+func RegisterPowerJobs() {
+	utils.RegisterJobPayloadHandler("PowerSync", syncSeatPower)
+}
+
+// powerSyncPayload는 PowerSync 작업의 데이터입니다.
+type powerSyncPayload struct {
+	CompanyCode int `json:"company_code"`
+	SeatCode    int `json:"seat_code"`
+}
+
+// GetBreakers: power_control이 켜진 seat/room의 차단기별 실시간 상태를 조회합니다.
+// 호출자의 company_code에 속한 seat/room만 조회합니다.
+func GetBreakers(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	companyCode := utils.CompanyCode(r.Context())
+
+	states := make(map[int]*BreakerState)
+	stateOf := func(n int) *BreakerState {
+		if st, ok := states[n]; ok {
+			return st
+		}
+		st := &BreakerState{BreakerNumber: n, Seats: []int{}, Rooms: []int{}}
+		states[n] = st
+		return st
+	}
+
+	for _, src := range []struct{ table, codeColumn string }{
+		{"seat_table", "seat_code"},
+		{"room_table", "room_code"},
+	} {
+		query := fmt.Sprintf(`SELECT breaker_number, %s FROM %s
+			WHERE company_code = $1 AND power_control <> 0 AND breaker_number > 0
+			ORDER BY %s`, src.codeColumn, src.table, src.codeColumn)
+		rows, err := utils.DB.QueryContext(ctx, query, companyCode)
+		if err != nil {
+			log.Printf("데이터베이스 쿼리 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+			return
+		}
+		for rows.Next() {
+			var breaker, code int
+			if err := rows.Scan(&breaker, &code); err != nil {
+				rows.Close()
+				writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+				return
+			}
+			st := stateOf(breaker)
+			if src.table == "seat_table" {
+				st.Seats = append(st.Seats, code)
+			} else {
+				st.Rooms = append(st.Rooms, code)
+			}
+		}
+		rows.Close()
+	}
+
+	result := make([]BreakerState, 0, len(states))
+	for _, st := range states {
+		occupied, err := breakerOccupied(ctx, companyCode, st.BreakerNumber)
+		if err != nil {
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+			return
+		}
+		st.Occupied = occupied
+		if power.PowerController != nil {
+			on, err := power.PowerController.Status(ctx, st.BreakerNumber)
+			if err != nil {
+				st.Error = err.Error()
+			} else {
+				st.On = &on
+			}
+		}
+		result = append(result, *st)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].BreakerNumber < result[j].BreakerNumber })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// enqueuePowerSync는 좌석 사용 상태에 맞춰 차단기를 동기화하는 작업을 큐에 넣습니다.
+func enqueuePowerSync(companyCode, seatCode int) {
+	job := utils.Job{
+		Name: "PowerSync",
+		Data: map[string]interface{}{
+			"company_code": companyCode,
+			"seat_code":    seatCode,
+			"time":         time.Now(),
+		},
+		// 좌석 전원은 사용자가 바로 체감하므로 다른 작업보다 먼저 처리합니다.
+		Priority: consts.JOB_PRIORITY_HIGH,
+	}
+	if utils.EnqueueJobHandler != nil {
+		utils.EnqueueJobHandler(job)
+	}
+}
+
+// breakerOccupied는 같은 회사에서 차단기를 공유하는 seat(또는 room 안의 seat) 중 사용 중인 것이 있는지 확인합니다.
+func breakerOccupied(ctx context.Context, companyCode, breaker int) (bool, error) {
+	var occupied bool
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM seat_session_table ss
+			JOIN seat_table s ON s.seat_code = ss.seat_code AND s.company_code = ss.company_code
+			LEFT JOIN room_table r ON r.room_code = s.room_code AND r.company_code = s.company_code
+			WHERE ss.company_code = $1 AND ss.end_time IS NULL
+			  AND ((s.power_control <> 0 AND s.breaker_number = $2)
+			    OR (r.power_control <> 0 AND r.breaker_number = $2)))`,
+		companyCode, breaker).Scan(&occupied)
+	return occupied, err
+}
+
+// syncSeatPower는 seat과 seat이 속한 room의 차단기를 사용 상태에 맞춰 켜거나 끕니다.
+// 여러 seat이 차단기를 공유할 수 있으므로, 공유 seat 중 하나라도 사용 중이면 켠 상태를 유지합니다.
+// PowerSync 작업으로 실행됩니다.
+func syncSeatPower(ctx context.Context, payload powerSyncPayload) error {
+	if power.PowerController == nil {
+		return nil
+	}
+	companyCode, seatCode := payload.CompanyCode, payload.SeatCode
+	if companyCode <= 0 {
+		return fmt.Errorf("company_code가 없는 PowerSync 작업")
+	}
+	if seatCode <= 0 {
+		return fmt.Errorf("seat_code가 없는 PowerSync 작업")
+	}
+
+	var seatPower, seatBreaker, roomPower, roomBreaker int
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT s.power_control, s.breaker_number,
+		       COALESCE(r.power_control, 0), COALESCE(r.breaker_number, 0)
+		FROM seat_table s
+		LEFT JOIN room_table r ON r.room_code = s.room_code AND r.company_code = s.company_code
+		WHERE s.company_code = $1 AND s.seat_code = $2`, companyCode, seatCode).
+		Scan(&seatPower, &seatBreaker, &roomPower, &roomBreaker)
+	if err != nil {
+		return err
+	}
+
+	breakers := []int{}
+	if seatPower != 0 && seatBreaker > 0 {
+		breakers = append(breakers, seatBreaker)
+	}
+	if roomPower != 0 && roomBreaker > 0 && roomBreaker != seatBreaker {
+		breakers = append(breakers, roomBreaker)
+	}
+
+	for _, breaker := range breakers {
+		on, err := breakerOccupied(ctx, companyCode, breaker)
+		if err != nil {
+			return err
+		}
+		if err := power.Set(ctx, power.PowerController, breaker, on); err != nil {
+			return fmt.Errorf("breaker %d 제어 실패: %w", breaker, err)
+		}
+		log.Printf("차단기 동기화: breaker=%d, on=%v (company_code=%d, seat_code=%d)", breaker, on, companyCode, seatCode)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// etag.go
+package tables
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"AllinB/src/consts"
+)
+
+// ETag는 버전을 강한 ETag 문자열("<auto_increment>-<version>")로 만듭니다.
+func (v RowVersion) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, v.AutoIncrement, v.Version)
+}
+
+// parseETag는 ETag 문자열을 RowVersion으로 해석합니다. 약한 ETag(W/)는 받지 않습니다.
+func parseETag(tag string) (RowVersion, bool) {
+	var v RowVersion
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return v, false
+	}
+	if n, err := fmt.Sscanf(tag[1:len(tag)-1], "%d-%d", &v.AutoIncrement, &v.Version); n != 2 || err != nil {
+		return v, false
+	}
+	if v.ETag() != tag {
+		return v, false
+	}
+	return v, true
+}
+
+// setETag는 응답에 행 버전을 ETag 헤더로 넣습니다.
+func setETag(w http.ResponseWriter, v RowVersion) {
+	w.Header().Set("ETag", v.ETag())
+}
+
+// etagCondition은 If-Match 헤더의 조건입니다.
+// any이면("*") 행이 있기만 하면 되고, 그 외에는 versions 중 하나와 같아야 합니다.
+type etagCondition struct {
+	any      bool
+	versions []RowVersion
+}
+
+// matches는 현재 버전이 조건을 만족하는지 확인합니다. 조건이 nil이면 항상 만족합니다.
+func (c *etagCondition) matches(current RowVersion) bool {
+	if c == nil || c.any {
+		return true
+	}
+	for _, v := range c.versions {
+		if v == current {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch는 If-Match 헤더를 읽습니다. 헤더가 없으면 버전을 확인하지 않으므로 nil을 반환합니다.
+// 쉼표로 구분한 ETag 목록 중 하나와 같으면 일치하며, "*"는 행이 있으면 일치합니다.
+// 이 서버가 만든 ETag가 하나도 없으면 어떤 행과도 일치할 수 없으므로 ok가 false이며, 412로 응답해야 합니다.
+func ifMatch(r *http.Request) (c *etagCondition, ok bool) {
+	header := strings.TrimSpace(strings.Join(r.Header.Values("If-Match"), ","))
+	if header == "" {
+		return nil, true
+	}
+	c = &etagCondition{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			c.any = true
+		} else if v, ok := parseETag(tag); ok {
+			c.versions = append(c.versions, v)
+		}
+	}
+	if !c.any && len(c.versions) == 0 {
+		return nil, false
+	}
+	return c, true
+}
+
+// notModified는 If-None-Match 헤더가 현재 ETag와 일치하면 304로 응답하고 true를 반환합니다.
+// 키오스크가 바뀐 경우에만 본문을 받도록 폴링할 때 사용합니다. 약한 비교(W/ 무시)를 사용합니다.
+func notModified(w http.ResponseWriter, r *http.Request, v RowVersion) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	current := v.ETag()
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// writePreconditionFailed는 If-Match 버전이 맞지 않을 때 412로 응답합니다.
+func writePreconditionFailed(w http.ResponseWriter) {
+	writeProblem(w, consts.ERR_PRECONDITION_FAILED, "다른 요청이 먼저 변경했습니다. 최신 데이터를 다시 조회한 뒤 요청하세요.")
+}
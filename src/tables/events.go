@@ -0,0 +1,276 @@
+// events.go
+package tables
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// 상태 변경 알림 작업 이름 목록
+// 핸들러가 변경 후 큐에 넣으며, 등록된 처리 함수가 없으면 작업이 실패로 처리되므로
+// 새 알림 작업을 추가할 때는 이 목록에도 추가해야 합니다.
+var eventJobNames = []string{
+	"RoomCreated",
+	"RoomUpdated",
+	"RoomDeleted",
+	"SeatCreated",
+	"SeatUpdated",
+	"SeatDeleted",
+	"SeatCheckedIn",
+	"SeatCheckedOut",
+	"SeatSessionExtended",
+	"ReservationReleased",
+	"WaitlistPromoted",
+	"WaitlistHoldExpired",
+}
+
+// eventResetType은 요청한 last-event-id 이후 이벤트를 모두 보낼 수 없을 때 보내는 이벤트입니다.
+// 이 이벤트를 받은 클라이언트는 GET /rooms, GET /seats로 전체 상태를 다시 조회해야 합니다.
+const eventResetType = "Reset"
+
+// RegisterEventJobs는 상태 변경 알림 작업의 처리 함수를 등록합니다.
+func RegisterEventJobs() {
+	for _, name := range eventJobNames {
+		utils.RegisterJobHandler(name, publishEvent)
+	}
+}
+
+// RegisterEventRoutes는 실시간 이벤트 스트림 엔드포인트를 등록합니다. (점주/직원/키오스크)
+func RegisterEventRoutes(r *mux.Router) {
+	r.Handle("/events", allow(StreamEvents, consts.ROLE_OWNER, consts.ROLE_STAFF, consts.ROLE_KIOSK)).Methods("GET")
+}
+
+// enqueueEvent는 상태 변경 알림 작업을 큐에 넣습니다.
+// seat 이벤트는 구독자의 room 필터에 쓰이도록 seat이 속한 room_code를 data에 담아야 합니다.
+func enqueueEvent(name string, data map[string]interface{}) {
+	data["time"] = time.Now()
+	if utils.EnqueueJobHandler != nil {
+		utils.EnqueueJobHandler(utils.Job{Name: name, Data: data})
+	}
+}
+
+// publishEvent는 상태 변경 알림 작업을 구독 중인 webhook마다 전송 작업으로 나누어 큐에 넣고,
+// 이벤트 스트림 구독자에게 전달합니다.
+// room 필터에 사용하는 room_code는 작업을 넣을 때 변경 시점의 값으로 담습니다. (enqueueEvent 참고)
+func publishEvent(ctx context.Context, job utils.Job) error {
+	companyCode, ok := job.Int("company_code")
+	if !ok {
+		return fmt.Errorf("company_code가 없는 %s 작업", job.Name)
+	}
+	roomCode, _ := job.Int("room_code")
+	seatCode, _ := job.Int("seat_code")
+
+	// webhook 조회가 실패하면 작업이 재시도되므로, 스트림 전달은 그 뒤에 하여 중복 전달을 피합니다.
+	now := time.Now()
+	err := enqueueWebhookDeliveries(ctx, webhookEventBody{
+		Type:        job.Name,
+		CompanyCode: companyCode,
+		RoomCode:    roomCode,
+		SeatCode:    seatCode,
+		Data:        job.Data,
+		Time:        now,
+	})
+	if err != nil {
+		return err
+	}
+
+	ev := utils.Events.Publish(utils.Event{
+		Type:        job.Name,
+		CompanyCode: companyCode,
+		RoomCode:    roomCode,
+		SeatCode:    seatCode,
+		Data:        job.Data,
+		Time:        now,
+	})
+	log.Printf("이벤트 %s: id=%d, company_code=%d, room_code=%d, seat_code=%d",
+		ev.Type, ev.ID, companyCode, roomCode, seatCode)
+	return nil
+}
+
+// StreamEvents: room/seat 생성·수정·삭제와 좌석 사용 상태 변경을 실시간으로 전달합니다.
+// 기본은 SSE(text/event-stream)이며, WebSocket 업그레이드 요청이면 WebSocket으로 JSON 메시지를 보냅니다.
+//
+// 쿼리 파라미터:
+//   - company_code: 호출자의 company_code와 같아야 합니다. (생략 가능)
+//   - room_code: 지정한 room의 이벤트만 받습니다. 여러 번 지정하거나 쉼표로 구분할 수 있습니다.
+//   - last_event_id: 재연결 시 마지막으로 받은 이벤트 ID입니다. SSE는 Last-Event-ID 헤더도 사용합니다.
+func StreamEvents(w http.ResponseWriter, r *http.Request) {
+	companyCode := utils.CompanyCode(r.Context())
+	q := r.URL.Query()
+
+	if v := q.Get("company_code"); v != "" {
+		requested, err := strconv.Atoi(v)
+		if err != nil {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 company_code")
+			return
+		}
+		if requested != companyCode {
+			writeProblem(w, consts.ERR_FORBIDDEN, "다른 company_code의 이벤트는 구독할 수 없습니다.")
+			return
+		}
+	}
+
+	rooms := make(map[int]bool)
+	for _, v := range q["room_code"] {
+		for _, part := range strings.Split(v, ",") {
+			roomCode, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+				return
+			}
+			rooms[roomCode] = true
+		}
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if v := q.Get("last_event_id"); v != "" {
+		lastID = v
+	}
+	var lastEventID int64
+	if lastID != "" {
+		var err error
+		if lastEventID, err = strconv.ParseInt(lastID, 10, 64); err != nil || lastEventID < 0 {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 last_event_id")
+			return
+		}
+	}
+
+	filter := func(ev utils.Event) bool {
+		if ev.CompanyCode != companyCode {
+			return false
+		}
+		return len(rooms) == 0 || rooms[ev.RoomCode]
+	}
+
+	if utils.IsWebSocketUpgrade(r) {
+		streamEventsWebSocket(w, r, lastEventID, filter)
+		return
+	}
+	streamEventsSSE(w, r, lastEventID, filter)
+}
+
+// streamEventsSSE는 이벤트를 Server-Sent Events 형식으로 보냅니다.
+func streamEventsSSE(w http.ResponseWriter, r *http.Request, lastEventID int64, filter utils.EventFilter) {
+	rc := http.NewResponseController(w)
+
+	sub, backlog, complete := utils.Events.Subscribe(lastEventID, filter)
+	defer utils.Events.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// 프록시(nginx 등)의 응답 버퍼링을 끕니다.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", consts.EVENT_RETRY_MILLIS)
+	if !complete {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventResetType)
+	}
+	for _, ev := range backlog {
+		if err := writeSSEEvent(w, ev); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		log.Printf("SSE flush 실패: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(time.Duration(consts.EVENT_HEARTBEAT_INTERVAL) * time.Second)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				// 전송이 밀려 구독이 끊겼습니다. 클라이언트는 Last-Event-ID로 다시 연결합니다.
+				return
+			}
+			if err := writeSSEEvent(w, ev); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSEEvent는 이벤트 하나를 id/event/data 필드로 씁니다.
+func writeSSEEvent(w http.ResponseWriter, ev utils.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+	return err
+}
+
+// streamEventsWebSocket은 이벤트를 WebSocket 텍스트 메시지(JSON)로 보냅니다.
+func streamEventsWebSocket(w http.ResponseWriter, r *http.Request, lastEventID int64, filter utils.EventFilter) {
+	ws, err := utils.UpgradeWebSocket(w, r)
+	if err != nil {
+		log.Printf("WebSocket 연결 실패: %v", err)
+		return
+	}
+	defer ws.Close()
+
+	sub, backlog, complete := utils.Events.Subscribe(lastEventID, filter)
+	defer utils.Events.Unsubscribe(sub)
+
+	send := func(ev utils.Event) error {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		return ws.WriteText(data)
+	}
+
+	if !complete {
+		if err := send(utils.Event{Type: eventResetType, CompanyCode: utils.CompanyCode(r.Context()), Time: time.Now()}); err != nil {
+			return
+		}
+	}
+	for _, ev := range backlog {
+		if err := send(ev); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(time.Duration(consts.EVENT_HEARTBEAT_INTERVAL) * time.Second)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ws.Closed():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := send(ev); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := ws.Ping(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,517 @@
+// filter.go
+package tables
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"AllinB/src/consts"
+)
+
+// filterType은 필터할 수 있는 컬럼의 값 종류입니다.
+type filterType int
+
+const (
+	filterInt filterType = iota
+	filterText
+)
+
+// filterColumns는 필터할 수 있는 컬럼명 → 값 종류입니다. 테이블마다 하나씩 정의합니다.
+type filterColumns map[string]filterType
+
+// Filter는 목록 조회 조건식입니다.
+// Postgres 저장소는 파라미터화된 SQL로 변환하고, 메모리 저장소는 직접 평가합니다. nil이면 조건이 없습니다.
+type Filter interface {
+	// sql은 조건식을 SQL로 만들고 값은 args 뒤에 추가합니다. ($n은 args 길이 기준입니다)
+	sql(args *[]interface{}) string
+	// match는 row(테이블 구조체)가 조건을 만족하는지 확인합니다.
+	match(row interface{}) bool
+}
+
+// filterOperators는 조건식에 사용할 수 있는 연산자 → SQL 연산자입니다.
+var filterOperators = map[string]string{
+	"eq":      "=",
+	"ne":      "<>",
+	"gt":      ">",
+	"gte":     ">=",
+	"lt":      "<",
+	"lte":     "<=",
+	"in":      "IN",
+	"between": "BETWEEN",
+	"like":    "LIKE",
+	"ilike":   "ILIKE",
+}
+
+// filterCondition은 "컬럼 연산자 값" 조건 하나입니다.
+// Values는 in이면 여러 개, between이면 두 개, 그 외에는 하나이며 int 또는 string입니다.
+type filterCondition struct {
+	Column string
+	Op     string
+	Values []interface{}
+}
+
+func (c filterCondition) sql(args *[]interface{}) string {
+	params := make([]string, len(c.Values))
+	for i, value := range c.Values {
+		*args = append(*args, value)
+		params[i] = "$" + strconv.Itoa(len(*args))
+	}
+	switch c.Op {
+	case "in":
+		return c.Column + " IN (" + strings.Join(params, ", ") + ")"
+	case "between":
+		return c.Column + " BETWEEN " + params[0] + " AND " + params[1]
+	}
+	return c.Column + " " + filterOperators[c.Op] + " " + params[0]
+}
+
+func (c filterCondition) match(row interface{}) bool {
+	value := columnValue(row, c.Column)
+	switch c.Op {
+	case "eq":
+		return compareValues(value, c.Values[0]) == 0
+	case "ne":
+		return compareValues(value, c.Values[0]) != 0
+	case "gt":
+		return compareValues(value, c.Values[0]) > 0
+	case "gte":
+		return compareValues(value, c.Values[0]) >= 0
+	case "lt":
+		return compareValues(value, c.Values[0]) < 0
+	case "lte":
+		return compareValues(value, c.Values[0]) <= 0
+	case "in":
+		for _, v := range c.Values {
+			if compareValues(value, v) == 0 {
+				return true
+			}
+		}
+		return false
+	case "between":
+		return compareValues(value, c.Values[0]) >= 0 && compareValues(value, c.Values[1]) <= 0
+	case "like", "ilike":
+		text, _ := value.(string)
+		pattern, _ := c.Values[0].(string)
+		return likePattern(pattern, c.Op == "ilike").MatchString(text)
+	}
+	return false
+}
+
+// filterGroup은 조건들을 AND 또는 OR로 묶습니다.
+type filterGroup struct {
+	Or    bool
+	Items []Filter
+}
+
+func (g filterGroup) sql(args *[]interface{}) string {
+	parts := make([]string, len(g.Items))
+	for i, item := range g.Items {
+		parts[i] = item.sql(args)
+	}
+	join := " AND "
+	if g.Or {
+		join = " OR "
+	}
+	return "(" + strings.Join(parts, join) + ")"
+}
+
+func (g filterGroup) match(row interface{}) bool {
+	for _, item := range g.Items {
+		if item.match(row) == g.Or {
+			return g.Or
+		}
+	}
+	return !g.Or
+}
+
+// filterAnd는 nil이 아닌 조건들을 AND로 묶습니다. 모두 nil이면 nil입니다.
+func filterAnd(items ...Filter) Filter {
+	var filters []Filter
+	for _, item := range items {
+		if item != nil {
+			filters = append(filters, item)
+		}
+	}
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	}
+	return filterGroup{Items: filters}
+}
+
+// filterEq는 column = value 조건입니다.
+func filterEq(column string, value interface{}) Filter {
+	return filterCondition{Column: column, Op: "eq", Values: []interface{}{value}}
+}
+
+// columnValue는 row 구조체에서 JSON 태그가 column인 필드 값을 반환합니다.
+func columnValue(row interface{}, column string) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(row))
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == column {
+			return v.Field(i).Interface()
+		}
+	}
+	return nil
+}
+
+// compareValues는 같은 종류(int 또는 string)의 두 값을 비교합니다.
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		b, _ := b.(int)
+		return a - b
+	case string:
+		b, _ := b.(string)
+		return strings.Compare(a, b)
+	}
+	return -1
+}
+
+// likePattern은 SQL LIKE 패턴(%, _, \ 이스케이프)을 정규식으로 바꿉니다. fold이면 대소문자를 무시합니다.
+func likePattern(pattern string, fold bool) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?s)")
+	if fold {
+		b.WriteString("(?i)")
+	}
+	b.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// errInvalidFilter는 필터 조건을 해석할 수 없을 때 반환됩니다.
+var errInvalidFilter = errors.New("잘못된 filter")
+
+// parseFilter는 목록 조회 요청의 필터 조건을 읽어 하나의 Filter로 묶습니다. 모든 조건은 AND입니다.
+//   - 컬럼명=값: 허용된 컬럼의 일치 조건 (예: room_code=3)
+//   - filter=조건식: 연산자와 OR 묶음을 사용하는 조건식
+//     (예: filter=seat_code between 1 and 20 and (gender eq 1 or seat_title ilike '%창가%'))
+//
+// 조건식의 연산자는 eq, ne, gt, gte, lt, lte, in (값, ...), between 값 and 값, like, ilike이며,
+// 문자열 값은 작은따옴표로 감싸고 작은따옴표 자체는 두 번 연달아 씁니다. and가 or보다 먼저 묶입니다.
+func parseFilter(r *http.Request, columns filterColumns) (Filter, error) {
+	query := r.URL.Query()
+
+	names := make([]string, 0, len(columns))
+	for column := range columns {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+
+	var filters []Filter
+	for _, column := range names {
+		value := query.Get(column)
+		if value == "" {
+			continue
+		}
+		if columns[column] == filterText {
+			filters = append(filters, filterEq(column, value))
+			continue
+		}
+		// 정수 컬럼은 INTEGER이므로 int32 범위를 넘는 값은 DB 오류 대신 잘못된 필터로 거부합니다.
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 잘못된 %s 값", errInvalidFilter, column)
+		}
+		filters = append(filters, filterEq(column, int(n)))
+	}
+
+	if expr := query.Get("filter"); expr != "" {
+		f, err := parseFilterExpr(expr, columns)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filterAnd(filters...), nil
+}
+
+// filterToken은 조건식의 토큰입니다.
+// kind는 'w'(단어), 'n'(숫자), 's'(문자열) 또는 괄호/쉼표 문자 자체입니다.
+type filterToken struct {
+	kind byte
+	text string
+}
+
+// tokenizeFilter는 조건식을 토큰으로 나눕니다.
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, filterToken{kind: c, text: string(c)})
+			i++
+		case c == '\'':
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(expr) {
+					return nil, fmt.Errorf("%w: 문자열이 닫히지 않았습니다", errInvalidFilter)
+				}
+				if expr[i] == '\'' {
+					if i+1 < len(expr) && expr[i+1] == '\'' {
+						b.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(expr[i])
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: 's', text: b.String()})
+		case c == '-' || c >= '0' && c <= '9':
+			start := i
+			i++
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: 'n', text: expr[start:i]})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(expr) && (expr[i] == '_' || expr[i] >= 'a' && expr[i] <= 'z' ||
+				expr[i] >= 'A' && expr[i] <= 'Z' || expr[i] >= '0' && expr[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: 'w', text: strings.ToLower(expr[start:i])})
+		default:
+			return nil, fmt.Errorf("%w: 알 수 없는 문자 %q", errInvalidFilter, c)
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser는 조건식 토큰을 Filter로 변환합니다.
+//
+//	or_expr  = and_expr { "or" and_expr }
+//	and_expr = factor { "and" factor }
+//	factor   = "(" or_expr ")" | 컬럼 연산자 값
+type filterParser struct {
+	tokens     []filterToken
+	pos        int
+	columns    filterColumns
+	conditions int
+	depth      int
+}
+
+// parseFilterExpr는 filter 쿼리의 조건식을 해석합니다. 컬럼은 columns에 있는 것만 사용할 수 있습니다.
+func parseFilterExpr(expr string, columns filterColumns) (Filter, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens, columns: columns}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: 예상하지 못한 %q", errInvalidFilter, p.tokens[p.pos].text)
+	}
+	return f, nil
+}
+
+// peek는 현재 토큰을 반환합니다. 끝이면 kind가 0입니다.
+func (p *filterParser) peek() filterToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return filterToken{}
+}
+
+// next는 현재 토큰을 반환하고 다음으로 넘어갑니다.
+func (p *filterParser) next() filterToken {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+// keyword는 현재 토큰이 word이면 소비하고 true를 반환합니다.
+func (p *filterParser) keyword(word string) bool {
+	if t := p.peek(); t.kind == 'w' && t.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect는 현재 토큰이 kind인지 확인하고 소비합니다.
+func (p *filterParser) expect(kind byte) error {
+	if t := p.next(); t.kind != kind {
+		return fmt.Errorf("%w: %q가 필요합니다", errInvalidFilter, string(kind))
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	items := []Filter{f}
+	for p.keyword("or") {
+		f, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, f)
+	}
+	if len(items) == 1 {
+		return items[0], nil
+	}
+	return filterGroup{Or: true, Items: items}, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	f, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	items := []Filter{f}
+	for p.keyword("and") {
+		f, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, f)
+	}
+	if len(items) == 1 {
+		return items[0], nil
+	}
+	return filterGroup{Items: items}, nil
+}
+
+func (p *filterParser) parseFactor() (Filter, error) {
+	if p.peek().kind == '(' {
+		p.next()
+		if p.depth++; p.depth > consts.FILTER_MAX_DEPTH {
+			return nil, fmt.Errorf("%w: 괄호는 %d단계까지 중첩할 수 있습니다", errInvalidFilter, consts.FILTER_MAX_DEPTH)
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		p.depth--
+		return f, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *filterParser) parseCondition() (Filter, error) {
+	if p.conditions++; p.conditions > consts.FILTER_MAX_CONDITIONS {
+		return nil, fmt.Errorf("%w: 조건은 %d개까지 사용할 수 있습니다", errInvalidFilter, consts.FILTER_MAX_CONDITIONS)
+	}
+
+	t := p.next()
+	if t.kind != 'w' {
+		return nil, fmt.Errorf("%w: 컬럼명이 필요합니다", errInvalidFilter)
+	}
+	typ, ok := p.columns[t.text]
+	if !ok {
+		return nil, fmt.Errorf("%w: 필터할 수 없는 컬럼 %s", errInvalidFilter, t.text)
+	}
+	c := filterCondition{Column: t.text}
+
+	op := p.next()
+	if _, ok := filterOperators[op.text]; op.kind != 'w' || !ok {
+		return nil, fmt.Errorf("%w: 알 수 없는 연산자 %q", errInvalidFilter, op.text)
+	}
+	c.Op = op.text
+	if (c.Op == "like" || c.Op == "ilike") && typ != filterText {
+		return nil, fmt.Errorf("%w: %s는 문자열 컬럼에만 사용할 수 있습니다", errInvalidFilter, c.Op)
+	}
+
+	switch c.Op {
+	case "in":
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		for {
+			value, err := p.parseValue(c.Column, typ)
+			if err != nil {
+				return nil, err
+			}
+			c.Values = append(c.Values, value)
+			if p.peek().kind != ',' {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+	case "between":
+		low, err := p.parseValue(c.Column, typ)
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword("and") {
+			return nil, fmt.Errorf("%w: between에는 and가 필요합니다", errInvalidFilter)
+		}
+		high, err := p.parseValue(c.Column, typ)
+		if err != nil {
+			return nil, err
+		}
+		c.Values = []interface{}{low, high}
+	default:
+		value, err := p.parseValue(c.Column, typ)
+		if err != nil {
+			return nil, err
+		}
+		c.Values = []interface{}{value}
+	}
+	return c, nil
+}
+
+// parseValue는 column의 값 종류에 맞는 값 하나를 읽습니다.
+func (p *filterParser) parseValue(column string, typ filterType) (interface{}, error) {
+	t := p.next()
+	switch {
+	case typ == filterInt && t.kind == 'n':
+		n, err := strconv.ParseInt(t.text, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 잘못된 %s 값 %s", errInvalidFilter, column, t.text)
+		}
+		return int(n), nil
+	case typ == filterText && t.kind == 's':
+		return t.text, nil
+	case typ == filterInt:
+		return nil, fmt.Errorf("%w: %s에는 숫자 값이 필요합니다", errInvalidFilter, column)
+	}
+	return nil, fmt.Errorf("%w: %s에는 작은따옴표로 감싼 문자열 값이 필요합니다", errInvalidFilter, column)
+}
@@ -0,0 +1,167 @@
+package tables
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFilterExpr(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{"단일 조건", "seat_code eq 3", "seat_code = $1", []interface{}{3}},
+		{"대소문자 무시", "SEAT_CODE GTE -1", "seat_code >= $1", []interface{}{-1}},
+		{"and가 or보다 먼저", "gender eq 1 or gender eq 2 and seat_code lt 10",
+			"(gender = $1 OR (gender = $2 AND seat_code < $3))", []interface{}{1, 2, 10}},
+		{"괄호가 우선", "(gender eq 1 or gender eq 2) and seat_code lt 10",
+			"((gender = $1 OR gender = $2) AND seat_code < $3)", []interface{}{1, 2, 10}},
+		{"in", "seat_code in (1, 2,3)", "seat_code IN ($1, $2, $3)", []interface{}{1, 2, 3}},
+		{"between과 and", "seat_code between 1 and 20 and room_code eq 2",
+			"(seat_code BETWEEN $1 AND $2 AND room_code = $3)", []interface{}{1, 20, 2}},
+		{"ilike", "seat_title ilike '%창가%'", "seat_title ILIKE $1", []interface{}{"%창가%"}},
+		{"작은따옴표 이스케이프", "seat_title eq 'it''s'", "seat_title = $1", []interface{}{"it's"}},
+		{"빈 문자열", "seat_title eq ''", "seat_title = $1", []interface{}{""}},
+		// 문자열 안의 SQL은 값 그대로 파라미터로 전달되고 SQL 문에는 들어가지 않습니다.
+		{"문자열 속 SQL 주입", "seat_title eq 'x'' OR 1=1 --'", "seat_title = $1", []interface{}{"x' OR 1=1 --"}},
+		{"문자열 속 세미콜론", "seat_title eq '1; DROP TABLE seat_table'", "seat_title = $1", []interface{}{"1; DROP TABLE seat_table"}},
+		{"int32 최댓값", "seat_code eq 2147483647", "seat_code = $1", []interface{}{2147483647}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseFilterExpr(tt.expr, seatFilterColumns)
+			if err != nil {
+				t.Fatalf("parseFilterExpr(%q): %v", tt.expr, err)
+			}
+			var args []interface{}
+			if got := f.sql(&args); got != tt.wantSQL {
+				t.Errorf("sql = %s, want %s", got, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseFilterExprErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"알 수 없는 컬럼", "password eq 'x'"},
+		{"컬럼명에 SQL", "seat_code eq 1 or 1 eq 1"},
+		{"연산자에 SQL", "seat_code = 1"},
+		{"주석", "seat_code eq 1 -- x"},
+		{"세미콜론", "seat_code eq 1; DROP TABLE seat_table"},
+		{"따옴표 밖 SQL", "seat_title eq 'x' OR 1=1"},
+		{"닫히지 않은 문자열", "seat_title eq 'x"},
+		{"닫히지 않은 괄호", "(seat_code eq 1"},
+		{"남는 괄호", "seat_code eq 1)"},
+		{"알 수 없는 연산자", "seat_code contains 1"},
+		{"정수 컬럼에 문자열", "seat_code eq '1'"},
+		{"문자열 컬럼에 숫자", "seat_title eq 1"},
+		{"정수 컬럼에 like", "seat_code like '1%'"},
+		{"between에 and 없음", "seat_code between 1 20"},
+		{"빈 in", "seat_code in ()"},
+		{"int32 초과", "seat_code eq 2147483648"},
+		{"int32 미만", "seat_code gt -2147483649"},
+		{"빈 조건식", "   "},
+		{"괄호 중첩 초과", strings.Repeat("(", 9) + "seat_code eq 1" + strings.Repeat(")", 9)},
+		{"조건 수 초과", strings.TrimSuffix(strings.Repeat("seat_code eq 1 or ", 33), " or ")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseFilterExpr(tt.expr, seatFilterColumns); !errors.Is(err, errInvalidFilter) {
+				t.Fatalf("parseFilterExpr(%q) err = %v, want errInvalidFilter", tt.expr, err)
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    url.Values
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{"조건 없음", url.Values{}, "", nil, false},
+		{"컬럼 파라미터", url.Values{"room_code": {"2"}}, "room_code = $1", []interface{}{2}, false},
+		{"문자열 컬럼 파라미터는 값 그대로", url.Values{"seat_title": {"' OR 1=1 --"}}, "seat_title = $1", []interface{}{"' OR 1=1 --"}, false},
+		{"컬럼 파라미터와 조건식은 AND", url.Values{"room_code": {"2"}, "filter": {"gender eq 1 or gender eq 2"}},
+			"(room_code = $1 AND (gender = $2 OR gender = $3))", []interface{}{2, 1, 2}, false},
+		{"허용되지 않은 쿼리 파라미터는 무시", url.Values{"password": {"x"}}, "", nil, false},
+		{"정수가 아닌 값", url.Values{"room_code": {"abc"}}, "", nil, true},
+		{"int32 초과", url.Values{"room_code": {"99999999999"}}, "", nil, true},
+		{"잘못된 조건식", url.Values{"filter": {"room_code eq"}}, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/seats?"+tt.query.Encode(), nil)
+			f, err := parseFilter(r, seatFilterColumns)
+			if tt.wantErr {
+				if !errors.Is(err, errInvalidFilter) {
+					t.Fatalf("err = %v, want errInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sql string
+			var args []interface{}
+			if f != nil {
+				sql = f.sql(&args)
+			}
+			if sql != tt.wantSQL || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("sql = %q %#v, want %q %#v", sql, args, tt.wantSQL, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	seats := []Seat{
+		{SeatCode: 1, Gender: 1, SeatTitle: "창가 1"},
+		{SeatCode: 2, Gender: 2, SeatTitle: "복도"},
+		{SeatCode: 12, Gender: 1, SeatTitle: "x' OR 1=1 --"},
+	}
+	tests := []struct {
+		name string
+		expr string
+		want []int
+	}{
+		{"and가 or보다 먼저", "gender eq 2 or gender eq 1 and seat_code gt 10", []int{2, 12}},
+		{"괄호가 우선", "(gender eq 2 or gender eq 1) and seat_code gt 10", []int{12}},
+		{"ilike", "seat_title ilike '%창가%'", []int{1}},
+		{"like는 대소문자 구분", "seat_title like '%or%'", nil},
+		{"주입 문자열은 값으로 비교", "seat_title eq 'x'' OR 1=1 --'", []int{12}},
+		{"in", "seat_code in (2, 12)", []int{2, 12}},
+		{"between", "seat_code between 2 and 11", []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseFilterExpr(tt.expr, seatFilterColumns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, seat := range seats {
+				if f.match(seat) {
+					got = append(got, seat.SeatCode)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// gender.go
+package tables
+
+import (
+	"context"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// validGender는 gender 값이 consts에 정의된 값인지 확인합니다.
+func validGender(v int) bool {
+	switch v {
+	case consts.GENDER_ANY, consts.GENDER_MALE, consts.GENDER_FEMALE:
+		return true
+	}
+	return false
+}
+
+// genderAllowed는 성별 제한(restriction)이 회원 성별을 허용하는지 확인합니다.
+// 제한이 GENDER_ANY이면 항상 허용하며, 성별을 지정하지 않은 회원은 제한된 좌석을 이용할 수 없습니다.
+func genderAllowed(restriction, memberGender int) bool {
+	return restriction == consts.GENDER_ANY || restriction == memberGender
+}
+
+// seatGenderAllowed는 seat과 seat이 속한 room의 성별 제한을 모두 확인합니다.
+// companyCode에 속한 seat이 없으면 sql.ErrNoRows를 반환합니다.
+func seatGenderAllowed(ctx context.Context, companyCode, seatCode, memberGender int) (bool, error) {
+	var seatGender, roomGender int
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT s.gender, COALESCE(r.gender, 0)
+		FROM seat_table s
+		LEFT JOIN room_table r ON r.room_code = s.room_code AND r.company_code = s.company_code
+		WHERE s.company_code = $1 AND s.seat_code = $2`, companyCode, seatCode).Scan(&seatGender, &roomGender)
+	if err != nil {
+		return false, err
+	}
+	return genderAllowed(seatGender, memberGender) && genderAllowed(roomGender, memberGender), nil
+}
@@ -0,0 +1,487 @@
+// kiosk.go
+package tables
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// Kiosk 구조체는 kiosk_table의 각 컬럼을 매핑합니다.
+// 장비 인증 키는 해시로만 저장하며, 발급 시 한 번만 KioskKey로 응답합니다.
+type Kiosk struct {
+	KioskID     int       `json:"kiosk_id" db:"kiosk_id"`
+	CompanyCode int       `json:"company_code"`
+	KioskName   string    `json:"kiosk_name"`
+	Enabled     int       `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	KioskKey    string    `json:"kiosk_key,omitempty"`
+}
+
+// kioskContextKey는 인증된 키오스크를 요청 컨텍스트에 저장할 때 사용하는 키입니다.
+type kioskContextKey struct{}
+
+// KioskFromContext는 ResolveTenant가 인증하여 저장한 키오스크를 반환합니다.
+func KioskFromContext(ctx context.Context) (Kiosk, bool) {
+	k, ok := ctx.Value(kioskContextKey{}).(Kiosk)
+	return k, ok
+}
+
+// kioskMemberContextKey는 키오스크 요청에서 인증한 회원의 member_id를 요청 컨텍스트에 저장할 때 사용하는 키입니다.
+type kioskMemberContextKey struct{}
+
+// kioskMemberFromContext는 prepareKioskRequest가 인증하여 저장한 회원의 member_id를 반환합니다.
+func kioskMemberFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(kioskMemberContextKey{}).(int)
+	return id, ok
+}
+
+// RegisterKioskRoutes는 키오스크 장비 관리 엔드포인트와 /kiosk 셀프서비스 엔드포인트를 등록합니다.
+// 장비 관리는 점주 전용이며, /kiosk 하위 경로는 키오스크 토큰(또는 X-Kiosk-Id, X-Kiosk-Key 헤더)으로 인증된 장비만 사용할 수 있습니다.
+// 좌석 체크인/체크아웃/연장/이동은 본문에 회원 전화번호(phone)와 비밀번호(password)가 있어야 합니다.
+func RegisterKioskRoutes(r *mux.Router) {
+	r.Handle("/kiosks", allow(GetKiosks, ownerOnly...)).Methods("GET")
+	r.Handle("/kiosks", allow(CreateKiosk, ownerOnly...)).Methods("POST")
+	r.Handle("/kiosks/{kiosk_id}", allow(DeleteKiosk, ownerOnly...)).Methods("DELETE")
+
+	k := r.PathPrefix("/kiosk").Subrouter()
+	k.Use(KioskAuthMiddleware)
+	k.HandleFunc("/layout", KioskLayout).Methods("GET")
+	k.HandleFunc("/seats/{seat_code}/checkin", KioskCheckIn).Methods("POST")
+	k.HandleFunc("/seats/{seat_code}/checkout", KioskCheckOut).Methods("POST")
+	k.HandleFunc("/seats/{seat_code}/extend", KioskExtend).Methods("POST")
+	k.HandleFunc("/seats/{seat_code}/move", KioskMove).Methods("POST")
+}
+
+// hashKioskKey는 키오스크 인증 키의 SHA-256 해시(hex)를 반환합니다.
+func hashKioskKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// errKioskAuthFailed는 키오스크 인증 키가 맞지 않거나 비활성화된 장비일 때 반환됩니다.
+var errKioskAuthFailed = fmt.Errorf("키오스크 인증에 실패했습니다: %w", utils.ErrUnauthenticated)
+
+// authenticateKiosk는 kiosk_id와 장비 인증 키로 키오스크를 인증합니다.
+// 인증에 실패하면 utils.ErrUnauthenticated를 감싼 errKioskAuthFailed를 반환합니다.
+func authenticateKiosk(ctx context.Context, kioskID int, key string) (Kiosk, error) {
+	var kiosk Kiosk
+	if kioskID <= 0 || key == "" {
+		return kiosk, errKioskAuthFailed
+	}
+
+	var keyHash string
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT kiosk_id, company_code, kiosk_name, enabled, created_at, key_hash
+		FROM kiosk_table WHERE kiosk_id = $1`, kioskID).
+		Scan(&kiosk.KioskID, &kiosk.CompanyCode, &kiosk.KioskName, &kiosk.Enabled,
+			&kiosk.CreatedAt, &keyHash)
+	if err == sql.ErrNoRows {
+		return kiosk, errKioskAuthFailed
+	}
+	if err != nil {
+		return kiosk, err
+	}
+	if kiosk.Enabled == 0 ||
+		subtle.ConstantTimeCompare([]byte(hashKioskKey(key)), []byte(keyHash)) != 1 {
+		return kiosk, errKioskAuthFailed
+	}
+	return kiosk, nil
+}
+
+// loadKiosk는 키오스크 토큰의 kiosk_id로 활성화된 키오스크를 조회합니다.
+// 삭제/비활성화된 장비이면 errKioskAuthFailed를 반환합니다.
+func loadKiosk(ctx context.Context, companyCode, kioskID int) (Kiosk, error) {
+	var kiosk Kiosk
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT kiosk_id, company_code, kiosk_name, enabled, created_at
+		FROM kiosk_table WHERE kiosk_id = $1 AND company_code = $2 AND enabled <> 0`,
+		kioskID, companyCode).
+		Scan(&kiosk.KioskID, &kiosk.CompanyCode, &kiosk.KioskName, &kiosk.Enabled, &kiosk.CreatedAt)
+	if err == sql.ErrNoRows {
+		return kiosk, errKioskAuthFailed
+	}
+	return kiosk, err
+}
+
+// KioskAuthMiddleware는 /kiosk 경로를 키오스크 역할의 호출자로 제한합니다.
+// 장비 인증은 ResolveTenant에서 처리되며, 여기서는 인증된 키오스크가 있는지만 확인합니다.
+func KioskAuthMiddleware(next http.Handler) http.Handler {
+	return utils.RequireRole(consts.ROLE_KIOSK)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := KioskFromContext(r.Context()); !ok {
+			writeProblem(w, consts.ERR_UNAUTHENTICATED, "키오스크 인증 정보가 필요합니다.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// GetKiosks: 호출자의 company_code에 등록된 키오스크 목록을 조회합니다.
+func GetKiosks(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	rows, err := utils.DB.QueryContext(ctx, `
+		SELECT kiosk_id, company_code, kiosk_name, enabled, created_at
+		FROM kiosk_table WHERE company_code = $1
+		ORDER BY kiosk_id ASC`, utils.CompanyCode(r.Context()))
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []Kiosk{}
+	for rows.Next() {
+		var k Kiosk
+		if err := rows.Scan(&k.KioskID, &k.CompanyCode, &k.KioskName, &k.Enabled, &k.CreatedAt); err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, k)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// CreateKiosk: 키오스크를 등록하고 장비 인증 키를 발급합니다.
+// 발급된 kiosk_key는 이 응답에서만 확인할 수 있습니다.
+func CreateKiosk(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var kiosk Kiosk
+	if err := json.NewDecoder(r.Body).Decode(&kiosk); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	// 키오스크는 항상 호출자의 company_code로 등록합니다.
+	kiosk.CompanyCode = utils.CompanyCode(r.Context())
+	kiosk.KioskName = strings.TrimSpace(kiosk.KioskName)
+	if kiosk.KioskName == "" {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "kiosk_name이 필요합니다.")
+		return
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	kiosk.KioskKey = hex.EncodeToString(raw)
+	kiosk.Enabled = 1
+
+	err := utils.DB.QueryRowContext(ctx, `
+		INSERT INTO kiosk_table (company_code, kiosk_name, key_hash, enabled)
+		VALUES ($1, $2, $3, $4)
+		RETURNING kiosk_id, created_at`,
+		kiosk.CompanyCode, kiosk.KioskName, hashKioskKey(kiosk.KioskKey), kiosk.Enabled).
+		Scan(&kiosk.KioskID, &kiosk.CreatedAt)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		writeInternalError(w, err)
+		return
+	}
+	log.Printf("키오스크 등록: kiosk_id=%d, company_code=%d", kiosk.KioskID, kiosk.CompanyCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(kiosk)
+}
+
+// DeleteKiosk: 키오스크를 삭제하여 장비 인증 키를 폐기합니다.
+func DeleteKiosk(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	kioskID, err := strconv.Atoi(mux.Vars(r)["kiosk_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 kiosk_id")
+		return
+	}
+	res, err := utils.DB.ExecContext(ctx,
+		"DELETE FROM kiosk_table WHERE kiosk_id = $1 AND company_code = $2",
+		kioskID, utils.CompanyCode(r.Context()))
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Kiosk를 찾을 수 없습니다.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// KioskRoom은 키오스크 배치도에 표시되는 room과 그 안의 seat 목록입니다.
+type KioskRoom struct {
+	Room
+	Seats []KioskSeat `json:"seats"`
+}
+
+// KioskSeat은 키오스크 배치도에 표시되는 seat과 현재 사용 여부입니다.
+type KioskSeat struct {
+	Seat
+	Occupied       bool       `json:"occupied"`
+	PlannedEndTime *time.Time `json:"planned_end_time"`
+}
+
+// KioskLayout: 키오스크 회사의 배치도를 조회합니다.
+// kiosk_disabled가 설정된 room과 seat(및 그런 room 안의 seat)은 제외합니다.
+func KioskLayout(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	kiosk, _ := KioskFromContext(r.Context())
+
+	roomRows, err := utils.DB.QueryContext(ctx, `
+		SELECT auto_increment, company_code, room_code, room_title,
+		       title_background_color, title_text_color, room_background_color,
+		       room_top, room_left, room_width, room_height,
+		       gender, waiting, release, hide_title,
+		       transparent_background, hide_border, kiosk_disabled,
+		       power_control, breaker_number
+		FROM room_table
+		WHERE company_code = $1 AND kiosk_disabled = 0
+		ORDER BY room_code ASC`, kiosk.CompanyCode)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	rooms := []*KioskRoom{}
+	roomIndex := make(map[int]*KioskRoom)
+	for roomRows.Next() {
+		kr := &KioskRoom{Seats: []KioskSeat{}}
+		room := &kr.Room
+		if err := roomRows.Scan(&room.AutoIncrement, &room.CompanyCode, &room.RoomCode, &room.RoomTitle,
+			&room.TitleBackgroundColor, &room.TitleTextColor, &room.RoomBackgroundColor,
+			&room.RoomTop, &room.RoomLeft, &room.RoomWidth, &room.RoomHeight,
+			&room.Gender, &room.Waiting, &room.Release, &room.HideTitle,
+			&room.TransparentBackground, &room.HideBorder, &room.KioskDisabled,
+			&room.PowerControl, &room.BreakerNumber); err != nil {
+			roomRows.Close()
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		rooms = append(rooms, kr)
+		roomIndex[room.RoomCode] = kr
+	}
+	roomRows.Close()
+
+	seatRows, err := utils.DB.QueryContext(ctx, `
+		SELECT s.auto_increment, s.company_code, s.seat_code, s.room_code, s.seat_title,
+		       s.title_background_color, s.title_text_color, s.seat_background_color,
+		       s.seat_top, s.seat_left, s.seat_width, s.seat_height,
+		       s.gender, s.waiting, s.release, s.hide_title,
+		       s.transparent_background, s.hide_border, s.kiosk_disabled,
+		       s.power_control, s.breaker_number,
+		       ss.auto_increment IS NOT NULL, ss.planned_end_time
+		FROM seat_table s
+		LEFT JOIN seat_session_table ss
+		       ON ss.company_code = s.company_code AND ss.seat_code = s.seat_code AND ss.end_time IS NULL
+		WHERE s.company_code = $1 AND s.kiosk_disabled = 0
+		ORDER BY s.seat_code ASC`, kiosk.CompanyCode)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer seatRows.Close()
+	for seatRows.Next() {
+		var ks KioskSeat
+		seat := &ks.Seat
+		if err := seatRows.Scan(&seat.AutoIncrement, &seat.CompanyCode, &seat.SeatCode, &seat.RoomCode, &seat.SeatTitle,
+			&seat.TitleBackgroundColor, &seat.TitleTextColor, &seat.SeatBackgroundColor,
+			&seat.SeatTop, &seat.SeatLeft, &seat.SeatWidth, &seat.SeatHeight,
+			&seat.Gender, &seat.Waiting, &seat.Release, &seat.HideTitle,
+			&seat.TransparentBackground, &seat.HideBorder, &seat.KioskDisabled,
+			&seat.PowerControl, &seat.BreakerNumber,
+			&ks.Occupied, &ks.PlannedEndTime); err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		// 키오스크에서 숨긴 room 안의 seat은 제외합니다.
+		if kr, ok := roomIndex[seat.RoomCode]; ok {
+			kr.Seats = append(kr.Seats, ks)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rooms)
+}
+
+// isKioskCaller는 키오스크로 인증된 요청인지 확인합니다.
+func isKioskCaller(r *http.Request) bool {
+	claims, ok := utils.ClaimsFromContext(r.Context())
+	return ok && claims.Role == consts.ROLE_KIOSK
+}
+
+// kioskRoomFilter는 키오스크 호출이면 where에 kiosk_disabled = 0 조건을 더합니다.
+func kioskRoomFilter(r *http.Request, where Filter) Filter {
+	if !isKioskCaller(r) {
+		return where
+	}
+	return filterAnd(where, filterEq("kiosk_disabled", 0))
+}
+
+// kioskSeatFilter는 키오스크 호출이면 where에 kiosk_disabled = 0이고 키오스크에서 숨긴 room에 속하지 않는 조건을 더합니다.
+// KioskLayout과 같은 규칙입니다.
+func kioskSeatFilter(ctx context.Context, r *http.Request, companyCode int, where Filter) (Filter, error) {
+	if !isKioskCaller(r) {
+		return where, nil
+	}
+	hidden, err := roomRepo.List(ctx, companyCode, RoomFilter{Where: filterEq("kiosk_disabled", 1)})
+	if err != nil {
+		return nil, err
+	}
+	where = filterAnd(where, filterEq("kiosk_disabled", 0))
+	for _, room := range hidden {
+		where = filterAnd(where, filterCondition{Column: "room_code", Op: "ne", Values: []interface{}{room.RoomCode}})
+	}
+	return where, nil
+}
+
+// kioskSeatAvailable은 seat이 키오스크 회사 소속이며 seat/room 모두 키오스크에서 사용 가능한지 확인합니다.
+// 키오스크에서 볼 수 없는 seat은 존재하지 않는 것처럼 false를 반환합니다.
+func kioskSeatAvailable(ctx context.Context, seatCode, companyCode int) (bool, error) {
+	var available bool
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT s.kiosk_disabled = 0 AND COALESCE(r.kiosk_disabled, 0) = 0
+		FROM seat_table s
+		LEFT JOIN room_table r ON r.room_code = s.room_code AND r.company_code = s.company_code
+		WHERE s.seat_code = $1 AND s.company_code = $2`, seatCode, companyCode).Scan(&available)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return available, err
+}
+
+// kioskMemberRequest는 키오스크 요청 본문의 회원 인증 정보(phone, password)와 member_id, to_seat_code를 담습니다.
+type kioskMemberRequest struct {
+	Phone      string `json:"phone"`
+	Password   string `json:"password"`
+	MemberID   int    `json:"member_id"`
+	ToSeatCode int    `json:"to_seat_code"`
+}
+
+// prepareKioskRequest는 키오스크 요청의 회원 인증 정보와 좌석 사용 가능 여부를 확인합니다.
+// 키오스크는 여러 회원이 함께 사용하므로 member_id만으로는 회원을 믿지 않고,
+// 매 요청마다 전화번호/비밀번호(MemberLogin과 같은 확인)로 인증한 회원만 본인 세션을 다룰 수 있습니다.
+// 인증한 회원은 컨텍스트에 저장한 요청으로 반환하며, 본문은 다시 읽을 수 있도록 복원하여 공용 좌석 핸들러에 그대로 전달합니다.
+func prepareKioskRequest(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var req kioskMemberRequest
+	kiosk, _ := KioskFromContext(r.Context())
+	seatCode, err := strconv.Atoi(mux.Vars(r)["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return r, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil || json.Unmarshal(body, &req) != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return r, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if req.Phone == "" || req.Password == "" {
+		writeProblem(w, consts.ERR_UNAUTHENTICATED, "회원 전화번호와 비밀번호가 필요합니다.")
+		return r, false
+	}
+
+	memberID, err := authenticateMember(ctx, kiosk.CompanyCode, req.Phone, req.Password)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			loginFailed(w)
+		} else {
+			writeInternalError(w, err)
+		}
+		return r, false
+	}
+	if req.MemberID != 0 && req.MemberID != memberID {
+		writeProblem(w, consts.ERR_FORBIDDEN, "본인의 세션만 다룰 수 있습니다.")
+		return r, false
+	}
+
+	for _, code := range []int{seatCode, req.ToSeatCode} {
+		if code == 0 {
+			continue
+		}
+		available, err := kioskSeatAvailable(ctx, code, kiosk.CompanyCode)
+		if err != nil {
+			writeInternalError(w, err)
+			return r, false
+		}
+		if !available {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+			return r, false
+		}
+	}
+	return r.WithContext(context.WithValue(r.Context(), kioskMemberContextKey{}, memberID)), true
+}
+
+// KioskCheckIn: 키오스크에서 인증한 회원으로 좌석에 체크인합니다.
+func KioskCheckIn(w http.ResponseWriter, r *http.Request) {
+	r, ok := prepareKioskRequest(w, r)
+	if !ok {
+		return
+	}
+	CheckInSeat(w, r)
+}
+
+// KioskCheckOut: 키오스크에서 인증한 회원이 사용 중인 좌석을 체크아웃합니다.
+func KioskCheckOut(w http.ResponseWriter, r *http.Request) {
+	r, ok := prepareKioskRequest(w, r)
+	if !ok {
+		return
+	}
+	CheckOutSeat(w, r)
+}
+
+// KioskExtend: 키오스크에서 인증한 회원 세션의 이용 시간을 연장합니다.
+func KioskExtend(w http.ResponseWriter, r *http.Request) {
+	r, ok := prepareKioskRequest(w, r)
+	if !ok {
+		return
+	}
+	ExtendSeatSession(w, r)
+}
+
+// KioskMove: 키오스크에서 인증한 회원의 세션을 다른 좌석으로 옮깁니다.
+func KioskMove(w http.ResponseWriter, r *http.Request) {
+	r, ok := prepareKioskRequest(w, r)
+	if !ok {
+		return
+	}
+	MoveSeatSession(w, r)
+}
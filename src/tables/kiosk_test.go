@@ -0,0 +1,45 @@
+package tables
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+)
+
+func TestKioskMutationsRequireMemberCredential(t *testing.T) {
+	r := mux.NewRouter()
+	RegisterKioskRoutes(r)
+	// ResolveTenant가 장비를 인증한 것처럼 키오스크를 컨텍스트에 넣습니다.
+	h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), kioskContextKey{}, Kiosk{KioskID: 1, CompanyCode: testCompanyCode, Enabled: 1})
+		r.ServeHTTP(w, req.WithContext(ctx))
+	})
+
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"member_id만으로 체크인", "/kiosk/seats/1/checkin", `{"member_id": 3}`, http.StatusUnauthorized, consts.ERR_UNAUTHENTICATED},
+		{"member_id만으로 체크아웃", "/kiosk/seats/1/checkout", `{"member_id": 3}`, http.StatusUnauthorized, consts.ERR_UNAUTHENTICATED},
+		{"member_id만으로 연장", "/kiosk/seats/1/extend", `{"member_id": 3, "minutes": 60}`, http.StatusUnauthorized, consts.ERR_UNAUTHENTICATED},
+		{"member_id만으로 이동", "/kiosk/seats/1/move", `{"member_id": 3, "to_seat_code": 2}`, http.StatusUnauthorized, consts.ERR_UNAUTHENTICATED},
+		{"비밀번호 없음", "/kiosk/seats/1/checkin", `{"phone": "01012345678"}`, http.StatusUnauthorized, consts.ERR_UNAUTHENTICATED},
+		{"전화번호 없음", "/kiosk/seats/1/checkout", `{"password": "1234"}`, http.StatusUnauthorized, consts.ERR_UNAUTHENTICATED},
+		{"잘못된 JSON", "/kiosk/seats/1/checkin", `{`, http.StatusBadRequest, consts.ERR_INVALID_BODY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := mustServe(t, h, testRequest{Method: "POST", Target: tt.target, Body: tt.body, Role: consts.ROLE_KIOSK}, tt.wantStatus)
+			if code := problemCode(t, rec); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
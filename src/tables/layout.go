@@ -0,0 +1,161 @@
+// layout.go
+package tables
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// seatLayoutColumns는 변경 시 배치 검사가 필요한 seat_table 컬럼입니다.
+var seatLayoutColumns = map[string]bool{
+	"room_code":   true,
+	"seat_top":    true,
+	"seat_left":   true,
+	"seat_width":  true,
+	"seat_height": true,
+}
+
+// roomSizeColumns는 변경 시 seat 영역 검사가 필요한 room_table 컬럼입니다.
+var roomSizeColumns = map[string]bool{
+	"room_width":  true,
+	"room_height": true,
+}
+
+// LayoutViolation은 배치 검사에서 발견된 문제입니다.
+type LayoutViolation struct {
+	// SeatCodes는 문제가 있는 seat_code 전체입니다. (중복 없이 오름차순)
+	SeatCodes []int `json:"seat_codes"`
+	// OutOfBounds는 room 영역을 벗어난 seat_code입니다.
+	OutOfBounds []int `json:"out_of_bounds"`
+	// Overlaps는 서로 겹치는 seat_code 쌍입니다.
+	Overlaps [][2]int `json:"overlaps"`
+}
+
+// newLayoutViolation은 목록이 null 대신 빈 배열로 응답되도록 초기화된 LayoutViolation을 반환합니다.
+func newLayoutViolation() LayoutViolation {
+	return LayoutViolation{SeatCodes: []int{}, OutOfBounds: []int{}, Overlaps: [][2]int{}}
+}
+
+// Empty는 문제가 없는지 확인합니다.
+func (v LayoutViolation) Empty() bool {
+	return len(v.OutOfBounds) == 0 && len(v.Overlaps) == 0
+}
+
+// layoutErrorResponse는 배치 검사 실패 응답 본문입니다.
+type layoutErrorResponse struct {
+	utils.Problem
+	LayoutViolation
+}
+
+// seatInBounds는 seat이 room 영역(0,0 ~ room_width,room_height) 안에 있는지 확인합니다.
+// seat 위치는 room 기준 상대 좌표입니다.
+func seatInBounds(room Room, seat Seat) bool {
+	return seat.SeatTop >= 0 && seat.SeatLeft >= 0 &&
+		seat.SeatLeft+seat.SeatWidth <= room.RoomWidth &&
+		seat.SeatTop+seat.SeatHeight <= room.RoomHeight
+}
+
+// seatsOverlap은 두 seat의 사각형이 겹치는지 확인합니다. 변이 맞닿는 것은 겹침이 아닙니다.
+func seatsOverlap(a, b Seat) bool {
+	return a.SeatLeft < b.SeatLeft+b.SeatWidth && b.SeatLeft < a.SeatLeft+a.SeatWidth &&
+		a.SeatTop < b.SeatTop+b.SeatHeight && b.SeatTop < a.SeatTop+a.SeatHeight
+}
+
+// validateLayout은 changed(새로 만들거나 바꿀 seat)가 room 영역 안에 있고,
+// 서로 또는 existing(room의 기존 seat)과 겹치지 않는지 검사합니다.
+// existing 중 changed와 같은 seat_code는 변경 전 값이므로 무시하며,
+// 기존 seat끼리의 겹침은 보고하지 않습니다.
+func validateLayout(room Room, existing, changed []Seat) LayoutViolation {
+	v := newLayoutViolation()
+	offending := make(map[int]bool)
+
+	changedCodes := make(map[int]bool, len(changed))
+	for _, seat := range changed {
+		changedCodes[seat.SeatCode] = true
+	}
+
+	for i, seat := range changed {
+		if !seatInBounds(room, seat) {
+			v.OutOfBounds = append(v.OutOfBounds, seat.SeatCode)
+			offending[seat.SeatCode] = true
+		}
+		for _, other := range changed[i+1:] {
+			if seatsOverlap(seat, other) {
+				v.Overlaps = append(v.Overlaps, [2]int{seat.SeatCode, other.SeatCode})
+				offending[seat.SeatCode] = true
+				offending[other.SeatCode] = true
+			}
+		}
+		for _, other := range existing {
+			if changedCodes[other.SeatCode] {
+				continue
+			}
+			if seatsOverlap(seat, other) {
+				v.Overlaps = append(v.Overlaps, [2]int{seat.SeatCode, other.SeatCode})
+				offending[seat.SeatCode] = true
+				offending[other.SeatCode] = true
+			}
+		}
+	}
+
+	for code := range offending {
+		v.SeatCodes = append(v.SeatCodes, code)
+	}
+	sort.Ints(v.SeatCodes)
+	return v
+}
+
+// checkSeatLayout은 room의 현재 seat을 조회하여 changed의 배치를 검사합니다.
+// changed는 모두 roomCode에 속해야 하며, room이 없으면 ErrRoomNotFound입니다.
+func checkSeatLayout(ctx context.Context, companyCode, roomCode int, changed []Seat) (LayoutViolation, error) {
+	room, err := roomRepo.Get(ctx, companyCode, roomCode)
+	if errors.Is(err, ErrNotFound) {
+		return LayoutViolation{}, ErrRoomNotFound
+	}
+	if err != nil {
+		return LayoutViolation{}, err
+	}
+	existing, err := seatRepo.List(ctx, companyCode, SeatFilter{Where: filterEq("room_code", roomCode)})
+	if err != nil {
+		return LayoutViolation{}, err
+	}
+	return validateLayout(room, existing, changed), nil
+}
+
+// checkRoomBounds는 room 크기를 바꿨을 때 영역을 벗어나는 seat을 찾습니다.
+func checkRoomBounds(ctx context.Context, room Room) (LayoutViolation, error) {
+	seats, err := seatRepo.List(ctx, room.CompanyCode, SeatFilter{Where: filterEq("room_code", room.RoomCode)})
+	if err != nil {
+		return LayoutViolation{}, err
+	}
+	v := newLayoutViolation()
+	for _, seat := range seats {
+		if !seatInBounds(room, seat) {
+			v.OutOfBounds = append(v.OutOfBounds, seat.SeatCode)
+			v.SeatCodes = append(v.SeatCodes, seat.SeatCode)
+		}
+	}
+	return v, nil
+}
+
+// forceLayout은 배치 검사를 건너뛰는 ?force=true 요청인지 확인합니다.
+// 의도적으로 요소를 겹쳐 배치할 때 사용합니다.
+func forceLayout(r *http.Request) bool {
+	return r.URL.Query().Get("force") == "true"
+}
+
+// writeLayoutError는 배치 검사 실패를 409 problem 문서와 문제 seat_code 목록으로 응답합니다.
+func writeLayoutError(w http.ResponseWriter, v LayoutViolation) {
+	p := utils.NewProblem(consts.ERR_LAYOUT_CONFLICT,
+		fmt.Sprintf("좌석 배치가 겹치거나 room 영역을 벗어납니다: %v (의도한 배치라면 force=true로 요청하세요)", v.SeatCodes))
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(layoutErrorResponse{Problem: p, LayoutViolation: v})
+}
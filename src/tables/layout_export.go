@@ -0,0 +1,446 @@
+// layout_export.go
+package tables
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// LayoutDocument는 배치도 내보내기/가져오기 JSON 문서입니다.
+// 다른 지점으로 옮길 수 있도록 auto_increment와 company_code는 room/seat에 포함하지 않습니다.
+type LayoutDocument struct {
+	Version     int          `json:"version"`
+	ExportedAt  time.Time    `json:"exported_at"`
+	CompanyCode int          `json:"company_code"` // 내보낸 회사 (가져오기 시 무시)
+	Rooms       []LayoutRoom `json:"rooms"`
+}
+
+// LayoutRoom은 배치 문서의 room과 그 안의 seat입니다.
+type LayoutRoom struct {
+	RoomCode              int          `json:"room_code"`
+	RoomTitle             string       `json:"room_title"`
+	TitleBackgroundColor  string       `json:"title_background_color"`
+	TitleTextColor        string       `json:"title_text_color"`
+	RoomBackgroundColor   string       `json:"room_background_color"`
+	RoomTop               int          `json:"room_top"`
+	RoomLeft              int          `json:"room_left"`
+	RoomWidth             int          `json:"room_width"`
+	RoomHeight            int          `json:"room_height"`
+	Gender                int          `json:"gender"`
+	Waiting               int          `json:"waiting"`
+	Release               int          `json:"release"`
+	HideTitle             int          `json:"hide_title"`
+	TransparentBackground int          `json:"transparent_background"`
+	HideBorder            int          `json:"hide_border"`
+	KioskDisabled         int          `json:"kiosk_disabled"`
+	PowerControl          int          `json:"power_control"`
+	BreakerNumber         int          `json:"breaker_number"`
+	Seats                 []LayoutSeat `json:"seats"`
+}
+
+// LayoutSeat은 배치 문서의 seat입니다. room_code는 속한 LayoutRoom에서 정해집니다.
+type LayoutSeat struct {
+	SeatCode              int    `json:"seat_code"`
+	SeatTitle             string `json:"seat_title"`
+	TitleBackgroundColor  string `json:"title_background_color"`
+	TitleTextColor        string `json:"title_text_color"`
+	SeatBackgroundColor   string `json:"seat_background_color"`
+	SeatTop               int    `json:"seat_top"`
+	SeatLeft              int    `json:"seat_left"`
+	SeatWidth             int    `json:"seat_width"`
+	SeatHeight            int    `json:"seat_height"`
+	Gender                int    `json:"gender"`
+	Waiting               int    `json:"waiting"`
+	Release               int    `json:"release"`
+	HideTitle             int    `json:"hide_title"`
+	TransparentBackground int    `json:"transparent_background"`
+	HideBorder            int    `json:"hide_border"`
+	KioskDisabled         int    `json:"kiosk_disabled"`
+	PowerControl          int    `json:"power_control"`
+	BreakerNumber         int    `json:"breaker_number"`
+}
+
+func layoutRoomFrom(room Room) LayoutRoom {
+	return LayoutRoom{
+		RoomCode: room.RoomCode, RoomTitle: room.RoomTitle,
+		TitleBackgroundColor: room.TitleBackgroundColor, TitleTextColor: room.TitleTextColor,
+		RoomBackgroundColor: room.RoomBackgroundColor,
+		RoomTop:             room.RoomTop, RoomLeft: room.RoomLeft, RoomWidth: room.RoomWidth, RoomHeight: room.RoomHeight,
+		Gender: room.Gender, Waiting: room.Waiting, Release: room.Release, HideTitle: room.HideTitle,
+		TransparentBackground: room.TransparentBackground, HideBorder: room.HideBorder,
+		KioskDisabled: room.KioskDisabled, PowerControl: room.PowerControl, BreakerNumber: room.BreakerNumber,
+		Seats: []LayoutSeat{},
+	}
+}
+
+func (l LayoutRoom) room(companyCode int) Room {
+	return Room{
+		CompanyCode: companyCode, RoomCode: l.RoomCode, RoomTitle: l.RoomTitle,
+		TitleBackgroundColor: l.TitleBackgroundColor, TitleTextColor: l.TitleTextColor,
+		RoomBackgroundColor: l.RoomBackgroundColor,
+		RoomTop:             l.RoomTop, RoomLeft: l.RoomLeft, RoomWidth: l.RoomWidth, RoomHeight: l.RoomHeight,
+		Gender: l.Gender, Waiting: l.Waiting, Release: l.Release, HideTitle: l.HideTitle,
+		TransparentBackground: l.TransparentBackground, HideBorder: l.HideBorder,
+		KioskDisabled: l.KioskDisabled, PowerControl: l.PowerControl, BreakerNumber: l.BreakerNumber,
+	}
+}
+
+func layoutSeatFrom(seat Seat) LayoutSeat {
+	return LayoutSeat{
+		SeatCode: seat.SeatCode, SeatTitle: seat.SeatTitle,
+		TitleBackgroundColor: seat.TitleBackgroundColor, TitleTextColor: seat.TitleTextColor,
+		SeatBackgroundColor: seat.SeatBackgroundColor,
+		SeatTop:             seat.SeatTop, SeatLeft: seat.SeatLeft, SeatWidth: seat.SeatWidth, SeatHeight: seat.SeatHeight,
+		Gender: seat.Gender, Waiting: seat.Waiting, Release: seat.Release, HideTitle: seat.HideTitle,
+		TransparentBackground: seat.TransparentBackground, HideBorder: seat.HideBorder,
+		KioskDisabled: seat.KioskDisabled, PowerControl: seat.PowerControl, BreakerNumber: seat.BreakerNumber,
+	}
+}
+
+func (l LayoutSeat) seat(companyCode, roomCode int) Seat {
+	return Seat{
+		CompanyCode: companyCode, SeatCode: l.SeatCode, RoomCode: roomCode, SeatTitle: l.SeatTitle,
+		TitleBackgroundColor: l.TitleBackgroundColor, TitleTextColor: l.TitleTextColor,
+		SeatBackgroundColor: l.SeatBackgroundColor,
+		SeatTop:             l.SeatTop, SeatLeft: l.SeatLeft, SeatWidth: l.SeatWidth, SeatHeight: l.SeatHeight,
+		Gender: l.Gender, Waiting: l.Waiting, Release: l.Release, HideTitle: l.HideTitle,
+		TransparentBackground: l.TransparentBackground, HideBorder: l.HideBorder,
+		KioskDisabled: l.KioskDisabled, PowerControl: l.PowerControl, BreakerNumber: l.BreakerNumber,
+	}
+}
+
+// LayoutChange는 배치 가져오기로 바뀌는 room/seat 하나입니다.
+type LayoutChange struct {
+	Kind   string                       `json:"kind"`   // room, seat
+	Code   int                          `json:"code"`   // room_code 또는 seat_code
+	Action string                       `json:"action"` // create, update, delete
+	Fields map[string]LayoutFieldChange `json:"fields,omitempty"`
+}
+
+// LayoutFieldChange는 수정되는 필드의 이전 값과 새 값입니다.
+type LayoutFieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// LayoutImportResult는 배치 가져오기 응답 본문입니다.
+type LayoutImportResult struct {
+	DryRun  bool           `json:"dry_run"`
+	Applied bool           `json:"applied"`
+	Changes []LayoutChange `json:"changes"`
+	// Summary는 create, update, delete, unchanged별 개수입니다.
+	Summary map[string]int `json:"summary"`
+}
+
+// RegisterLayoutRoutes는 배치도 내보내기/가져오기/SVG 엔드포인트를 등록합니다.
+// 내보내기와 SVG는 점주/직원, 가져오기는 점주만 할 수 있습니다.
+func RegisterLayoutRoutes(r *mux.Router) {
+	r.Handle("/layout/export", allow(ExportLayout, staffRoles...)).Methods("GET")
+	r.Handle("/layout/import", allow(ImportLayout, ownerOnly...)).Methods("POST")
+	r.Handle("/layout/svg", allow(GetLayoutSVG, staffRoles...)).Methods("GET")
+}
+
+// loadLayout은 회사의 room과 seat을 배치 문서로 조회합니다.
+func loadLayout(ctx context.Context, companyCode int) (LayoutDocument, error) {
+	rooms, err := roomRepo.List(ctx, companyCode, RoomFilter{})
+	if err != nil {
+		return LayoutDocument{}, err
+	}
+	seats, err := seatRepo.List(ctx, companyCode, SeatFilter{})
+	if err != nil {
+		return LayoutDocument{}, err
+	}
+
+	doc := LayoutDocument{
+		Version:     consts.LAYOUT_FORMAT_VERSION,
+		ExportedAt:  time.Now(),
+		CompanyCode: companyCode,
+		Rooms:       make([]LayoutRoom, 0, len(rooms)),
+	}
+	index := make(map[int]int, len(rooms))
+	for i, room := range rooms {
+		index[room.RoomCode] = i
+		doc.Rooms = append(doc.Rooms, layoutRoomFrom(room))
+	}
+	for _, seat := range seats {
+		// room이 없는 seat은 배치도에 그릴 수 없으므로 제외합니다.
+		if i, ok := index[seat.RoomCode]; ok {
+			doc.Rooms[i].Seats = append(doc.Rooms[i].Seats, layoutSeatFrom(seat))
+		}
+	}
+	return doc, nil
+}
+
+// ExportLayout: 회사의 모든 room과 seat을 버전이 붙은 JSON 문서로 내보냅니다.
+func ExportLayout(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	companyCode := utils.CompanyCode(r.Context())
+	doc, err := loadLayout(ctx, companyCode)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="layout-%d-%s.json"`, companyCode, doc.ExportedAt.Format("20060102")))
+	json.NewEncoder(w).Encode(doc)
+}
+
+// diffFields는 a와 b의 JSON 필드 중 값이 다른 필드를 반환합니다. (auto_increment, company_code 제외)
+func diffFields(a, b interface{}) (map[string]LayoutFieldChange, error) {
+	toMap := func(v interface{}) (map[string]interface{}, error) {
+		body, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]interface{}
+		err = json.Unmarshal(body, &m)
+		return m, err
+	}
+	from, err := toMap(a)
+	if err != nil {
+		return nil, err
+	}
+	to, err := toMap(b)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]LayoutFieldChange)
+	for key, value := range to {
+		if key == "auto_increment" || key == "company_code" || key == "version" {
+			continue
+		}
+		if !reflect.DeepEqual(from[key], value) {
+			fields[key] = LayoutFieldChange{From: from[key], To: value}
+		}
+	}
+	return fields, nil
+}
+
+// ImportLayout: 배치 문서를 호출자의 회사에 반영합니다.
+// room/seat은 코드 기준으로 없으면 생성하고 있으면 수정하며, prune=true이면 문서에 없는 room/seat을 삭제합니다.
+// 삭제할 seat 중 사용 중이거나 예약/고정석 이용권이 있는 것이 있으면 아무것도 반영하지 않고 409를 반환합니다.
+// dry_run=true이면 반영하지 않고 변경 내역만 반환합니다. 배치 검사는 force=true로 건너뛸 수 있습니다.
+func ImportLayout(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.LONG_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	companyCode := utils.CompanyCode(r.Context())
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	prune := r.URL.Query().Get("prune") == "true"
+
+	var doc LayoutDocument
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if doc.Version != consts.LAYOUT_FORMAT_VERSION {
+		writeProblem(w, consts.ERR_INVALID_BODY, fmt.Sprintf("지원하지 않는 배치 문서 버전입니다: %d", doc.Version))
+		return
+	}
+
+	// 문서의 room/seat을 회사 기준으로 변환하며 코드 중복과 필드 값을 확인합니다.
+	// 필드 값 오류는 "rooms[번호].seats[번호].필드" 형식으로 모아서 한 번에 응답합니다.
+	docRooms := make(map[int]Room, len(doc.Rooms))
+	docSeats := make(map[int]Seat)
+	seatsByRoom := make(map[int][]Seat, len(doc.Rooms))
+	errs := make(fieldErrors)
+	for i, lr := range doc.Rooms {
+		if _, ok := docRooms[lr.RoomCode]; ok {
+			writeProblem(w, consts.ERR_DUPLICATE_CODE, fmt.Sprintf("문서에 중복된 room_code가 있습니다: %d", lr.RoomCode))
+			return
+		}
+		room := lr.room(companyCode)
+		errs.merge(fmt.Sprintf("rooms[%d].", i), validateRoom(room))
+		docRooms[room.RoomCode] = room
+		for j, ls := range lr.Seats {
+			if _, ok := docSeats[ls.SeatCode]; ok {
+				writeProblem(w, consts.ERR_DUPLICATE_CODE, fmt.Sprintf("문서에 중복된 seat_code가 있습니다: %d", ls.SeatCode))
+				return
+			}
+			seat := ls.seat(companyCode, room.RoomCode)
+			errs.merge(fmt.Sprintf("rooms[%d].seats[%d].", i, j), validateSeat(seat))
+			docSeats[seat.SeatCode] = seat
+			seatsByRoom[room.RoomCode] = append(seatsByRoom[room.RoomCode], seat)
+		}
+	}
+	if len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+
+	currentRooms, err := roomRepo.List(ctx, companyCode, RoomFilter{})
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	currentSeats, err := seatRepo.List(ctx, companyCode, SeatFilter{})
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// 현재 상태와 비교하여 변경 내역을 만듭니다.
+	result := LayoutImportResult{
+		DryRun:  dryRun,
+		Changes: []LayoutChange{},
+		Summary: map[string]int{"create": 0, "update": 0, "delete": 0, "unchanged": 0},
+	}
+	var changes LayoutChanges
+	record := func(change LayoutChange) {
+		result.Changes = append(result.Changes, change)
+		result.Summary[change.Action]++
+	}
+
+	currentRoomByCode := make(map[int]Room, len(currentRooms))
+	for _, room := range currentRooms {
+		currentRoomByCode[room.RoomCode] = room
+	}
+	currentSeatByCode := make(map[int]Seat, len(currentSeats))
+	for _, seat := range currentSeats {
+		currentSeatByCode[seat.SeatCode] = seat
+	}
+
+	for _, lr := range doc.Rooms {
+		room := docRooms[lr.RoomCode]
+		current, ok := currentRoomByCode[room.RoomCode]
+		if !ok {
+			record(LayoutChange{Kind: "room", Code: room.RoomCode, Action: "create"})
+			changes.Rooms = append(changes.Rooms, room)
+			continue
+		}
+		fields, err := diffFields(current, room)
+		if err != nil {
+			writeInternalError(w, err)
+			return
+		}
+		if len(fields) == 0 {
+			result.Summary["unchanged"]++
+			continue
+		}
+		record(LayoutChange{Kind: "room", Code: room.RoomCode, Action: "update", Fields: fields})
+		changes.Rooms = append(changes.Rooms, room)
+	}
+	for _, lr := range doc.Rooms {
+		for _, ls := range lr.Seats {
+			seat := docSeats[ls.SeatCode]
+			current, ok := currentSeatByCode[seat.SeatCode]
+			if !ok {
+				record(LayoutChange{Kind: "seat", Code: seat.SeatCode, Action: "create"})
+				changes.Seats = append(changes.Seats, seat)
+				continue
+			}
+			fields, err := diffFields(current, seat)
+			if err != nil {
+				writeInternalError(w, err)
+				return
+			}
+			if len(fields) == 0 {
+				result.Summary["unchanged"]++
+				continue
+			}
+			record(LayoutChange{Kind: "seat", Code: seat.SeatCode, Action: "update", Fields: fields})
+			changes.Seats = append(changes.Seats, seat)
+		}
+	}
+	deletedSeatCount := make(map[int]int)
+	if prune {
+		for _, seat := range currentSeats {
+			if _, ok := docSeats[seat.SeatCode]; !ok {
+				record(LayoutChange{Kind: "seat", Code: seat.SeatCode, Action: "delete"})
+				changes.DeleteSeats = append(changes.DeleteSeats, seat.SeatCode)
+				deletedSeatCount[seat.RoomCode]++
+			}
+		}
+		for _, room := range currentRooms {
+			if _, ok := docRooms[room.RoomCode]; !ok {
+				record(LayoutChange{Kind: "room", Code: room.RoomCode, Action: "delete"})
+				changes.DeleteRooms = append(changes.DeleteRooms, room.RoomCode)
+			}
+		}
+	}
+
+	// 문서의 room마다 seat 배치를 검사합니다. 문서에 없는 기존 seat은 prune하지 않으면 그대로 남으므로 함께 검사합니다.
+	if !forceLayout(r) {
+		violation := newLayoutViolation()
+		roomCodes := make([]int, 0, len(docRooms))
+		for code := range docRooms {
+			roomCodes = append(roomCodes, code)
+		}
+		sort.Ints(roomCodes)
+		for _, code := range roomCodes {
+			var existing []Seat
+			if !prune {
+				for _, seat := range currentSeats {
+					if _, ok := docSeats[seat.SeatCode]; !ok && seat.RoomCode == code {
+						existing = append(existing, seat)
+					}
+				}
+			}
+			v := validateLayout(docRooms[code], existing, seatsByRoom[code])
+			violation.SeatCodes = append(violation.SeatCodes, v.SeatCodes...)
+			violation.OutOfBounds = append(violation.OutOfBounds, v.OutOfBounds...)
+			violation.Overlaps = append(violation.Overlaps, v.Overlaps...)
+		}
+		if !violation.Empty() {
+			sort.Ints(violation.SeatCodes)
+			writeLayoutError(w, violation)
+			return
+		}
+	}
+
+	if !dryRun && len(result.Changes) > 0 {
+		if err := roomRepo.ApplyLayout(ctx, companyCode, changes); err != nil {
+			log.Printf("배치 가져오기 오류: %v", err)
+			writeRepoError(w, err, "이미 존재하는 코드입니다")
+			return
+		}
+		result.Applied = true
+		log.Printf("배치 가져오기: company_code=%d %v", companyCode, result.Summary)
+		enqueueLayoutEvents(companyCode, result.Changes, docSeats, currentSeatByCode, deletedSeatCount)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// enqueueLayoutEvents는 배치 가져오기로 바뀐 room/seat마다 상태 변경 알림을 큐에 넣습니다.
+func enqueueLayoutEvents(companyCode int, changes []LayoutChange, docSeats, currentSeats map[int]Seat, deletedSeatCount map[int]int) {
+	eventNames := map[string]map[string]string{
+		"room": {"create": "RoomCreated", "update": "RoomUpdated", "delete": "RoomDeleted"},
+		"seat": {"create": "SeatCreated", "update": "SeatUpdated", "delete": "SeatDeleted"},
+	}
+	for _, change := range changes {
+		data := map[string]interface{}{"company_code": companyCode}
+		if change.Kind == "room" {
+			data["room_code"] = change.Code
+			if change.Action == "delete" {
+				data["seat_count"] = deletedSeatCount[change.Code]
+			}
+		} else {
+			data["seat_code"] = change.Code
+			if seat, ok := docSeats[change.Code]; ok {
+				data["room_code"] = seat.RoomCode
+			} else {
+				data["room_code"] = currentSeats[change.Code].RoomCode
+			}
+		}
+		enqueueEvent(eventNames[change.Kind][change.Action], data)
+	}
+}
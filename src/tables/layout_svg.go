@@ -0,0 +1,120 @@
+// layout_svg.go
+package tables
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"time"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// svgTitleHeight는 room/seat 제목 띠의 높이(px)입니다.
+const svgTitleHeight = 20
+
+// svgColor는 색상 값을 SVG 속성에 넣을 수 있도록 이스케이프하며, 비어 있으면 fallback을 사용합니다.
+func svgColor(color, fallback string) string {
+	if color == "" {
+		color = fallback
+	}
+	return html.EscapeString(color)
+}
+
+// svgBox는 room 또는 seat 하나를 그립니다.
+// transparent_background이면 배경을, hide_border이면 테두리를, hide_title이면 제목 띠를 그리지 않습니다.
+func svgBox(buf *bytes.Buffer, class string, x, y, width, height int, title, background, titleBackground, titleText string,
+	hideTitle, transparentBackground, hideBorder int) {
+	fill := svgColor(background, "#FFFFFF")
+	if transparentBackground == 1 {
+		fill = "none"
+	}
+	stroke := `stroke="#333333" stroke-width="1"`
+	if hideBorder == 1 {
+		stroke = `stroke="none"`
+	}
+	fmt.Fprintf(buf, `<g class="%s">`+"\n", class)
+	fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" %s/>`+"\n",
+		x, y, width, height, fill, stroke)
+	if hideTitle != 1 {
+		titleHeight := svgTitleHeight
+		if height < titleHeight {
+			titleHeight = height
+		}
+		fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+			x, y, width, titleHeight, svgColor(titleBackground, "#000000"))
+		fmt.Fprintf(buf, `<text x="%d" y="%d" fill="%s" font-size="12" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+			x+width/2, y+titleHeight/2, svgColor(titleText, "#FFFFFF"), html.EscapeString(title))
+	}
+	buf.WriteString("</g>\n")
+}
+
+// renderLayoutSVG는 배치 문서를 인쇄용 SVG로 그립니다.
+// room은 room_top/room_left 위치에, seat은 속한 room 기준 상대 위치에 그립니다.
+func renderLayoutSVG(doc LayoutDocument) []byte {
+	width, height := 0, 0
+	for _, room := range doc.Rooms {
+		if right := room.RoomLeft + room.RoomWidth; right > width {
+			width = right
+		}
+		if bottom := room.RoomTop + room.RoomHeight; bottom > height {
+			height = bottom
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	for _, room := range doc.Rooms {
+		svgBox(&buf, "room", room.RoomLeft, room.RoomTop, room.RoomWidth, room.RoomHeight,
+			room.RoomTitle, room.RoomBackgroundColor, room.TitleBackgroundColor, room.TitleTextColor,
+			room.HideTitle, room.TransparentBackground, room.HideBorder)
+		for _, seat := range room.Seats {
+			svgBox(&buf, "seat", room.RoomLeft+seat.SeatLeft, room.RoomTop+seat.SeatTop, seat.SeatWidth, seat.SeatHeight,
+				seat.SeatTitle, seat.SeatBackgroundColor, seat.TitleBackgroundColor, seat.TitleTextColor,
+				seat.HideTitle, seat.TransparentBackground, seat.HideBorder)
+		}
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// GetLayoutSVG: 회사의 배치도를 SVG로 반환합니다. room_code 쿼리로 room 하나만 그릴 수 있습니다.
+func GetLayoutSVG(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	doc, err := loadLayout(ctx, utils.CompanyCode(r.Context()))
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if v := r.URL.Query().Get("room_code"); v != "" {
+		roomCode, err := strconv.Atoi(v)
+		if err != nil {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+			return
+		}
+		var rooms []LayoutRoom
+		for _, room := range doc.Rooms {
+			if room.RoomCode == roomCode {
+				// room 하나만 그릴 때는 왼쪽 위에 붙여 그립니다.
+				room.RoomTop, room.RoomLeft = 0, 0
+				rooms = append(rooms, room)
+			}
+		}
+		if len(rooms) == 0 {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Room을 찾을 수 없습니다.")
+			return
+		}
+		doc.Rooms = rooms
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(renderLayoutSVG(doc))
+}
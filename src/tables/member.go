@@ -0,0 +1,491 @@
+// member.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// Member 구조체는 member_table의 각 컬럼을 매핑합니다.
+type Member struct {
+	MemberID    int       `json:"member_id" db:"member_id"`
+	CompanyCode int       `json:"company_code"`
+	MemberName  string    `json:"member_name"`
+	Phone       string    `json:"phone"`
+	Gender      int       `json:"gender"`
+	Status      int       `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RegisterMemberRoutes는 member_table 관련 엔드포인트를 등록합니다.
+// 회원 관리는 점주/직원이 하며, 회원은 본인 정보만 조회할 수 있습니다.
+func RegisterMemberRoutes(r *mux.Router) {
+	r.Handle("/members", allow(GetMembers, staffRoles...)).Methods("GET")
+	r.Handle("/members/{member_id}", allow(GetMember, memberRoles...)).Methods("GET")
+	r.Handle("/members", allow(CreateMember, staffRoles...)).Methods("POST")
+	// UpdateMember는 전체/부분 업데이트를 모두 지원합니다.
+	r.Handle("/members/{member_id}", allow(UpdateMember, staffRoles...)).Methods("PUT")
+	r.Handle("/members/{member_id}", allow(DeleteMember, ownerOnly...)).Methods("DELETE")
+	r.Handle("/members/{member_id}/password", allow(SetMemberPassword, staffRoles...)).Methods("PUT")
+}
+
+// maskedMember는 로그 출력용으로 전화번호를 가린 사본을 반환합니다.
+func maskedMember(m Member) Member {
+	m.Phone = utils.MaskPhoneNumber(m.Phone)
+	return m
+}
+
+// GetMembers: "X-Fields" 헤더에 지정된 필드만 조회하거나 전체 필드를 조회합니다.
+// URL 쿼리 파라미터를 통해 필터링 기능도 지원합니다.
+func GetMembers(w http.ResponseWriter, r *http.Request) {
+	// 요청 컨텍스트에 10초 타임아웃 설정
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	allowedFields := []string{
+		"member_id", "company_code", "member_name", "phone",
+		"gender", "status", "created_at",
+	}
+
+	// 필드 선택 처리
+	fieldsHeader := r.Header.Get("X-Fields")
+	var fields []string
+	if fieldsHeader != "" {
+		requested := strings.Split(fieldsHeader, ",")
+		allowedSet := make(map[string]bool)
+		for _, f := range allowedFields {
+			allowedSet[f] = true
+		}
+		for _, f := range requested {
+			f = strings.TrimSpace(f)
+			if allowedSet[f] {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) == 0 {
+			fields = allowedFields
+		}
+	} else {
+		fields = allowedFields
+	}
+
+	// 필터링 조건 처리 (호출자의 company_code에 속한 member만 조회합니다)
+	filters := []string{"company_code = $1"}
+	args := []interface{}{utils.CompanyCode(r.Context())}
+	paramIdx := 2
+
+	// 지원하는 필터 파라미터 목록
+	filterParams := map[string]string{
+		"member_id": "member_id",
+		"gender":    "gender",
+		"status":    "status",
+		"phone":     "phone",
+	}
+
+	// URL 쿼리 파라미터에서 필터 조건 추출
+	for param, dbField := range filterParams {
+		if value := r.URL.Query().Get(param); value != "" {
+			filters = append(filters, fmt.Sprintf("%s = $%d", dbField, paramIdx))
+			args = append(args, value)
+			paramIdx++
+		}
+	}
+
+	// 검색 기능 추가 (member_name, phone에 대한 부분 검색)
+	if search := r.URL.Query().Get("search"); search != "" {
+		filters = append(filters, fmt.Sprintf("(member_name LIKE $%d OR phone LIKE $%d)", paramIdx, paramIdx))
+		args = append(args, "%"+search+"%")
+		paramIdx++
+	}
+
+	// 쿼리 구성
+	query := "SELECT " + strings.Join(fields, ", ") + " FROM member_table"
+	if len(filters) > 0 {
+		query += " WHERE " + strings.Join(filters, " AND ")
+	}
+
+	// 정렬 옵션 처리
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		direction := "ASC"
+		if strings.HasPrefix(sort, "-") {
+			sort = sort[1:]
+			direction = "DESC"
+		}
+
+		// 허용된 정렬 필드인지 확인
+		allowedSortFields := map[string]bool{
+			"member_id":   true,
+			"member_name": true,
+			"created_at":  true,
+		}
+
+		if allowedSortFields[sort] {
+			query += fmt.Sprintf(" ORDER BY %s %s", sort, direction)
+		}
+	} else {
+		// 기본 정렬은 member_id 기준
+		query += " ORDER BY member_id ASC"
+	}
+
+	// 로깅 추가 (인자에 전화번호가 포함될 수 있어 개수만 기록)
+	log.Printf("실행 쿼리: %s, 인자 수: %d", query, len(args))
+
+	// 쿼리 실행
+	rows, err := utils.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	// 결과 처리
+	columns, err := rows.Columns()
+	if err != nil {
+		log.Printf("컬럼 정보 조회 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+		return
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+
+		rowMap := make(map[string]interface{})
+		for i, col := range columns {
+			var v interface{}
+			val := values[i]
+			if b, ok := val.([]byte); ok {
+				v = string(b)
+			} else {
+				v = val
+			}
+			rowMap[col] = v
+		}
+		result = append(result, rowMap)
+	}
+
+	// 결과 반환
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("JSON 인코딩 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "응답 생성 중 오류가 발생했습니다")
+		return
+	}
+}
+
+// GetMember: 단일 member를 전체 필드로 조회합니다.
+func GetMember(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["member_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 member_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+	// 회원은 본인 정보만 조회할 수 있습니다.
+	if !bindCallerMember(w, r, &memberID) {
+		return
+	}
+
+	member, err := getMember(ctx, companyCode, memberID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Member를 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
+
+// CreateMember: 새로운 member를 생성합니다.
+func CreateMember(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var member Member
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	// member는 항상 호출자의 company_code로 생성합니다.
+	member.CompanyCode = utils.CompanyCode(r.Context())
+	member.MemberName = strings.TrimSpace(member.MemberName)
+	if member.MemberName == "" {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "member_name이 필요합니다.")
+		return
+	}
+	if !validGender(member.Gender) {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "잘못된 gender 값")
+		return
+	}
+
+	// 시작 시간 로깅 (전화번호는 가려서 기록)
+	startTime := time.Now()
+	log.Printf("Member 생성 요청 시작: %+v", maskedMember(member))
+
+	err := utils.DB.QueryRowContext(ctx, `
+		INSERT INTO member_table
+		(company_code, member_name, phone, gender, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING member_id, created_at`,
+		member.CompanyCode, member.MemberName, member.Phone, member.Gender, member.Status).
+		Scan(&member.MemberID, &member.CreatedAt)
+
+	// 실행 시간 및 오류 로깅
+	duration := time.Since(startTime)
+	log.Printf("쿼리 실행 시간: %v", duration)
+
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		if isPQError(err, "23505") { // unique_violation (member_company_phone_idx)
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "이미 등록된 전화번호입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(member)
+}
+
+// UpdateMember: 제공된 JSON 데이터에 따라 전체 또는 일부 필드만 업데이트합니다.
+func UpdateMember(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["member_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 member_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	// 요청 본문을 map[string]interface{}로 디코딩하여, 제공된 필드만 업데이트합니다.
+	var updateData map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	// JSON에 "member_id"가 있다면 URL과 일치하는지 확인 후 제거합니다.
+	if v, ok := updateData["member_id"]; ok {
+		switch v := v.(type) {
+		case float64:
+			if int(v) != memberID {
+				writeProblem(w, consts.ERR_INVALID_REQUEST, "URL과 body의 member_id가 다릅니다.")
+				return
+			}
+		default:
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "잘못된 member_id 값")
+			return
+		}
+		delete(updateData, "member_id")
+	}
+	// company_code는 호출자의 테넌트로 고정되며 변경할 수 없습니다.
+	if v, ok := updateData["company_code"]; ok {
+		if code, ok := v.(float64); !ok || int(code) != companyCode {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "company_code는 변경할 수 없습니다.")
+			return
+		}
+		delete(updateData, "company_code")
+	}
+	if len(updateData) == 0 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "업데이트할 필드가 없습니다.")
+		return
+	}
+	if v, ok := updateData["gender"]; ok {
+		gender, ok := v.(float64)
+		if !ok || gender != float64(int(gender)) || !validGender(int(gender)) {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "잘못된 gender 값")
+			return
+		}
+	}
+
+	allowed := map[string]bool{
+		"member_name": true,
+		"phone":       true,
+		"gender":      true,
+		"status":      true,
+	}
+	updates := []string{}
+	args := []interface{}{}
+	idx := 1
+	for key, value := range updateData {
+		if !allowed[key] {
+			continue
+		}
+		updates = append(updates, key+" = $"+strconv.Itoa(idx))
+		args = append(args, value)
+		idx++
+	}
+	if len(updates) == 0 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "유효한 업데이트 필드가 없습니다.")
+		return
+	}
+
+	query := "UPDATE member_table SET " + strings.Join(updates, ", ") +
+		" WHERE member_id = $" + strconv.Itoa(idx) + " AND company_code = $" + strconv.Itoa(idx+1)
+	args = append(args, memberID, companyCode)
+	res, err := utils.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		if isPQError(err, "23505") { // unique_violation (member_company_phone_idx)
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "이미 등록된 전화번호입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Member를 찾을 수 없습니다.")
+		return
+	}
+
+	// 업데이트된 member를 조회하여 반환합니다.
+	member, err := getMember(ctx, companyCode, memberID)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	log.Printf("Member 업데이트 완료: %+v", maskedMember(member))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
+
+// DeleteMember: member를 삭제합니다.
+func DeleteMember(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["member_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 member_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+	res, err := utils.DB.ExecContext(ctx,
+		"DELETE FROM member_table WHERE member_id = $1 AND company_code = $2", memberID, companyCode)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Member를 찾을 수 없습니다.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setMemberPasswordRequest는 회원 비밀번호 설정 요청 본문입니다.
+type setMemberPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// SetMemberPassword: 회원 로그인(/auth/member/login)에 사용할 비밀번호를 설정합니다.
+func SetMemberPassword(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["member_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 member_id")
+		return
+	}
+
+	var req setMemberPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if len(req.Password) < 4 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "password는 4자 이상이어야 합니다.")
+		return
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	res, err := utils.DB.ExecContext(ctx,
+		"UPDATE member_table SET password_hash = $1 WHERE member_id = $2 AND company_code = $3",
+		string(hash), memberID, utils.CompanyCode(r.Context()))
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Member를 찾을 수 없습니다.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getMember는 companyCode에 속한 단일 member를 member_id로 조회합니다.
+// 존재하지 않거나 다른 회사의 member이면 sql.ErrNoRows를 반환합니다.
+func getMember(ctx context.Context, companyCode, memberID int) (Member, error) {
+	var member Member
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT member_id, company_code, member_name, phone,
+		       gender, status, created_at
+		FROM member_table WHERE member_id = $1 AND company_code = $2`, memberID, companyCode).
+		Scan(&member.MemberID, &member.CompanyCode, &member.MemberName, &member.Phone,
+			&member.Gender, &member.Status, &member.CreatedAt)
+	return member, err
+}
+
+// authenticateMember는 회사/전화번호/비밀번호로 정상 이용 중인 회원을 확인하고 member_id를 반환합니다.
+// 회원이 없거나 비밀번호가 설정되지 않았거나 틀리면 sql.ErrNoRows를 반환합니다.
+func authenticateMember(ctx context.Context, companyCode int, phone, password string) (int, error) {
+	if phone == "" {
+		return 0, sql.ErrNoRows
+	}
+	var memberID int
+	var hash string
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT member_id, password_hash FROM member_table
+		WHERE company_code = $1 AND phone = $2 AND status = $3 AND password_hash <> ''`,
+		companyCode, phone, consts.MEMBER_STATUS_ACTIVE).Scan(&memberID, &hash)
+	if err != nil {
+		return 0, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return 0, sql.ErrNoRows
+	}
+	return memberID, nil
+}
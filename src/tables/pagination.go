@@ -0,0 +1,113 @@
+// pagination.go
+package tables
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"AllinB/src/consts"
+)
+
+// Cursor는 키셋 페이지의 위치입니다. 마지막으로 받은 항목의 정렬 컬럼 값과 auto_increment를 담습니다.
+// 클라이언트에는 base64로 인코딩한 불투명한 문자열로 전달합니다.
+type Cursor struct {
+	Sort string `json:"s"`  // 커서를 만든 정렬 (예: "seat_code", "-seat_title")
+	Key  string `json:"k"`  // 정렬 컬럼 값
+	ID   int    `json:"id"` // auto_increment (정렬 값이 같을 때 순서를 정합니다)
+}
+
+// Page는 목록 조회의 페이지 조건입니다.
+type Page struct {
+	// Limit는 최대 항목 수이며, 0이면 제한하지 않습니다.
+	Limit int
+	// After가 있으면 그 위치 다음 항목부터 조회합니다.
+	After *Cursor
+}
+
+// errInvalidCursor는 cursor 파라미터를 해석할 수 없을 때 반환됩니다.
+var errInvalidCursor = errors.New("잘못된 cursor")
+
+func encodeCursor(c Cursor) string {
+	body, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+func decodeCursor(s string) (*Cursor, error) {
+	body, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(body, &c); err != nil {
+		return nil, errInvalidCursor
+	}
+	// auto_increment는 양수인 INTEGER이므로 범위를 벗어난 ID는 DB 오류 대신 잘못된 cursor로 거부합니다.
+	if c.ID <= 0 || c.ID > math.MaxInt32 {
+		return nil, errInvalidCursor
+	}
+	return &c, nil
+}
+
+// intKey는 정수 컬럼(room_code, seat_code, auto_increment) 정렬로 만든 cursor의 정렬 값을 해석합니다.
+// 정수 컬럼은 INTEGER이므로 int32 범위를 넘는 값은 DB 오류 대신 잘못된 cursor로 거부합니다.
+func (c *Cursor) intKey() (int, error) {
+	n, err := strconv.ParseInt(c.Key, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidValue, errInvalidCursor)
+	}
+	return int(n), nil
+}
+
+// parsePage는 limit, cursor 쿼리 파라미터를 읽습니다.
+// 둘 다 없으면 paged가 false이며, 기존 클라이언트처럼 전체 목록을 배열로 응답해야 합니다.
+// sort는 현재 요청의 정렬이며, 다른 정렬로 만든 cursor는 거부합니다.
+func parsePage(r *http.Request, sort string) (page Page, paged bool, err error) {
+	limitParam := r.URL.Query().Get("limit")
+	cursorParam := r.URL.Query().Get("cursor")
+	if limitParam == "" && cursorParam == "" {
+		return Page{}, false, nil
+	}
+
+	page.Limit = consts.PAGE_LIMIT_DEFAULT
+	if limitParam != "" {
+		page.Limit, err = strconv.Atoi(limitParam)
+		if err != nil || page.Limit <= 0 {
+			return Page{}, true, errors.New("잘못된 limit 값")
+		}
+		if page.Limit > consts.PAGE_LIMIT_MAX {
+			page.Limit = consts.PAGE_LIMIT_MAX
+		}
+	}
+	if cursorParam != "" {
+		page.After, err = decodeCursor(cursorParam)
+		if err != nil {
+			return Page{}, true, err
+		}
+		if page.After.Sort != sort {
+			return Page{}, true, errors.New("cursor와 sort가 다릅니다")
+		}
+	}
+	return page, true, nil
+}
+
+// pageResponse는 limit/cursor를 보낸 요청의 목록 응답 본문입니다.
+// next_cursor가 null이면 마지막 페이지입니다.
+type pageResponse struct {
+	Items      interface{} `json:"items"`
+	NextCursor *string     `json:"next_cursor"`
+}
+
+// includeTotal은 X-Total-Count 헤더를 요청했는지 확인합니다. (?include_total=true)
+func includeTotal(r *http.Request) bool {
+	return r.URL.Query().Get("include_total") == "true"
+}
+
+// setTotalCount는 페이지와 관계없이 조건에 맞는 전체 항목 수를 헤더로 알립니다.
+func setTotalCount(w http.ResponseWriter, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+}
@@ -0,0 +1,189 @@
+package tables
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"AllinB/src/consts"
+)
+
+func TestParsePage(t *testing.T) {
+	valid := encodeCursor(Cursor{Sort: "seat_code", Key: "3", ID: 5})
+	tests := []struct {
+		name      string
+		query     string
+		wantPaged bool
+		wantLimit int
+		wantAfter *Cursor
+		wantErr   bool
+	}{
+		{"페이지 요청 아님", "", false, 0, nil, false},
+		{"limit 생략", "cursor=" + valid, true, consts.PAGE_LIMIT_DEFAULT, &Cursor{Sort: "seat_code", Key: "3", ID: 5}, false},
+		{"limit 1", "limit=1", true, 1, nil, false},
+		{"limit 최댓값", fmt.Sprintf("limit=%d", consts.PAGE_LIMIT_MAX), true, consts.PAGE_LIMIT_MAX, nil, false},
+		{"limit 최댓값 초과는 최댓값으로", fmt.Sprintf("limit=%d", consts.PAGE_LIMIT_MAX+1), true, consts.PAGE_LIMIT_MAX, nil, false},
+		{"limit 0", "limit=0", true, 0, nil, true},
+		{"음수 limit", "limit=-1", true, 0, nil, true},
+		{"숫자가 아닌 limit", "limit=ten", true, 0, nil, true},
+		{"base64가 아닌 cursor", "cursor=%21%21%21", true, 0, nil, true},
+		{"JSON이 아닌 cursor", "cursor=" + base64.RawURLEncoding.EncodeToString([]byte("seat_code:3")), true, 0, nil, true},
+		{"ID가 0인 cursor", "cursor=" + encodeCursor(Cursor{Sort: "seat_code", Key: "3", ID: 0}), true, 0, nil, true},
+		{"음수 ID cursor", "cursor=" + encodeCursor(Cursor{Sort: "seat_code", Key: "3", ID: -1}), true, 0, nil, true},
+		{"int32 범위를 넘는 ID cursor", "cursor=" + encodeCursor(Cursor{Sort: "seat_code", Key: "3", ID: math.MaxInt32 + 1}), true, 0, nil, true},
+		{"다른 sort로 만든 cursor", "cursor=" + encodeCursor(Cursor{Sort: "-seat_code", Key: "3", ID: 5}), true, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/seats?"+tt.query, nil)
+			page, paged, err := parsePage(r, "seat_code")
+			if paged != tt.wantPaged {
+				t.Fatalf("paged = %v, want %v", paged, tt.wantPaged)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("오류가 없습니다")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if page.Limit != tt.wantLimit || !reflect.DeepEqual(page.After, tt.wantAfter) {
+				t.Errorf("page = {%d %+v}, want {%d %+v}", page.Limit, page.After, tt.wantLimit, tt.wantAfter)
+			}
+		})
+	}
+}
+
+// listSeatPages는 next_cursor가 null이 될 때까지 limit개씩 GET /seats를 요청해 seat_code를 순서대로 모읍니다.
+func listSeatPages(t *testing.T, h http.Handler, query string, limit int) []int {
+	t.Helper()
+	var codes []int
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 20 {
+			t.Fatal("페이지가 끝나지 않습니다")
+		}
+		target := fmt.Sprintf("/seats?limit=%d&%s", limit, query)
+		if cursor != "" {
+			target += "&cursor=" + cursor
+		}
+		rec := mustServe(t, h, testRequest{Method: "GET", Target: target}, http.StatusOK)
+		var page struct {
+			Items      []Seat  `json:"items"`
+			NextCursor *string `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		for _, seat := range page.Items {
+			codes = append(codes, seat.SeatCode)
+		}
+		if page.NextCursor == nil {
+			return codes
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+func TestSeatPagesStableOrder(t *testing.T) {
+	r := newSeatTestRouter(t)
+	// seat_title이 같은 seat가 여러 개 있어도 auto_increment(생성 순서)로 순서가 정해집니다.
+	titles := []string{"B", "A", "B", "A", "B", "C", "A"}
+	for i, title := range titles {
+		body := fmt.Sprintf(`{"seat_code": %d, "seat_title": %q, "seat_left": %d, "seat_width": 10}`, 10-i, title, i*10)
+		mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: body}, http.StatusCreated)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{"seat_code", "", []int{4, 5, 6, 7, 8, 9, 10}},
+		{"seat_title", "sort=seat_title", []int{9, 7, 4, 10, 8, 6, 5}},
+		{"seat_title 역순", "sort=-seat_title", []int{5, 6, 8, 10, 4, 7, 9}},
+		{"필터와 함께", "sort=seat_title&seat_title=B", []int{10, 8, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, limit := range []int{1, 2, 3, len(titles), consts.PAGE_LIMIT_MAX} {
+				if got := listSeatPages(t, r, tt.query, limit); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("limit=%d: seat_code = %v, want %v", limit, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListTamperedCursor(t *testing.T) {
+	r := newSeatTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 1}`}, http.StatusCreated)
+
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{"seat 정수 아닌 key", "/seats?cursor=" + encodeCursor(Cursor{Sort: "seat_code", Key: "1 OR 1=1", ID: 1})},
+		{"seat int32 초과 key", "/seats?cursor=" + encodeCursor(Cursor{Sort: "seat_code", Key: "2147483648", ID: 1})},
+		{"seat sort 불일치", "/seats?sort=seat_title&cursor=" + encodeCursor(Cursor{Sort: "seat_code", Key: "1", ID: 1})},
+		{"seat 잘린 cursor", "/seats?cursor=" + encodeCursor(Cursor{Sort: "seat_code", Key: "1", ID: 1})[:10]},
+		{"room 정수 아닌 key", "/rooms?cursor=" + encodeCursor(Cursor{Sort: "room_code", Key: "x", ID: 1})},
+		{"room 다른 sort", "/rooms?cursor=" + encodeCursor(Cursor{Sort: "room_title", Key: "1", ID: 1})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := mustServe(t, r, testRequest{Method: "GET", Target: tt.target}, http.StatusBadRequest)
+			if code := problemCode(t, rec); code != consts.ERR_INVALID_PARAMETER {
+				t.Fatalf("code = %s, want %s", code, consts.ERR_INVALID_PARAMETER)
+			}
+		})
+	}
+}
+
+func TestListLimitBounds(t *testing.T) {
+	r := newSeatTestRouter(t)
+	for i := 1; i <= 3; i++ {
+		body := fmt.Sprintf(`{"seat_code": %d, "seat_left": %d}`, i, i*100)
+		mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: body}, http.StatusCreated)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantItems  int
+		wantNext   bool
+	}{
+		{"limit 0", "/seats?limit=0", http.StatusBadRequest, 0, false},
+		{"음수 limit", "/rooms?limit=-5", http.StatusBadRequest, 0, false},
+		{"첫 페이지", "/seats?limit=2", http.StatusOK, 2, true},
+		{"전체와 같은 limit", "/seats?limit=3", http.StatusOK, 3, false},
+		{"최댓값 초과", fmt.Sprintf("/seats?limit=%d", consts.PAGE_LIMIT_MAX*10), http.StatusOK, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := mustServe(t, r, testRequest{Method: "GET", Target: tt.target + "&include_total=true"}, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var page struct {
+				Items      []Seat  `json:"items"`
+				NextCursor *string `json:"next_cursor"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &page)
+			if len(page.Items) != tt.wantItems || (page.NextCursor != nil) != tt.wantNext {
+				t.Fatalf("items = %d, next_cursor = %v: %s", len(page.Items), page.NextCursor, rec.Body.String())
+			}
+			if total := rec.Header().Get("X-Total-Count"); total != "3" {
+				t.Fatalf("X-Total-Count = %s, want 3", total)
+			}
+		})
+	}
+}
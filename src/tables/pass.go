@@ -0,0 +1,237 @@
+// pass.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// Pass 구조체는 pass_table(회원이 구매한 이용권)의 각 컬럼을 매핑합니다.
+// RemainingMinutes는 시간권에만, SeatCode는 고정석 정기권에만 값이 있습니다.
+type Pass struct {
+	PassID           int        `json:"pass_id" db:"pass_id"`
+	CompanyCode      int        `json:"company_code"`
+	MemberID         int        `json:"member_id"`
+	ProductID        int        `json:"product_id"`
+	PassType         int        `json:"pass_type"`
+	SeatCode         *int       `json:"seat_code"`
+	RemainingMinutes *int       `json:"remaining_minutes"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	PurchasedAt      time.Time  `json:"purchased_at"`
+}
+
+// RegisterPassRoutes는 회원 이용권 관련 엔드포인트를 등록합니다.
+// 이용권 발급은 점주/직원이 하며, 회원은 본인 이용권만 조회할 수 있습니다.
+func RegisterPassRoutes(r *mux.Router) {
+	r.Handle("/members/{member_id}/passes", allow(GetMemberPasses, memberRoles...)).Methods("GET")
+	r.Handle("/members/{member_id}/passes", allow(CreateMemberPass, staffRoles...)).Methods("POST")
+}
+
+const passColumns = `pass_id, company_code, member_id, product_id, pass_type,
+	seat_code, remaining_minutes, expires_at, purchased_at`
+
+// scanPass는 passColumns 순서로 조회한 행을 Pass로 변환합니다.
+func scanPass(row interface{ Scan(...interface{}) error }) (Pass, error) {
+	var p Pass
+	err := row.Scan(&p.PassID, &p.CompanyCode, &p.MemberID, &p.ProductID, &p.PassType,
+		&p.SeatCode, &p.RemainingMinutes, &p.ExpiresAt, &p.PurchasedAt)
+	return p, err
+}
+
+// GetMemberPasses: 회원의 이용권 목록을 조회합니다.
+// usable=true이면 현재 사용 가능한(잔여 시간이 있고 만료되지 않은) 이용권만 반환합니다.
+func GetMemberPasses(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["member_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 member_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+	if !bindCallerMember(w, r, &memberID) {
+		return
+	}
+
+	query := "SELECT " + passColumns + " FROM pass_table WHERE member_id = $1 AND company_code = $2"
+	args := []interface{}{memberID, companyCode}
+	if r.URL.Query().Get("usable") == "true" {
+		query += ` AND (remaining_minutes IS NULL OR remaining_minutes > 0)
+		           AND (expires_at IS NULL OR expires_at > $3)`
+		args = append(args, time.Now())
+	}
+	query += " ORDER BY purchased_at DESC"
+
+	rows, err := utils.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []Pass{}
+	for rows.Next() {
+		p, err := scanPass(rows)
+		if err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, p)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// createPassRequest는 이용권 구매 요청 본문입니다.
+type createPassRequest struct {
+	ProductID int `json:"product_id"`
+	SeatCode  int `json:"seat_code"`
+}
+
+// CreateMemberPass: 상품을 기준으로 회원에게 이용권을 발급합니다.
+// 시간권은 상품의 minutes를 잔여 시간으로, 기간권은 valid_days로 만료 시각을 정합니다.
+func CreateMemberPass(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["member_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 member_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req createPassRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+
+	member, err := getMember(ctx, companyCode, memberID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Member를 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	product, err := getProduct(ctx, companyCode, req.ProductID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "존재하지 않는 product_id입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if product.OnSale == 0 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "판매 중이 아닌 상품입니다.")
+		return
+	}
+
+	now := time.Now()
+	pass := Pass{
+		CompanyCode: member.CompanyCode,
+		MemberID:    memberID,
+		ProductID:   product.ProductID,
+		PassType:    product.ProductType,
+		PurchasedAt: now,
+	}
+	if product.ProductType == consts.PRODUCT_TYPE_TIME {
+		minutes := product.Minutes
+		pass.RemainingMinutes = &minutes
+	}
+	if product.ValidDays > 0 {
+		expires := now.AddDate(0, 0, product.ValidDays)
+		pass.ExpiresAt = &expires
+	}
+	if product.ProductType == consts.PRODUCT_TYPE_FIXED_SEAT {
+		if req.SeatCode == 0 {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "고정석 이용권은 seat_code가 필요합니다.")
+			return
+		}
+		// 고정석 배정 시 seat/room의 성별 제한 확인 (같은 회사의 seat만 배정할 수 있습니다)
+		allowed, err := seatGenderAllowed(ctx, companyCode, req.SeatCode, member.Gender)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				writeProblem(w, consts.ERR_INVALID_REQUEST, "존재하지 않는 seat_code입니다")
+			} else {
+				writeInternalError(w, err)
+			}
+			return
+		}
+		if !allowed {
+			writeProblem(w, consts.ERR_GENDER_MISMATCH, "좌석의 성별 제한과 회원 성별이 일치하지 않습니다.")
+			return
+		}
+		seatCode := req.SeatCode
+		pass.SeatCode = &seatCode
+	}
+
+	err = utils.DB.QueryRowContext(ctx, `
+		INSERT INTO pass_table
+		(company_code, member_id, product_id, pass_type,
+		 seat_code, remaining_minutes, expires_at, purchased_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING pass_id`,
+		pass.CompanyCode, pass.MemberID, pass.ProductID, pass.PassType,
+		pass.SeatCode, pass.RemainingMinutes, pass.ExpiresAt, pass.PurchasedAt).
+		Scan(&pass.PassID)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pass)
+}
+
+// findUsablePass는 회원이 해당 좌석에서 지금 사용할 수 있는 이용권을 찾습니다.
+// 고정석 이용권은 지정 좌석에서만 사용되며, 만료가 빠른 이용권을 우선합니다.
+// 사용 가능한 이용권이 없으면 sql.ErrNoRows를 반환합니다.
+func findUsablePass(ctx context.Context, tx *sql.Tx, companyCode, memberID, seatCode int, now time.Time) (Pass, error) {
+	row := tx.QueryRowContext(ctx, `
+		SELECT `+passColumns+` FROM pass_table
+		WHERE company_code = $1 AND member_id = $2
+		  AND (seat_code IS NULL OR seat_code = $3)
+		  AND (remaining_minutes IS NULL OR remaining_minutes > 0)
+		  AND (expires_at IS NULL OR expires_at > $4)
+		ORDER BY seat_code IS NULL, expires_at ASC NULLS LAST, pass_id ASC
+		LIMIT 1`, companyCode, memberID, seatCode, now)
+	return scanPass(row)
+}
+
+// deductPassMinutes는 시간권의 잔여 시간에서 사용 시간을 차감합니다.
+// 사용 시간은 분 단위로 올림하며, 잔여 시간은 0 미만으로 내려가지 않습니다.
+// 기간권(remaining_minutes가 NULL)은 변경하지 않습니다.
+func deductPassMinutes(ctx context.Context, tx *sql.Tx, passID int, used time.Duration) error {
+	minutes := int(math.Ceil(used.Minutes()))
+	if minutes <= 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		UPDATE pass_table SET remaining_minutes = GREATEST(remaining_minutes - $1, 0)
+		WHERE pass_id = $2 AND remaining_minutes IS NOT NULL`, minutes, passID)
+	return err
+}
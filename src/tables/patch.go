@@ -0,0 +1,434 @@
+// patch.go
+package tables
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"AllinB/src/consts"
+)
+
+// PATCH 요청이 지원하는 Content-Type
+const (
+	mergePatchContentType = "application/merge-patch+json" // RFC 7396
+	jsonPatchContentType  = "application/json-patch+json"  // RFC 6902
+)
+
+// patch 적용 오류
+var (
+	// errUnsupportedPatch는 지원하지 않는 Content-Type일 때 반환됩니다. (415)
+	errUnsupportedPatch = errors.New("PATCH는 application/merge-patch+json 또는 application/json-patch+json만 지원합니다")
+	// errInvalidPatch는 patch 문서를 해석할 수 없을 때 반환됩니다. (400)
+	errInvalidPatch = errors.New("잘못된 patch 문서")
+	// errPatchFailed는 patch 연산을 적용할 수 없을 때 반환됩니다. (422)
+	errPatchFailed = errors.New("patch를 적용할 수 없습니다")
+	// errPatchTestFailed는 JSON Patch의 test 연산이 실패했을 때 반환됩니다. (409)
+	errPatchTestFailed = errors.New("patch test 연산이 실패했습니다")
+)
+
+// toDocument는 구조체를 JSON 필드명 → 값 문서로 변환합니다. 숫자는 float64입니다.
+func toDocument(v interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	err = json.Unmarshal(body, &doc)
+	return doc, err
+}
+
+// fromDocument는 문서를 dst 구조체에 채웁니다.
+func fromDocument(doc map[string]interface{}, dst interface{}) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, dst)
+}
+
+// applyPatchRequest는 요청의 Content-Type에 따라 merge patch 또는 JSON Patch를 doc에 적용한 문서를 반환합니다.
+func applyPatchRequest(r *http.Request, doc map[string]interface{}) (map[string]interface{}, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var result interface{}
+	switch mediaType {
+	case mergePatchContentType:
+		var patch interface{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			return nil, errInvalidPatch
+		}
+		if _, ok := patch.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("%w: merge patch는 JSON 객체여야 합니다", errInvalidPatch)
+		}
+		result = mergePatch(doc, patch)
+	case jsonPatchContentType:
+		var ops []patchOperation
+		if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+			return nil, errInvalidPatch
+		}
+		var err error
+		if result, err = applyJSONPatch(doc, ops); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errUnsupportedPatch
+	}
+
+	patched, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: 결과가 JSON 객체가 아닙니다", errPatchFailed)
+	}
+	return patched, nil
+}
+
+// writePatchError는 patch 적용 오류를 problem 문서로 응답합니다.
+func writePatchError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errUnsupportedPatch):
+		writeProblem(w, consts.ERR_UNSUPPORTED_MEDIA_TYPE, err.Error())
+	case errors.Is(err, errPatchTestFailed):
+		writeProblem(w, consts.ERR_PATCH_TEST_FAILED, err.Error())
+	case errors.Is(err, errPatchFailed):
+		writeProblem(w, consts.ERR_PATCH_FAILED, err.Error())
+	default:
+		writeProblem(w, consts.ERR_INVALID_BODY, err.Error())
+	}
+}
+
+// mergePatch는 RFC 7396 JSON Merge Patch를 적용합니다. target은 바꾸지 않고 새 값을 반환합니다.
+// patch의 null은 필드 삭제입니다.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	result := make(map[string]interface{})
+	if t, ok := target.(map[string]interface{}); ok {
+		for key, value := range t {
+			result[key] = value
+		}
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(result, key)
+		} else {
+			result[key] = mergePatch(result[key], value)
+		}
+	}
+	return result
+}
+
+// patchOperation은 RFC 6902 JSON Patch 연산 하나입니다.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// value는 연산의 value를 해석합니다. add, replace, test는 value가 있어야 합니다.
+func (op patchOperation) value() (interface{}, error) {
+	if len(op.Value) == 0 {
+		return nil, fmt.Errorf("%w: value가 없습니다", errInvalidPatch)
+	}
+	var v interface{}
+	err := json.Unmarshal(op.Value, &v)
+	return v, err
+}
+
+// applyJSONPatch는 RFC 6902 JSON Patch 연산을 순서대로 적용합니다. 하나라도 실패하면 전체가 실패합니다.
+func applyJSONPatch(doc interface{}, ops []patchOperation) (interface{}, error) {
+	// 실패 시 원본을 건드리지 않도록 복사본에 적용합니다.
+	doc = copyJSON(doc)
+	for i, op := range ops {
+		var err error
+		doc, err = applyPatchOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("%d번째 연산 (%s %s): %w", i+1, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func applyPatchOperation(doc interface{}, op patchOperation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value, false)
+	case "remove":
+		doc, _, err := pointerRemove(doc, path)
+		return doc, err
+	case "replace":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value, true)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if op.Op == "move" {
+			if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+				return nil, fmt.Errorf("%w: 자기 자신의 하위 경로로 이동할 수 없습니다", errPatchFailed)
+			}
+			doc, value, err = pointerRemove(doc, from)
+		} else {
+			value, err = pointerGet(doc, from)
+			value = copyJSON(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value, false)
+	case "test":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		current, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, errPatchTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("%w: 알 수 없는 op %q", errInvalidPatch, op.Op)
+}
+
+// copyJSON은 JSON으로 해석한 값을 깊은 복사합니다.
+func copyJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			c[key] = copyJSON(value)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, value := range v {
+			c[i] = copyJSON(value)
+		}
+		return c
+	}
+	return v
+}
+
+// parsePointer는 RFC 6901 JSON Pointer를 토큰으로 나눕니다. ""는 문서 전체입니다.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: 잘못된 경로 %q", errInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// arrayIndex는 배열 인덱스 토큰을 해석합니다. 0 이상 max 이하만 허용합니다.
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: 잘못된 배열 인덱스 %q", errPatchFailed, token)
+	}
+	return i, nil
+}
+
+// pointerGet은 path의 값을 반환합니다.
+func pointerGet(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q 경로가 없습니다", errPatchFailed, token)
+			}
+			doc = value
+		case []interface{}:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %q 경로가 없습니다", errPatchFailed, token)
+		}
+	}
+	return doc, nil
+}
+
+// pointerAdd는 path에 value를 넣은 문서를 반환합니다.
+// replace이면 path가 이미 있어야 하며 값을 바꾸고, 아니면 객체는 추가/교체, 배열은 그 위치에 끼워 넣습니다.
+func pointerAdd(doc interface{}, path []string, value interface{}, replace bool) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, last := path[0], len(path) == 1
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !last || replace {
+			if !ok {
+				return nil, fmt.Errorf("%w: %q 경로가 없습니다", errPatchFailed, token)
+			}
+		}
+		if last {
+			node[token] = value
+			return node, nil
+		}
+		child, err := pointerAdd(child, path[1:], value, replace)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+		return node, nil
+	case []interface{}:
+		if last && !replace {
+			if token == "-" {
+				return append(node, value), nil
+			}
+			i, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			node[i] = value
+			return node, nil
+		}
+		child, err := pointerAdd(node[i], path[1:], value, replace)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = child
+		return node, nil
+	}
+	return nil, fmt.Errorf("%w: %q 경로가 없습니다", errPatchFailed, token)
+}
+
+// pointerRemove는 path의 값을 지운 문서와 지운 값을 반환합니다.
+func pointerRemove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: 문서 전체는 지울 수 없습니다", errPatchFailed)
+	}
+	token, last := path[0], len(path) == 1
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %q 경로가 없습니다", errPatchFailed, token)
+		}
+		if last {
+			delete(node, token)
+			return node, child, nil
+		}
+		child, removed, err := pointerRemove(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		node[token] = child
+		return node, removed, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if last {
+			removed := node[i]
+			return append(node[:i], node[i+1:]...), removed, nil
+		}
+		child, removed, err := pointerRemove(node[i], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		node[i] = child
+		return node, removed, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %q 경로가 없습니다", errPatchFailed, token)
+}
+
+// validateDocument는 변경 후 문서 doc을 현재 문서 current와 비교해 검사합니다.
+//   - current에 없는 필드는 알 수 없는 필드입니다.
+//   - writable에 없는 필드는 읽기 전용이며, 현재 값과 달라서는 안 됩니다.
+//   - writable 필드는 모두 있어야 하며, 현재 값과 같은 타입(정수/문자열)이어야 합니다.
+func validateDocument(doc, current map[string]interface{}, writable map[string]bool) fieldErrors {
+	errs := make(fieldErrors)
+	for key, value := range doc {
+		currentValue, known := current[key]
+		switch {
+		case !known:
+			errs[key] = consts.FIELD_UNKNOWN
+		case !writable[key]:
+			if !reflect.DeepEqual(value, currentValue) {
+				errs[key] = consts.FIELD_READ_ONLY
+			}
+		case value == nil:
+			errs[key] = consts.FIELD_NULL
+		default:
+			switch currentValue.(type) {
+			case float64:
+				n, ok := value.(float64)
+				if !ok || n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32 {
+					errs[key] = consts.FIELD_NOT_INTEGER
+				}
+			case string:
+				if _, ok := value.(string); !ok {
+					errs[key] = consts.FIELD_NOT_STRING
+				}
+			}
+		}
+	}
+	for key := range writable {
+		if _, ok := doc[key]; !ok {
+			errs[key] = consts.FIELD_REQUIRED
+		}
+	}
+	return errs
+}
+
+// changedFields는 doc에서 current와 값이 다른 writable 필드를 저장소 Update에 넘길 형태로 반환합니다.
+// validateDocument를 통과한 문서여야 하며, 정수 값은 int로 바꿉니다.
+func changedFields(doc, current map[string]interface{}, writable map[string]bool) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for key := range writable {
+		value := doc[key]
+		if reflect.DeepEqual(value, current[key]) {
+			continue
+		}
+		if n, ok := value.(float64); ok {
+			value = int(n)
+		}
+		fields[key] = value
+	}
+	return fields
+}
@@ -0,0 +1,234 @@
+package tables
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// jsonValue는 테스트용 JSON 문자열을 해석합니다.
+func jsonValue(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("잘못된 JSON %s: %v", s, err)
+	}
+	return v
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	const doc = `{"a": 1, "b": {"c": "x"}, "list": [1, 2, 3]}`
+	tests := []struct {
+		name    string
+		ops     string
+		want    string
+		wantErr error
+	}{
+		{"add", `[{"op": "add", "path": "/d", "value": 4}]`,
+			`{"a": 1, "b": {"c": "x"}, "list": [1, 2, 3], "d": 4}`, nil},
+		{"replace", `[{"op": "replace", "path": "/b/c", "value": "y"}]`,
+			`{"a": 1, "b": {"c": "y"}, "list": [1, 2, 3]}`, nil},
+		{"remove", `[{"op": "remove", "path": "/a"}]`,
+			`{"b": {"c": "x"}, "list": [1, 2, 3]}`, nil},
+		{"배열 원소 remove", `[{"op": "remove", "path": "/list/0"}]`,
+			`{"a": 1, "b": {"c": "x"}, "list": [2, 3]}`, nil},
+		{"move", `[{"op": "move", "from": "/a", "path": "/b/a"}]`,
+			`{"b": {"c": "x", "a": 1}, "list": [1, 2, 3]}`, nil},
+		{"배열 안에서 move", `[{"op": "move", "from": "/list/0", "path": "/list/2"}]`,
+			`{"a": 1, "b": {"c": "x"}, "list": [2, 3, 1]}`, nil},
+		{"copy", `[{"op": "copy", "from": "/b", "path": "/e"}]`,
+			`{"a": 1, "b": {"c": "x"}, "e": {"c": "x"}, "list": [1, 2, 3]}`, nil},
+		{"test 성공 후 replace", `[{"op": "test", "path": "/a", "value": 1}, {"op": "replace", "path": "/a", "value": 2}]`,
+			`{"a": 2, "b": {"c": "x"}, "list": [1, 2, 3]}`, nil},
+		{"배열 끝 -에 add", `[{"op": "add", "path": "/list/-", "value": 4}]`,
+			`{"a": 1, "b": {"c": "x"}, "list": [1, 2, 3, 4]}`, nil},
+		{"배열 길이 인덱스에 add", `[{"op": "add", "path": "/list/3", "value": 4}]`,
+			`{"a": 1, "b": {"c": "x"}, "list": [1, 2, 3, 4]}`, nil},
+		{"배열 중간에 add", `[{"op": "add", "path": "/list/1", "value": 9}]`,
+			`{"a": 1, "b": {"c": "x"}, "list": [1, 9, 2, 3]}`, nil},
+		{"~1 이스케이프", `[{"op": "add", "path": "/x~1y", "value": true}]`,
+			`{"a": 1, "b": {"c": "x"}, "list": [1, 2, 3], "x/y": true}`, nil},
+
+		{"test 실패", `[{"op": "test", "path": "/a", "value": 2}]`, "", errPatchTestFailed},
+		{"test 타입 불일치", `[{"op": "test", "path": "/a", "value": "1"}]`, "", errPatchTestFailed},
+		{"없는 경로 remove", `[{"op": "remove", "path": "/z"}]`, "", errPatchFailed},
+		{"없는 경로 replace", `[{"op": "replace", "path": "/z", "value": 1}]`, "", errPatchFailed},
+		{"없는 경로 test", `[{"op": "test", "path": "/z", "value": 1}]`, "", errPatchFailed},
+		{"-는 replace에 사용할 수 없음", `[{"op": "replace", "path": "/list/-", "value": 4}]`, "", errPatchFailed},
+		{"-는 remove에 사용할 수 없음", `[{"op": "remove", "path": "/list/-"}]`, "", errPatchFailed},
+		{"범위 밖 배열 인덱스", `[{"op": "add", "path": "/list/5", "value": 4}]`, "", errPatchFailed},
+		{"0으로 시작하는 배열 인덱스", `[{"op": "remove", "path": "/list/01"}]`, "", errPatchFailed},
+		{"음수 배열 인덱스", `[{"op": "remove", "path": "/list/-1"}]`, "", errPatchFailed},
+		{"하위 경로로 move", `[{"op": "move", "from": "/b", "path": "/b/c/d"}]`, "", errPatchFailed},
+		{"문서 전체 remove", `[{"op": "remove", "path": ""}]`, "", errPatchFailed},
+		{"value 없음", `[{"op": "add", "path": "/d"}]`, "", errInvalidPatch},
+		{"알 수 없는 op", `[{"op": "increment", "path": "/a"}]`, "", errInvalidPatch},
+		{"/로 시작하지 않는 경로", `[{"op": "remove", "path": "a"}]`, "", errInvalidPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []patchOperation
+			if err := json.Unmarshal([]byte(tt.ops), &ops); err != nil {
+				t.Fatal(err)
+			}
+			original := jsonValue(t, doc)
+			got, err := applyJSONPatch(original, ops)
+			if !reflect.DeepEqual(original, jsonValue(t, doc)) {
+				t.Fatalf("원본 문서가 바뀌었습니다: %v", original)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := jsonValue(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("결과 = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"필드 변경", `{"a": 2}`, `{"a": 2, "b": {"c": "x", "d": 1}}`},
+		{"null은 삭제", `{"a": null}`, `{"b": {"c": "x", "d": 1}}`},
+		{"중첩 객체 병합", `{"b": {"c": null, "e": true}}`, `{"a": 1, "b": {"d": 1, "e": true}}`},
+		{"객체가 아닌 값은 교체", `{"b": [1]}`, `{"a": 1, "b": [1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := jsonValue(t, `{"a": 1, "b": {"c": "x", "d": 1}}`)
+			got := mergePatch(target, jsonValue(t, tt.patch))
+			if want := jsonValue(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("결과 = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateDocument(t *testing.T) {
+	current := map[string]interface{}{"company_code": float64(7), "room_title": "A", "room_width": float64(100)}
+	writable := map[string]bool{"room_title": true, "room_width": true}
+	tests := []struct {
+		name string
+		doc  string
+		want fieldErrors
+	}{
+		{"변경 없음", `{"company_code": 7, "room_title": "A", "room_width": 100}`, fieldErrors{}},
+		{"writable 필드 변경", `{"company_code": 7, "room_title": "B", "room_width": 200}`, fieldErrors{}},
+		{"읽기 전용 필드 변경", `{"company_code": 8, "room_title": "A", "room_width": 100}`,
+			fieldErrors{"company_code": consts.FIELD_READ_ONLY}},
+		{"알 수 없는 필드", `{"company_code": 7, "room_title": "A", "room_width": 100, "owner": "x"}`,
+			fieldErrors{"owner": consts.FIELD_UNKNOWN}},
+		{"writable 필드 삭제", `{"company_code": 7, "room_width": 100}`,
+			fieldErrors{"room_title": consts.FIELD_REQUIRED}},
+		{"null", `{"company_code": 7, "room_title": null, "room_width": 100}`,
+			fieldErrors{"room_title": consts.FIELD_NULL}},
+		{"정수가 아닌 값", `{"company_code": 7, "room_title": "A", "room_width": 1.5}`,
+			fieldErrors{"room_width": consts.FIELD_NOT_INTEGER}},
+		{"int32 초과", `{"company_code": 7, "room_title": "A", "room_width": 2147483648}`,
+			fieldErrors{"room_width": consts.FIELD_NOT_INTEGER}},
+		{"문자열이 아닌 값", `{"company_code": 7, "room_title": 1, "room_width": 100}`,
+			fieldErrors{"room_title": consts.FIELD_NOT_STRING}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := jsonValue(t, tt.doc).(map[string]interface{})
+			if got := validateDocument(doc, current, writable); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateDocument = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchRoomJSONPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+		wantField   string
+	}{
+		{"replace", jsonPatchContentType, `[{"op": "replace", "path": "/room_title", "value": "B"}]`, http.StatusOK, "", ""},
+		{"test 후 replace", jsonPatchContentType,
+			`[{"op": "test", "path": "/room_title", "value": "A"}, {"op": "replace", "path": "/room_title", "value": "B"}]`, http.StatusOK, "", ""},
+		{"test 실패", jsonPatchContentType, `[{"op": "test", "path": "/room_title", "value": "Z"}]`,
+			http.StatusConflict, consts.ERR_PATCH_TEST_FAILED, ""},
+		{"없는 경로", jsonPatchContentType, `[{"op": "remove", "path": "/nothing"}]`,
+			http.StatusUnprocessableEntity, consts.ERR_PATCH_FAILED, ""},
+		{"writable 필드 remove", jsonPatchContentType, `[{"op": "remove", "path": "/room_title"}]`,
+			http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS, "room_title"},
+		{"읽기 전용 필드 replace", jsonPatchContentType, `[{"op": "replace", "path": "/company_code", "value": 8}]`,
+			http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS, "company_code"},
+		{"읽기 전용 필드로 move", jsonPatchContentType, `[{"op": "move", "from": "/room_title", "path": "/version"}]`,
+			http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS, "version"},
+		{"알 수 없는 필드 add", jsonPatchContentType, `[{"op": "add", "path": "/owner", "value": "x"}]`,
+			http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS, "owner"},
+		{"알 수 없는 필드 merge", mergePatchContentType, `{"owner": "x"}`,
+			http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS, "owner"},
+		{"잘못된 patch 문서", jsonPatchContentType, `{"op": "remove"}`,
+			http.StatusBadRequest, consts.ERR_INVALID_BODY, ""},
+		{"지원하지 않는 Content-Type", "application/json", `{"room_title": "B"}`,
+			http.StatusUnsupportedMediaType, consts.ERR_UNSUPPORTED_MEDIA_TYPE, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestRouter(t)
+			mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1, "room_title": "A"}`}, http.StatusCreated)
+
+			rec := mustServe(t, r, testRequest{
+				Method: "PATCH", Target: "/rooms/1", Body: tt.body,
+				Header: map[string]string{"Content-Type": tt.contentType},
+			}, tt.wantStatus)
+			if tt.wantCode == "" {
+				var room Room
+				json.Unmarshal(rec.Body.Bytes(), &room)
+				if room.RoomTitle != "B" {
+					t.Fatalf("room_title = %s, want B", room.RoomTitle)
+				}
+				return
+			}
+
+			var p utils.Problem
+			json.Unmarshal(rec.Body.Bytes(), &p)
+			if p.Code != tt.wantCode {
+				t.Fatalf("code = %s, want %s", p.Code, tt.wantCode)
+			}
+			if tt.wantField != "" && !hasFieldError(p.Errors, tt.wantField) {
+				t.Fatalf("%s 필드 오류가 없습니다: %s", tt.wantField, rec.Body.String())
+			}
+
+			// 실패한 patch는 아무것도 바꾸지 않습니다.
+			rec = mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/1"}, http.StatusOK)
+			var room Room
+			json.Unmarshal(rec.Body.Bytes(), &room)
+			if room.RoomTitle != "A" || room.Version != 1 {
+				t.Fatalf("실패한 patch 후 room = %+v", room)
+			}
+		})
+	}
+}
+
+// hasFieldError는 errs에 field의 오류가 있는지 확인합니다.
+func hasFieldError(errs []utils.FieldError, field string) bool {
+	for _, e := range errs {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
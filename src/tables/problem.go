@@ -0,0 +1,105 @@
+// problem.go
+package tables
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// FieldErrorInfo는 필드 오류 코드 카탈로그의 항목입니다. Message는 기본(한국어) 메시지입니다.
+type FieldErrorInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ErrorCatalog는 GET /errors 응답 본문입니다.
+type ErrorCatalog struct {
+	Errors      []utils.ErrorInfo `json:"errors"`
+	FieldErrors []FieldErrorInfo  `json:"field_errors"`
+}
+
+// fieldErrorCatalog는 필드 오류 코드와 기본 메시지 목록입니다.
+var fieldErrorCatalog = []FieldErrorInfo{
+	{consts.FIELD_REQUIRED, "필수 필드입니다"},
+	{consts.FIELD_UNKNOWN, "알 수 없는 필드입니다"},
+	{consts.FIELD_READ_ONLY, "읽기 전용 필드입니다"},
+	{consts.FIELD_NULL, "null은 사용할 수 없습니다"},
+	{consts.FIELD_NOT_INTEGER, "정수 값이 필요합니다"},
+	{consts.FIELD_NOT_STRING, "문자열 값이 필요합니다"},
+	{consts.FIELD_INVALID_COLOR, "#RGB, #RRGGBB, #RRGGBBAA 형식의 색상이어야 합니다"},
+	{consts.FIELD_NEGATIVE, "0 이상이어야 합니다"},
+	{consts.FIELD_INVALID_FLAG, "0 또는 1이어야 합니다"},
+	{consts.FIELD_INVALID_GENDER, "허용되지 않는 gender 값입니다"},
+	{consts.FIELD_TOO_LONG, fmt.Sprintf("%d자 이하여야 합니다", consts.TITLE_MAX_LENGTH)},
+}
+
+// RegisterErrorRoutes는 오류 코드 카탈로그 엔드포인트를 등록합니다.
+// 로그인 전 화면에서도 메시지를 표시할 수 있도록 인증 없이 조회합니다.
+func RegisterErrorRoutes(r *mux.Router) {
+	r.HandleFunc("/errors", GetErrorCatalog).Methods("GET")
+	r.HandleFunc("/errors/{code}", GetErrorCode).Methods("GET")
+}
+
+// GetErrorCatalog: 오류 코드와 필드 오류 코드 전체 목록을 조회합니다.
+func GetErrorCatalog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ErrorCatalog{Errors: utils.ErrorCatalog(), FieldErrors: fieldErrorCatalog})
+}
+
+// GetErrorCode: problem 문서의 type(/errors/{code})이 가리키는 오류 코드 하나를 조회합니다.
+func GetErrorCode(w http.ResponseWriter, r *http.Request) {
+	code := mux.Vars(r)["code"]
+	info, ok := utils.LookupError(code)
+	if !ok {
+		writeProblem(w, consts.ERR_NOT_FOUND, "알 수 없는 오류 코드입니다: "+code)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// fieldErrorMessage는 필드 오류 코드의 기본 메시지입니다.
+func fieldErrorMessage(code string) string {
+	for _, info := range fieldErrorCatalog {
+		if info.Code == code {
+			return info.Message
+		}
+	}
+	return code
+}
+
+// writeProblem은 오류 코드에 해당하는 problem 문서로 응답합니다.
+func writeProblem(w http.ResponseWriter, code, detail string) {
+	utils.WriteError(w, code, detail)
+}
+
+// writeInternalError는 오류를 로그에만 남기고 내부 오류로 응답합니다.
+// DB 오류 메시지가 그대로 클라이언트에 노출되지 않도록 합니다.
+func writeInternalError(w http.ResponseWriter, err error) {
+	log.Printf("내부 오류: %v", err)
+	writeProblem(w, consts.ERR_INTERNAL, "")
+}
+
+// writeFieldErrors는 필드 검사 실패를 422와 필드별 오류 코드로 응답합니다.
+func writeFieldErrors(w http.ResponseWriter, errs fieldErrors) {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	p := utils.NewProblem(consts.ERR_INVALID_FIELDS, "")
+	for _, key := range keys {
+		p.Errors = append(p.Errors, utils.FieldError{Field: key, Code: errs[key], Message: fieldErrorMessage(errs[key])})
+	}
+	p.Detail = fmt.Sprintf("%d개 필드의 값이 올바르지 않습니다", len(keys))
+	utils.WriteProblem(w, p)
+}
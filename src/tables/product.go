@@ -0,0 +1,268 @@
+// product.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// Product 구조체는 product_table의 각 컬럼을 매핑합니다.
+// Minutes는 시간권의 제공 시간(분), ValidDays는 구매 시점부터의 유효 기간(일)입니다.
+type Product struct {
+	ProductID   int    `json:"product_id" db:"product_id"`
+	CompanyCode int    `json:"company_code"`
+	ProductName string `json:"product_name"`
+	ProductType int    `json:"product_type"`
+	Minutes     int    `json:"minutes"`
+	ValidDays   int    `json:"valid_days"`
+	Price       int    `json:"price"`
+	OnSale      int    `json:"on_sale"`
+}
+
+// RegisterProductRoutes는 product_table 관련 엔드포인트를 등록합니다.
+// 상품 조회는 모든 역할, 생성/수정/삭제는 점주만 할 수 있습니다.
+func RegisterProductRoutes(r *mux.Router) {
+	r.Handle("/products", allow(GetProducts, anyRole...)).Methods("GET")
+	r.Handle("/products/{product_id}", allow(GetProduct, anyRole...)).Methods("GET")
+	r.Handle("/products", allow(CreateProduct, ownerOnly...)).Methods("POST")
+	r.Handle("/products/{product_id}", allow(UpdateProduct, ownerOnly...)).Methods("PUT")
+	r.Handle("/products/{product_id}", allow(DeleteProduct, ownerOnly...)).Methods("DELETE")
+}
+
+// validateProduct는 상품 유형에 맞게 시간/기간 값이 채워졌는지 확인합니다.
+func validateProduct(p Product) string {
+	switch p.ProductType {
+	case consts.PRODUCT_TYPE_TIME:
+		if p.Minutes <= 0 {
+			return "시간권은 minutes가 필요합니다."
+		}
+	case consts.PRODUCT_TYPE_DAY, consts.PRODUCT_TYPE_FIXED_SEAT:
+		if p.ValidDays <= 0 {
+			return "기간권은 valid_days가 필요합니다."
+		}
+	default:
+		return "잘못된 product_type 값"
+	}
+	if strings.TrimSpace(p.ProductName) == "" {
+		return "product_name이 필요합니다."
+	}
+	return ""
+}
+
+// GetProducts: 상품 목록을 조회합니다.
+// 호출자의 company_code에 속한 상품만 조회하며, product_type, on_sale 쿼리 파라미터로 필터링할 수 있습니다.
+func GetProducts(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	filters := []string{"company_code = $1"}
+	args := []interface{}{utils.CompanyCode(r.Context())}
+	paramIdx := 2
+	filterParams := map[string]string{
+		"product_type": "product_type",
+		"on_sale":      "on_sale",
+	}
+	for param, dbField := range filterParams {
+		if value := r.URL.Query().Get(param); value != "" {
+			filters = append(filters, fmt.Sprintf("%s = $%d", dbField, paramIdx))
+			args = append(args, value)
+			paramIdx++
+		}
+	}
+
+	query := `
+		SELECT product_id, company_code, product_name, product_type,
+		       minutes, valid_days, price, on_sale
+		FROM product_table WHERE ` + strings.Join(filters, " AND ")
+	query += " ORDER BY product_id ASC"
+
+	rows, err := utils.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []Product{}
+	for rows.Next() {
+		var p Product
+		if err := rows.Scan(&p.ProductID, &p.CompanyCode, &p.ProductName, &p.ProductType,
+			&p.Minutes, &p.ValidDays, &p.Price, &p.OnSale); err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, p)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetProduct: 단일 상품을 조회합니다.
+func GetProduct(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	productID, err := strconv.Atoi(mux.Vars(r)["product_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 product_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	product, err := getProduct(ctx, companyCode, productID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Product를 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// CreateProduct: 새로운 상품을 생성합니다.
+func CreateProduct(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	// on_sale은 생략 시 판매 중(1)으로 생성합니다.
+	product := Product{OnSale: 1}
+	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	// 상품은 항상 호출자의 company_code로 생성합니다.
+	product.CompanyCode = utils.CompanyCode(r.Context())
+	if msg := validateProduct(product); msg != "" {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, msg)
+		return
+	}
+
+	err := utils.DB.QueryRowContext(ctx, `
+		INSERT INTO product_table
+		(company_code, product_name, product_type, minutes, valid_days, price, on_sale)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING product_id`,
+		product.CompanyCode, product.ProductName, product.ProductType,
+		product.Minutes, product.ValidDays, product.Price, product.OnSale).
+		Scan(&product.ProductID)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(product)
+}
+
+// UpdateProduct: 상품 정보를 전체 교체합니다.
+// 이미 판매된 이용권에는 영향을 주지 않습니다.
+func UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	productID, err := strconv.Atoi(mux.Vars(r)["product_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 product_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var product Product
+	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if product.ProductID != 0 && product.ProductID != productID {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "URL과 body의 product_id가 다릅니다.")
+		return
+	}
+	if product.CompanyCode != 0 && product.CompanyCode != companyCode {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "company_code는 변경할 수 없습니다.")
+		return
+	}
+	product.ProductID = productID
+	product.CompanyCode = companyCode
+	if msg := validateProduct(product); msg != "" {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, msg)
+		return
+	}
+
+	res, err := utils.DB.ExecContext(ctx, `
+		UPDATE product_table SET
+		product_name = $1, product_type = $2,
+		minutes = $3, valid_days = $4, price = $5, on_sale = $6
+		WHERE product_id = $7 AND company_code = $8`,
+		product.ProductName, product.ProductType,
+		product.Minutes, product.ValidDays, product.Price, product.OnSale, productID, companyCode)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Product를 찾을 수 없습니다.")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// DeleteProduct: 상품을 삭제합니다.
+func DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	productID, err := strconv.Atoi(mux.Vars(r)["product_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 product_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+	res, err := utils.DB.ExecContext(ctx,
+		"DELETE FROM product_table WHERE product_id = $1 AND company_code = $2", productID, companyCode)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Product를 찾을 수 없습니다.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getProduct는 companyCode에 속한 단일 상품을 product_id로 조회합니다.
+func getProduct(ctx context.Context, companyCode, productID int) (Product, error) {
+	var p Product
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT product_id, company_code, product_name, product_type,
+		       minutes, valid_days, price, on_sale
+		FROM product_table WHERE product_id = $1 AND company_code = $2`, productID, companyCode).
+		Scan(&p.ProductID, &p.CompanyCode, &p.ProductName, &p.ProductType,
+			&p.Minutes, &p.ValidDays, &p.Price, &p.OnSale)
+	return p, err
+}
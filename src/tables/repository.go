@@ -0,0 +1,226 @@
+// repository.go
+package tables
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// 저장소(repository) 공통 오류
+// 핸들러는 이 오류들을 HTTP 상태 코드로 변환합니다.
+var (
+	// ErrNotFound는 조회/수정/삭제 대상이 없을 때 반환됩니다.
+	ErrNotFound = errors.New("대상을 찾을 수 없습니다")
+	// ErrDuplicate는 company_code 안에서 코드가 중복될 때 반환됩니다.
+	ErrDuplicate = errors.New("이미 존재하는 코드입니다")
+	// ErrInvalidValue는 컬럼 제약(CHECK, 타입)을 만족하지 않는 값일 때 반환됩니다.
+	ErrInvalidValue = errors.New("허용되지 않는 필드 값이 있습니다")
+	// ErrRoomNotEmpty는 seat이 남아 있는 room을 cascade 없이 삭제하려 할 때 반환됩니다.
+	ErrRoomNotEmpty = errors.New("좌석이 배정된 room은 삭제할 수 없습니다")
+	// ErrVersionMismatch는 If-Match로 받은 버전과 현재 행의 버전이 다를 때 반환됩니다.
+	ErrVersionMismatch = errors.New("다른 요청이 먼저 변경했습니다")
+	// ErrRoomNotFound는 seat이 같은 company_code에 없는 room을 가리킬 때 반환됩니다. (seat_room_fk)
+	ErrRoomNotFound = errors.New("seat이 가리키는 room이 없습니다")
+	// ErrSeatInUse는 사용 중인 세션, 끝나지 않은 확정 예약, 고정석 이용권이 있는 seat을 삭제하려 할 때 반환됩니다.
+	ErrSeatInUse = errors.New("사용 중이거나 예약/고정석 이용권이 있는 좌석은 삭제할 수 없습니다")
+)
+
+// RowVersion은 room/seat 행의 버전입니다. ETag로 주고받으며 수정/삭제 시 If-Match 조건으로 사용합니다.
+// 같은 코드로 삭제 후 다시 만든 행과 구분하도록 auto_increment를 함께 담습니다.
+type RowVersion struct {
+	AutoIncrement int
+	Version       int
+}
+
+// RoomRepository는 room_table 저장소입니다.
+// 모든 메서드는 company_code 범위 안에서만 동작합니다.
+type RoomRepository interface {
+	// List는 filter를 만족하는 회사의 room을 room_code 순으로 반환합니다.
+	List(ctx context.Context, companyCode int, filter RoomFilter) ([]Room, error)
+	// Count는 filter를 만족하는 회사의 room 수를 반환합니다. (페이지 조건은 무시합니다)
+	Count(ctx context.Context, companyCode int, filter RoomFilter) (int, error)
+	// Get은 room 하나를 반환합니다. 없으면 ErrNotFound입니다.
+	Get(ctx context.Context, companyCode, roomCode int) (Room, error)
+	// Create는 room을 저장하고 auto_increment가 채워진 room을 반환합니다.
+	Create(ctx context.Context, room Room) (Room, error)
+	// Update는 fields(컬럼명 → 값)만 변경하고 version을 올린 뒤 변경된 room을 반환합니다.
+	// ifMatch가 nil이 아니고 현재 버전과 다르면 ErrVersionMismatch입니다.
+	Update(ctx context.Context, companyCode, roomCode int, fields map[string]interface{}, ifMatch *RowVersion) (Room, error)
+	// Delete는 room을 삭제하고 함께 삭제된 seat 수를 반환합니다.
+	// seat이 남아 있으면 cascade가 true일 때만 seat까지 삭제하며, 아니면 ErrRoomNotEmpty입니다.
+	// 함께 삭제할 seat 중 사용 중이거나 예약/고정석 이용권이 있는 것이 있으면 ErrSeatInUse입니다.
+	// ifMatch가 nil이 아니고 현재 버전과 다르면 ErrVersionMismatch입니다.
+	Delete(ctx context.Context, companyCode, roomCode int, cascade bool, ifMatch *RowVersion) (int, error)
+	// ApplyLayout은 changes를 한 트랜잭션으로 반영합니다. (배치 가져오기)
+	// 수정되는 room/seat의 version도 올립니다. 반영 후 room이 없는 seat이 남으면 ErrRoomNotFound,
+	// 삭제할 seat 중 사용 중이거나 예약/고정석 이용권이 있는 것이 있으면 ErrSeatInUse입니다.
+	ApplyLayout(ctx context.Context, companyCode int, changes LayoutChanges) error
+}
+
+// LayoutChanges는 배치 가져오기로 한 번에 반영할 room/seat 변경입니다.
+// Rooms, Seats는 코드 기준으로 없으면 생성하고 있으면 수정하며, 그 다음 삭제를 적용합니다.
+type LayoutChanges struct {
+	Rooms       []Room
+	Seats       []Seat
+	DeleteSeats []int
+	DeleteRooms []int
+}
+
+// RoomFilter는 room 목록 조회 조건입니다.
+type RoomFilter struct {
+	// Where는 조회 조건이며, nil이면 회사의 모든 room입니다. 컬럼은 roomFilterColumns 중에서 사용합니다.
+	Where Filter
+	// Page는 페이지 조건이며, After의 Key는 room_code입니다.
+	Page
+}
+
+// roomFilterColumns는 room 목록에서 필터할 수 있는 컬럼입니다.
+var roomFilterColumns = filterColumns{
+	"auto_increment":         filterInt,
+	"room_code":              filterInt,
+	"room_title":             filterText,
+	"title_background_color": filterText,
+	"title_text_color":       filterText,
+	"room_background_color":  filterText,
+	"room_top":               filterInt,
+	"room_left":              filterInt,
+	"room_width":             filterInt,
+	"room_height":            filterInt,
+	"gender":                 filterInt,
+	"waiting":                filterInt,
+	"release":                filterInt,
+	"hide_title":             filterInt,
+	"transparent_background": filterInt,
+	"hide_border":            filterInt,
+	"kiosk_disabled":         filterInt,
+	"power_control":          filterInt,
+	"breaker_number":         filterInt,
+}
+
+// SeatFilter는 seat 목록 조회 조건입니다.
+type SeatFilter struct {
+	// Where는 조회 조건이며, nil이면 회사의 모든 seat입니다. 컬럼은 seatFilterColumns 중에서 사용합니다.
+	Where Filter
+	// Sort는 정렬 컬럼(seat_code, seat_title, auto_increment)이며, 비어 있으면 seat_code입니다.
+	// 정렬 값이 같으면 auto_increment 순입니다.
+	Sort string
+	// Desc가 true이면 내림차순으로 정렬합니다.
+	Desc bool
+	// Page는 페이지 조건이며, After의 Key는 Sort 컬럼 값입니다.
+	Page
+}
+
+// sortColumn은 실제로 사용할 정렬 컬럼을 반환합니다.
+func (f SeatFilter) sortColumn() string {
+	if seatSortColumns[f.Sort] {
+		return f.Sort
+	}
+	return "seat_code"
+}
+
+// seatSortKey는 cursor에 담을 seat의 정렬 컬럼 값입니다.
+func seatSortKey(seat Seat, column string) string {
+	switch column {
+	case "seat_title":
+		return seat.SeatTitle
+	case "auto_increment":
+		return strconv.Itoa(seat.AutoIncrement)
+	}
+	return strconv.Itoa(seat.SeatCode)
+}
+
+// seatFilterColumns는 seat 목록에서 필터할 수 있는 컬럼입니다.
+var seatFilterColumns = filterColumns{
+	"auto_increment":         filterInt,
+	"seat_code":              filterInt,
+	"room_code":              filterInt,
+	"seat_title":             filterText,
+	"title_background_color": filterText,
+	"title_text_color":       filterText,
+	"seat_background_color":  filterText,
+	"seat_top":               filterInt,
+	"seat_left":              filterInt,
+	"seat_width":             filterInt,
+	"seat_height":            filterInt,
+	"gender":                 filterInt,
+	"waiting":                filterInt,
+	"release":                filterInt,
+	"hide_title":             filterInt,
+	"transparent_background": filterInt,
+	"hide_border":            filterInt,
+	"kiosk_disabled":         filterInt,
+	"power_control":          filterInt,
+	"breaker_number":         filterInt,
+}
+
+// seatSortColumns는 SeatFilter.Sort에 사용할 수 있는 컬럼입니다.
+var seatSortColumns = map[string]bool{
+	"seat_code":      true,
+	"seat_title":     true,
+	"auto_increment": true,
+}
+
+// SeatRepository는 seat_table 저장소입니다.
+// 모든 메서드는 company_code 범위 안에서만 동작합니다.
+type SeatRepository interface {
+	// List는 filter를 만족하는 회사의 seat을 반환합니다.
+	List(ctx context.Context, companyCode int, filter SeatFilter) ([]Seat, error)
+	// Count는 filter를 만족하는 회사의 seat 수를 반환합니다. (페이지 조건은 무시합니다)
+	Count(ctx context.Context, companyCode int, filter SeatFilter) (int, error)
+	// Get은 seat 하나를 반환합니다. 없으면 ErrNotFound입니다.
+	Get(ctx context.Context, companyCode, seatCode int) (Seat, error)
+	// Create는 seat을 저장하고 auto_increment가 채워진 seat을 반환합니다.
+	// room이 없으면 ErrRoomNotFound입니다.
+	Create(ctx context.Context, seat Seat) (Seat, error)
+	// CreateMany는 seats를 모두 저장하거나, 하나라도 실패하면 아무것도 저장하지 않습니다.
+	// room이 없으면 ErrRoomNotFound입니다.
+	CreateMany(ctx context.Context, seats []Seat) ([]Seat, error)
+	// Update는 fields(컬럼명 → 값)만 변경하고 version을 올린 뒤 변경된 seat을 반환합니다.
+	// ifMatch가 nil이 아니고 현재 버전과 다르면 ErrVersionMismatch, 바꾼 room_code의 room이 없으면 ErrRoomNotFound입니다.
+	Update(ctx context.Context, companyCode, seatCode int, fields map[string]interface{}, ifMatch *RowVersion) (Seat, error)
+	// Delete는 seat을 삭제하고 삭제된 seat을 반환합니다.
+	// ifMatch가 nil이 아니고 현재 버전과 다르면 ErrVersionMismatch,
+	// 사용 중이거나 예약/고정석 이용권이 있는 seat이면 ErrSeatInUse입니다.
+	Delete(ctx context.Context, companyCode, seatCode int, ifMatch *RowVersion) (Seat, error)
+}
+
+// applyFields는 허용된 컬럼의 값만 JSON 태그 기준으로 dst에 덮어씁니다.
+// 타입이 맞지 않는 값은 ErrInvalidValue입니다.
+func applyFields(dst interface{}, fields map[string]interface{}, allowed map[string]bool) error {
+	patch := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if allowed[key] {
+			patch[key] = value
+		}
+	}
+	if len(patch) == 0 {
+		return fmt.Errorf("%w: 변경할 컬럼이 없습니다", ErrInvalidValue)
+	}
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	return nil
+}
+
+// room/seat 핸들러가 사용하는 저장소
+var (
+	roomRepo RoomRepository
+	seatRepo SeatRepository
+)
+
+// SetRoomRepository는 room 핸들러가 사용할 저장소를 설정합니다.
+func SetRoomRepository(repo RoomRepository) {
+	roomRepo = repo
+}
+
+// SetSeatRepository는 seat 핸들러가 사용할 저장소를 설정합니다.
+func SetSeatRepository(repo SeatRepository) {
+	seatRepo = repo
+}
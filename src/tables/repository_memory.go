@@ -0,0 +1,505 @@
+// repository_memory.go
+package tables
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MemoryStore는 메모리에 room/seat을 보관하는 저장소입니다.
+// DB 없이 httptest만으로 핸들러를 시험할 때 사용합니다.
+// room 삭제 시 seat을 함께 지울 수 있도록 room/seat 저장소가 하나의 잠금을 공유합니다.
+type MemoryStore struct {
+	mu     sync.Mutex
+	nextID int
+	rooms  map[[2]int]Room // {company_code, room_code}
+	seats  map[[2]int]Seat // {company_code, seat_code}
+	// seatsInUse는 세션/예약/고정석 이용권이 있는 것으로 취급할 seat입니다. (삭제 시 ErrSeatInUse)
+	seatsInUse map[[2]int]bool
+}
+
+// NewMemoryStore는 빈 MemoryStore를 생성합니다.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		rooms:      make(map[[2]int]Room),
+		seats:      make(map[[2]int]Seat),
+		seatsInUse: make(map[[2]int]bool),
+	}
+}
+
+// SetSeatInUse는 seat을 사용 중(열린 세션, 확정 예약, 고정석 이용권이 있음)으로 표시하거나 해제합니다.
+// 메모리 저장소에는 세션/예약/이용권이 없으므로 삭제 거부를 시험할 때 사용합니다.
+func (m *MemoryStore) SetSeatInUse(companyCode, seatCode int, inUse bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seatsInUse[[2]int{companyCode, seatCode}] = inUse
+}
+
+// checkSeatsNotInUse는 seatCodes 중 사용 중으로 표시된 seat이 있으면 ErrSeatInUse를 반환합니다.
+// 호출자가 mu를 잡고 있어야 합니다.
+func (m *MemoryStore) checkSeatsNotInUse(companyCode int, seatCodes []int) error {
+	for _, seatCode := range seatCodes {
+		if m.seatsInUse[[2]int{companyCode, seatCode}] {
+			return fmt.Errorf("seat_code %d: %w", seatCode, ErrSeatInUse)
+		}
+	}
+	return nil
+}
+
+// Rooms는 이 저장소를 사용하는 RoomRepository를 반환합니다.
+func (m *MemoryStore) Rooms() *MemoryRoomRepository {
+	return &MemoryRoomRepository{store: m}
+}
+
+// Seats는 이 저장소를 사용하는 SeatRepository를 반환합니다.
+func (m *MemoryStore) Seats() *MemorySeatRepository {
+	return &MemorySeatRepository{store: m}
+}
+
+// validFlags는 0/1만 허용하는 플래그 컬럼과 gender, breaker_number 제약을 확인합니다.
+// 0002_layout_constraints 마이그레이션의 CHECK 제약과 같은 규칙입니다.
+func validFlags(gender, breakerNumber int, flags ...int) bool {
+	if gender < 0 || gender > 2 || breakerNumber < 0 {
+		return false
+	}
+	for _, f := range flags {
+		if f != 0 && f != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func validRoom(room Room) bool {
+	return room.RoomWidth >= 0 && room.RoomHeight >= 0 &&
+		validFlags(room.Gender, room.BreakerNumber,
+			room.Waiting, room.Release, room.HideTitle, room.TransparentBackground,
+			room.HideBorder, room.KioskDisabled, room.PowerControl)
+}
+
+func validSeat(seat Seat) bool {
+	return seat.SeatWidth >= 0 && seat.SeatHeight >= 0 &&
+		validFlags(seat.Gender, seat.BreakerNumber,
+			seat.Waiting, seat.Release, seat.HideTitle, seat.TransparentBackground,
+			seat.HideBorder, seat.KioskDisabled, seat.PowerControl)
+}
+
+// hasRoom은 seat이 가리키는 room이 있는지 확인합니다. 0004_seat_room_fk 외래 키와 같은 규칙입니다.
+// 호출자가 mu를 잡고 있어야 합니다.
+func (m *MemoryStore) hasRoom(companyCode, roomCode int) bool {
+	_, ok := m.rooms[[2]int{companyCode, roomCode}]
+	return ok
+}
+
+// checkVersion은 ifMatch가 있을 때 현재 버전과 같은지 확인합니다.
+func checkVersion(current RowVersion, ifMatch *RowVersion) error {
+	if ifMatch != nil && *ifMatch != current {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// MemoryRoomRepository는 MemoryStore를 사용하는 RoomRepository입니다.
+type MemoryRoomRepository struct {
+	store *MemoryStore
+}
+
+func (p *MemoryRoomRepository) List(ctx context.Context, companyCode int, filter RoomFilter) ([]Room, error) {
+	var afterKey int
+	if filter.After != nil {
+		var err error
+		if afterKey, err = filter.After.intKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	rooms := []Room{}
+	for key, room := range p.store.rooms {
+		if key[0] != companyCode || filter.Where != nil && !filter.Where.match(room) {
+			continue
+		}
+		if filter.After != nil && (room.RoomCode < afterKey ||
+			room.RoomCode == afterKey && room.AutoIncrement <= filter.After.ID) {
+			continue
+		}
+		rooms = append(rooms, room)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].RoomCode != rooms[j].RoomCode {
+			return rooms[i].RoomCode < rooms[j].RoomCode
+		}
+		return rooms[i].AutoIncrement < rooms[j].AutoIncrement
+	})
+	if filter.Limit > 0 && len(rooms) > filter.Limit {
+		rooms = rooms[:filter.Limit]
+	}
+	return rooms, nil
+}
+
+func (p *MemoryRoomRepository) Count(ctx context.Context, companyCode int, filter RoomFilter) (int, error) {
+	filter.Page = Page{}
+	rooms, err := p.List(ctx, companyCode, filter)
+	return len(rooms), err
+}
+
+func (p *MemoryRoomRepository) Get(ctx context.Context, companyCode, roomCode int) (Room, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	room, ok := p.store.rooms[[2]int{companyCode, roomCode}]
+	if !ok {
+		return Room{}, ErrNotFound
+	}
+	return room, nil
+}
+
+func (p *MemoryRoomRepository) Create(ctx context.Context, room Room) (Room, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	key := [2]int{room.CompanyCode, room.RoomCode}
+	if _, ok := p.store.rooms[key]; ok {
+		return Room{}, ErrDuplicate
+	}
+	if !validRoom(room) {
+		return Room{}, ErrInvalidValue
+	}
+	p.store.nextID++
+	room.AutoIncrement = p.store.nextID
+	room.Version = 1
+	p.store.rooms[key] = room
+	return room, nil
+}
+
+func (p *MemoryRoomRepository) Update(ctx context.Context, companyCode, roomCode int, fields map[string]interface{}, ifMatch *RowVersion) (Room, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	key := [2]int{companyCode, roomCode}
+	room, ok := p.store.rooms[key]
+	if !ok {
+		return Room{}, ErrNotFound
+	}
+	if err := checkVersion(room.rowVersion(), ifMatch); err != nil {
+		return Room{}, err
+	}
+	if err := applyFields(&room, fields, roomUpdateColumns); err != nil {
+		return Room{}, err
+	}
+	if !validRoom(room) {
+		return Room{}, ErrInvalidValue
+	}
+	room.Version++
+	p.store.rooms[key] = room
+	return room, nil
+}
+
+func (p *MemoryRoomRepository) Delete(ctx context.Context, companyCode, roomCode int, cascade bool, ifMatch *RowVersion) (int, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	key := [2]int{companyCode, roomCode}
+	room, ok := p.store.rooms[key]
+	if ok {
+		if err := checkVersion(room.rowVersion(), ifMatch); err != nil {
+			return 0, err
+		}
+	}
+	var seatKeys [][2]int
+	var seatCodes []int
+	for key, seat := range p.store.seats {
+		if key[0] == companyCode && seat.RoomCode == roomCode {
+			seatKeys = append(seatKeys, key)
+			seatCodes = append(seatCodes, key[1])
+		}
+	}
+	if len(seatKeys) > 0 && !cascade {
+		return 0, ErrRoomNotEmpty
+	}
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if err := p.store.checkSeatsNotInUse(companyCode, seatCodes); err != nil {
+		return 0, err
+	}
+	for _, seatKey := range seatKeys {
+		delete(p.store.seats, seatKey)
+	}
+	delete(p.store.rooms, key)
+	return len(seatKeys), nil
+}
+
+func (p *MemoryRoomRepository) ApplyLayout(ctx context.Context, companyCode int, changes LayoutChanges) error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	// 모두 반영할 수 있는지 먼저 확인한 뒤 반영합니다.
+	for _, room := range changes.Rooms {
+		if !validRoom(room) {
+			return fmt.Errorf("room_code %d: %w", room.RoomCode, ErrInvalidValue)
+		}
+	}
+	for _, seat := range changes.Seats {
+		if !validSeat(seat) {
+			return fmt.Errorf("seat_code %d: %w", seat.SeatCode, ErrInvalidValue)
+		}
+	}
+	if err := p.checkLayoutRooms(companyCode, changes); err != nil {
+		return err
+	}
+	if err := p.store.checkSeatsNotInUse(companyCode, changes.DeleteSeats); err != nil {
+		return err
+	}
+
+	for _, room := range changes.Rooms {
+		room.CompanyCode = companyCode
+		key := [2]int{companyCode, room.RoomCode}
+		if current, ok := p.store.rooms[key]; ok {
+			room.AutoIncrement = current.AutoIncrement
+			room.Version = current.Version + 1
+		} else {
+			p.store.nextID++
+			room.AutoIncrement = p.store.nextID
+			room.Version = 1
+		}
+		p.store.rooms[key] = room
+	}
+	for _, seat := range changes.Seats {
+		seat.CompanyCode = companyCode
+		key := [2]int{companyCode, seat.SeatCode}
+		if current, ok := p.store.seats[key]; ok {
+			seat.AutoIncrement = current.AutoIncrement
+			seat.Version = current.Version + 1
+		} else {
+			p.store.nextID++
+			seat.AutoIncrement = p.store.nextID
+			seat.Version = 1
+		}
+		p.store.seats[key] = seat
+	}
+	for _, code := range changes.DeleteSeats {
+		delete(p.store.seats, [2]int{companyCode, code})
+	}
+	for _, code := range changes.DeleteRooms {
+		delete(p.store.rooms, [2]int{companyCode, code})
+	}
+	return nil
+}
+
+// checkLayoutRooms는 changes를 반영한 뒤 모든 seat의 room이 남아 있는지 확인합니다.
+func (p *MemoryRoomRepository) checkLayoutRooms(companyCode int, changes LayoutChanges) error {
+	rooms := make(map[int]bool)
+	for key := range p.store.rooms {
+		if key[0] == companyCode {
+			rooms[key[1]] = true
+		}
+	}
+	for _, room := range changes.Rooms {
+		rooms[room.RoomCode] = true
+	}
+	for _, code := range changes.DeleteRooms {
+		delete(rooms, code)
+	}
+
+	seats := make(map[int]int)
+	for key, seat := range p.store.seats {
+		if key[0] == companyCode {
+			seats[key[1]] = seat.RoomCode
+		}
+	}
+	for _, seat := range changes.Seats {
+		seats[seat.SeatCode] = seat.RoomCode
+	}
+	for _, code := range changes.DeleteSeats {
+		delete(seats, code)
+	}
+	for seatCode, roomCode := range seats {
+		if !rooms[roomCode] {
+			return fmt.Errorf("seat_code %d: %w", seatCode, ErrRoomNotFound)
+		}
+	}
+	return nil
+}
+
+// MemorySeatRepository는 MemoryStore를 사용하는 SeatRepository입니다.
+type MemorySeatRepository struct {
+	store *MemoryStore
+}
+
+// compareSeat은 column 값, 같으면 auto_increment로 seat과 (key, id)를 비교합니다.
+func compareSeat(seat Seat, column, key string, id int) int {
+	var c int
+	if column == "seat_title" {
+		c = strings.Compare(seat.SeatTitle, key)
+	} else {
+		value := seat.AutoIncrement
+		if column == "seat_code" {
+			value = seat.SeatCode
+		}
+		n, _ := strconv.Atoi(key)
+		c = value - n
+	}
+	if c == 0 {
+		c = seat.AutoIncrement - id
+	}
+	return c
+}
+
+func (p *MemorySeatRepository) List(ctx context.Context, companyCode int, filter SeatFilter) ([]Seat, error) {
+	column := filter.sortColumn()
+	if filter.After != nil && column != "seat_title" {
+		if _, err := filter.After.intKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	seats := []Seat{}
+	for key, seat := range p.store.seats {
+		if key[0] != companyCode || filter.Where != nil && !filter.Where.match(seat) {
+			continue
+		}
+		if filter.After != nil {
+			c := compareSeat(seat, column, filter.After.Key, filter.After.ID)
+			if filter.Desc && c >= 0 || !filter.Desc && c <= 0 {
+				continue
+			}
+		}
+		seats = append(seats, seat)
+	}
+
+	sort.Slice(seats, func(i, j int) bool {
+		c := compareSeat(seats[i], column, seatSortKey(seats[j], column), seats[j].AutoIncrement)
+		if filter.Desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	if filter.Limit > 0 && len(seats) > filter.Limit {
+		seats = seats[:filter.Limit]
+	}
+	return seats, nil
+}
+
+func (p *MemorySeatRepository) Count(ctx context.Context, companyCode int, filter SeatFilter) (int, error) {
+	filter.Page = Page{}
+	seats, err := p.List(ctx, companyCode, filter)
+	return len(seats), err
+}
+
+func (p *MemorySeatRepository) Get(ctx context.Context, companyCode, seatCode int) (Seat, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	seat, ok := p.store.seats[[2]int{companyCode, seatCode}]
+	if !ok {
+		return Seat{}, ErrNotFound
+	}
+	return seat, nil
+}
+
+func (p *MemorySeatRepository) Create(ctx context.Context, seat Seat) (Seat, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	key := [2]int{seat.CompanyCode, seat.SeatCode}
+	if _, ok := p.store.seats[key]; ok {
+		return Seat{}, ErrDuplicate
+	}
+	if !validSeat(seat) {
+		return Seat{}, ErrInvalidValue
+	}
+	if !p.store.hasRoom(seat.CompanyCode, seat.RoomCode) {
+		return Seat{}, ErrRoomNotFound
+	}
+	p.store.nextID++
+	seat.AutoIncrement = p.store.nextID
+	seat.Version = 1
+	p.store.seats[key] = seat
+	return seat, nil
+}
+
+func (p *MemorySeatRepository) CreateMany(ctx context.Context, seats []Seat) ([]Seat, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	// 모두 저장할 수 있는지 먼저 확인한 뒤 저장합니다.
+	keys := make(map[[2]int]bool, len(seats))
+	for _, seat := range seats {
+		key := [2]int{seat.CompanyCode, seat.SeatCode}
+		if _, ok := p.store.seats[key]; ok || keys[key] {
+			return nil, fmt.Errorf("seat_code %d: %w", seat.SeatCode, ErrDuplicate)
+		}
+		if !validSeat(seat) {
+			return nil, fmt.Errorf("seat_code %d: %w", seat.SeatCode, ErrInvalidValue)
+		}
+		if !p.store.hasRoom(seat.CompanyCode, seat.RoomCode) {
+			return nil, fmt.Errorf("seat_code %d: %w", seat.SeatCode, ErrRoomNotFound)
+		}
+		keys[key] = true
+	}
+
+	created := make([]Seat, 0, len(seats))
+	for _, seat := range seats {
+		p.store.nextID++
+		seat.AutoIncrement = p.store.nextID
+		seat.Version = 1
+		p.store.seats[[2]int{seat.CompanyCode, seat.SeatCode}] = seat
+		created = append(created, seat)
+	}
+	return created, nil
+}
+
+func (p *MemorySeatRepository) Update(ctx context.Context, companyCode, seatCode int, fields map[string]interface{}, ifMatch *RowVersion) (Seat, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	key := [2]int{companyCode, seatCode}
+	seat, ok := p.store.seats[key]
+	if !ok {
+		return Seat{}, ErrNotFound
+	}
+	if err := checkVersion(seat.rowVersion(), ifMatch); err != nil {
+		return Seat{}, err
+	}
+	if err := applyFields(&seat, fields, seatUpdateColumns); err != nil {
+		return Seat{}, err
+	}
+	if !validSeat(seat) {
+		return Seat{}, ErrInvalidValue
+	}
+	if !p.store.hasRoom(seat.CompanyCode, seat.RoomCode) {
+		return Seat{}, ErrRoomNotFound
+	}
+	seat.Version++
+	p.store.seats[key] = seat
+	return seat, nil
+}
+
+func (p *MemorySeatRepository) Delete(ctx context.Context, companyCode, seatCode int, ifMatch *RowVersion) (Seat, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	key := [2]int{companyCode, seatCode}
+	seat, ok := p.store.seats[key]
+	if !ok {
+		return Seat{}, ErrNotFound
+	}
+	if err := checkVersion(seat.rowVersion(), ifMatch); err != nil {
+		return Seat{}, err
+	}
+	if err := p.store.checkSeatsNotInUse(companyCode, []int{seatCode}); err != nil {
+		return Seat{}, err
+	}
+	delete(p.store.seats, key)
+	return seat, nil
+}
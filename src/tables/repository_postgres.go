@@ -0,0 +1,601 @@
+// repository_postgres.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"AllinB/src/consts"
+)
+
+// roomColumns는 Room 필드 순서의 room_table 컬럼 목록입니다.
+const roomColumns = `auto_increment, company_code, room_code, room_title,
+	title_background_color, title_text_color, room_background_color,
+	room_top, room_left, room_width, room_height,
+	gender, waiting, release, hide_title,
+	transparent_background, hide_border, kiosk_disabled,
+	power_control, breaker_number, version`
+
+// seatColumns는 Seat 필드 순서의 seat_table 컬럼 목록입니다.
+const seatColumns = `auto_increment, company_code, seat_code, room_code, seat_title,
+	title_background_color, title_text_color, seat_background_color,
+	seat_top, seat_left, seat_width, seat_height,
+	gender, waiting, release, hide_title,
+	transparent_background, hide_border, kiosk_disabled,
+	power_control, breaker_number, version`
+
+// roomUpdateColumns는 Update로 변경할 수 있는 room_table 컬럼입니다.
+var roomUpdateColumns = map[string]bool{
+	"room_title":             true,
+	"room_background_color":  true,
+	"room_top":               true,
+	"room_left":              true,
+	"room_width":             true,
+	"room_height":            true,
+	"title_background_color": true,
+	"title_text_color":       true,
+	"gender":                 true,
+	"waiting":                true,
+	"release":                true,
+	"hide_title":             true,
+	"transparent_background": true,
+	"hide_border":            true,
+	"kiosk_disabled":         true,
+	"power_control":          true,
+	"breaker_number":         true,
+}
+
+// seatUpdateColumns는 Update로 변경할 수 있는 seat_table 컬럼입니다.
+var seatUpdateColumns = map[string]bool{
+	"room_code":              true,
+	"seat_title":             true,
+	"seat_background_color":  true,
+	"seat_top":               true,
+	"seat_left":              true,
+	"seat_width":             true,
+	"seat_height":            true,
+	"title_background_color": true,
+	"title_text_color":       true,
+	"gender":                 true,
+	"waiting":                true,
+	"release":                true,
+	"hide_title":             true,
+	"transparent_background": true,
+	"hide_border":            true,
+	"kiosk_disabled":         true,
+	"power_control":          true,
+	"breaker_number":         true,
+}
+
+// scanRoom은 roomColumns 순서로 조회한 행을 Room으로 변환합니다.
+func scanRoom(row interface{ Scan(...interface{}) error }) (Room, error) {
+	var room Room
+	err := row.Scan(&room.AutoIncrement, &room.CompanyCode, &room.RoomCode, &room.RoomTitle,
+		&room.TitleBackgroundColor, &room.TitleTextColor, &room.RoomBackgroundColor,
+		&room.RoomTop, &room.RoomLeft, &room.RoomWidth, &room.RoomHeight,
+		&room.Gender, &room.Waiting, &room.Release, &room.HideTitle,
+		&room.TransparentBackground, &room.HideBorder, &room.KioskDisabled,
+		&room.PowerControl, &room.BreakerNumber, &room.Version)
+	return room, err
+}
+
+// scanSeat은 seatColumns 순서로 조회한 행을 Seat으로 변환합니다.
+func scanSeat(row interface{ Scan(...interface{}) error }) (Seat, error) {
+	var seat Seat
+	err := row.Scan(&seat.AutoIncrement, &seat.CompanyCode, &seat.SeatCode, &seat.RoomCode, &seat.SeatTitle,
+		&seat.TitleBackgroundColor, &seat.TitleTextColor, &seat.SeatBackgroundColor,
+		&seat.SeatTop, &seat.SeatLeft, &seat.SeatWidth, &seat.SeatHeight,
+		&seat.Gender, &seat.Waiting, &seat.Release, &seat.HideTitle,
+		&seat.TransparentBackground, &seat.HideBorder, &seat.KioskDisabled,
+		&seat.PowerControl, &seat.BreakerNumber, &seat.Version)
+	return seat, err
+}
+
+// queryRower는 *sql.DB와 *sql.Tx가 공통으로 제공하는 단일 행 조회 메서드입니다.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// upsertClause는 conflictColumns가 겹치면 allowed 컬럼을 새 값으로 바꾸는 ON CONFLICT 절을 만듭니다.
+func upsertClause(conflictColumns string, allowed map[string]bool) string {
+	columns := make([]string, 0, len(allowed))
+	for column := range allowed {
+		columns = append(columns, column+" = EXCLUDED."+column)
+	}
+	sort.Strings(columns)
+	return " ON CONFLICT (" + conflictColumns + ") DO UPDATE SET " + strings.Join(columns, ", ")
+}
+
+// repoError는 Postgres 오류를 저장소 공통 오류로 변환합니다.
+func repoError(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505": // unique_violation
+			return fmt.Errorf("%w: %s", ErrDuplicate, pqErr.Message)
+		case pqErr.Code == "23514", pqErr.Code.Class() == "22": // check_violation, data_exception
+			return fmt.Errorf("%w: %s", ErrInvalidValue, pqErr.Message)
+		case pqErr.Code == "23503": // foreign_key_violation (seat_room_fk)
+			return fmt.Errorf("%w: %s", ErrRoomNotFound, pqErr.Message)
+		}
+	}
+	return err
+}
+
+// isPQError는 err가 code인 Postgres 오류인지 확인합니다.
+func isPQError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
+
+// updateClause는 허용된 컬럼만으로 "col = $n, ..." 절과 인자를 만듭니다.
+// 컬럼 순서를 정렬하여 같은 요청이 항상 같은 쿼리가 되도록 합니다.
+func updateClause(fields map[string]interface{}, allowed map[string]bool) (string, []interface{}) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if allowed[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	updates := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys)+2)
+	for i, key := range keys {
+		updates = append(updates, key+" = $"+strconv.Itoa(i+1))
+		args = append(args, fields[key])
+	}
+	return strings.Join(updates, ", "), args
+}
+
+// versionCondition은 ifMatch가 있으면 auto_increment/version 일치 조건을 만들고 값을 args 뒤에 추가합니다.
+func versionCondition(ifMatch *RowVersion, args *[]interface{}) string {
+	if ifMatch == nil {
+		return ""
+	}
+	*args = append(*args, ifMatch.AutoIncrement, ifMatch.Version)
+	return fmt.Sprintf(" AND auto_increment = $%d AND version = $%d", len(*args)-1, len(*args))
+}
+
+// versionError는 버전 조건 때문에 바뀐 행이 없을 때의 오류입니다.
+// 행이 남아 있으면 ErrVersionMismatch, 없으면 ErrNotFound입니다.
+func versionError(ctx context.Context, q queryRower, table, codeColumn string, companyCode, code int) error {
+	var exists bool
+	err := q.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM "+table+" WHERE "+codeColumn+" = $1 AND company_code = $2)",
+		code, companyCode).Scan(&exists)
+	switch {
+	case err != nil:
+		return err
+	case exists:
+		return ErrVersionMismatch
+	}
+	return ErrNotFound
+}
+
+// checkSeatsNotInUse는 seatCodes 중 열린 세션, 끝나지 않은 확정 예약, 만료되지 않은 고정석 이용권이 있는 seat이 있으면
+// ErrSeatInUse를 반환합니다. 체크인/예약 생성과 엇갈리지 않도록 seat 행을 삭제해 잠근 뒤 같은 트랜잭션에서 호출합니다.
+func checkSeatsNotInUse(ctx context.Context, q queryRower, companyCode int, seatCodes []int) error {
+	if len(seatCodes) == 0 {
+		return nil
+	}
+	var seatCode sql.NullInt64
+	err := q.QueryRowContext(ctx, `
+		SELECT MIN(seat_code) FROM (
+			SELECT seat_code FROM seat_session_table
+			WHERE company_code = $1 AND seat_code = ANY($2) AND end_time IS NULL
+			UNION ALL
+			SELECT seat_code FROM reservation_table
+			WHERE company_code = $1 AND seat_code = ANY($2) AND status = $3 AND end_time > $4
+			UNION ALL
+			SELECT seat_code FROM pass_table
+			WHERE company_code = $1 AND seat_code = ANY($2) AND (expires_at IS NULL OR expires_at > $4)
+		) used`,
+		companyCode, pq.Array(seatCodes), consts.RESERVATION_STATUS_BOOKED, time.Now()).Scan(&seatCode)
+	if err != nil {
+		return err
+	}
+	if seatCode.Valid {
+		return fmt.Errorf("seat_code %d: %w", seatCode.Int64, ErrSeatInUse)
+	}
+	return nil
+}
+
+// whereConditions는 company_code 조건과 where 조건식을 WHERE 절 조건 목록과 인자로 만듭니다.
+func whereConditions(companyCode int, where Filter) ([]string, []interface{}) {
+	conditions := []string{"company_code = $1"}
+	args := []interface{}{companyCode}
+	if where != nil {
+		conditions = append(conditions, where.sql(&args))
+	}
+	return conditions, args
+}
+
+// PostgresRoomRepository는 room_table을 사용하는 RoomRepository입니다.
+type PostgresRoomRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRoomRepository는 db를 사용하는 RoomRepository를 생성합니다.
+func NewPostgresRoomRepository(db *sql.DB) *PostgresRoomRepository {
+	return &PostgresRoomRepository{db: db}
+}
+
+func (p *PostgresRoomRepository) List(ctx context.Context, companyCode int, filter RoomFilter) ([]Room, error) {
+	conditions, args := whereConditions(companyCode, filter.Where)
+	if filter.After != nil {
+		key, err := filter.After.intKey()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, key, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(room_code, auto_increment) > ($%d, $%d)", len(args)-1, len(args)))
+	}
+	query := "SELECT " + roomColumns + " FROM room_table WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY room_code ASC, auto_increment ASC"
+	if filter.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filter.Limit)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := []Room{}
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+	return rooms, rows.Err()
+}
+
+func (p *PostgresRoomRepository) Count(ctx context.Context, companyCode int, filter RoomFilter) (int, error) {
+	conditions, args := whereConditions(companyCode, filter.Where)
+	var count int
+	err := p.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM room_table WHERE "+strings.Join(conditions, " AND "), args...).Scan(&count)
+	return count, err
+}
+
+func (p *PostgresRoomRepository) Get(ctx context.Context, companyCode, roomCode int) (Room, error) {
+	room, err := scanRoom(p.db.QueryRowContext(ctx,
+		"SELECT "+roomColumns+" FROM room_table WHERE room_code = $1 AND company_code = $2",
+		roomCode, companyCode))
+	return room, repoError(err)
+}
+
+// roomInsertQuery는 room 하나를 저장하는 쿼리입니다. 호출자가 ON CONFLICT/RETURNING 절을 덧붙입니다.
+// version은 컬럼 기본값(1)으로 시작합니다.
+const roomInsertQuery = `
+	INSERT INTO room_table
+	(company_code, room_code, room_title,
+	 title_background_color, title_text_color, room_background_color,
+	 room_top, room_left, room_width, room_height,
+	 gender, waiting, release, hide_title,
+	 transparent_background, hide_border, kiosk_disabled,
+	 power_control, breaker_number)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+	        $12, $13, $14, $15, $16, $17, $18, $19)`
+
+// insertRoom은 db 또는 트랜잭션으로 room 하나를 저장합니다.
+// conflict가 비어 있지 않으면 INSERT 뒤에 덧붙입니다. (예: ON CONFLICT ... DO UPDATE)
+func insertRoom(ctx context.Context, q queryRower, room Room, conflict string) (Room, error) {
+	err := q.QueryRowContext(ctx, roomInsertQuery+conflict+" RETURNING auto_increment, version",
+		room.CompanyCode, room.RoomCode, room.RoomTitle,
+		room.TitleBackgroundColor, room.TitleTextColor, room.RoomBackgroundColor,
+		room.RoomTop, room.RoomLeft, room.RoomWidth, room.RoomHeight,
+		room.Gender, room.Waiting, room.Release, room.HideTitle,
+		room.TransparentBackground, room.HideBorder, room.KioskDisabled,
+		room.PowerControl, room.BreakerNumber).Scan(&room.AutoIncrement, &room.Version)
+	return room, repoError(err)
+}
+
+func (p *PostgresRoomRepository) Create(ctx context.Context, room Room) (Room, error) {
+	return insertRoom(ctx, p.db, room, "")
+}
+
+func (p *PostgresRoomRepository) Update(ctx context.Context, companyCode, roomCode int, fields map[string]interface{}, ifMatch *RowVersion) (Room, error) {
+	set, args := updateClause(fields, roomUpdateColumns)
+	if set == "" {
+		return Room{}, fmt.Errorf("%w: 변경할 컬럼이 없습니다", ErrInvalidValue)
+	}
+	args = append(args, roomCode, companyCode)
+	query := "UPDATE room_table SET " + set + ", version = version + 1" +
+		" WHERE room_code = $" + strconv.Itoa(len(args)-1) + " AND company_code = $" + strconv.Itoa(len(args)) +
+		versionCondition(ifMatch, &args) +
+		" RETURNING " + roomColumns
+	room, err := scanRoom(p.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows && ifMatch != nil {
+		return Room{}, versionError(ctx, p.db, "room_table", "room_code", companyCode, roomCode)
+	}
+	return room, repoError(err)
+}
+
+func (p *PostgresRoomRepository) Delete(ctx context.Context, companyCode, roomCode int, cascade bool, ifMatch *RowVersion) (int, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// 버전 조건이 있으면 seat을 지우기 전에 room을 잠그고 버전을 확인합니다.
+	if ifMatch != nil {
+		var current RowVersion
+		err := tx.QueryRowContext(ctx,
+			"SELECT auto_increment, version FROM room_table WHERE room_code = $1 AND company_code = $2 FOR UPDATE",
+			roomCode, companyCode).Scan(&current.AutoIncrement, &current.Version)
+		if err != nil {
+			return 0, repoError(err)
+		}
+		if current != *ifMatch {
+			return 0, ErrVersionMismatch
+		}
+	}
+
+	var seatCount int
+	err = tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM seat_table WHERE room_code = $1 AND company_code = $2",
+		roomCode, companyCode).Scan(&seatCount)
+	if err != nil {
+		return 0, err
+	}
+	if seatCount > 0 {
+		if !cascade {
+			return 0, ErrRoomNotEmpty
+		}
+		rows, err := tx.QueryContext(ctx,
+			"DELETE FROM seat_table WHERE room_code = $1 AND company_code = $2 RETURNING seat_code",
+			roomCode, companyCode)
+		if err != nil {
+			return 0, err
+		}
+		var seatCodes []int
+		for rows.Next() {
+			var seatCode int
+			if err := rows.Scan(&seatCode); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			seatCodes = append(seatCodes, seatCode)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		if err := checkSeatsNotInUse(ctx, tx, companyCode, seatCodes); err != nil {
+			return 0, err
+		}
+	}
+
+	// 개수를 센 뒤 다른 요청이 seat을 추가했다면 seat_room_fk 위반으로 삭제가 실패합니다.
+	res, err := tx.ExecContext(ctx,
+		"DELETE FROM room_table WHERE room_code = $1 AND company_code = $2", roomCode, companyCode)
+	if err != nil {
+		if err = repoError(err); errors.Is(err, ErrRoomNotFound) {
+			return 0, ErrRoomNotEmpty
+		}
+		return 0, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, ErrNotFound
+	}
+	return seatCount, tx.Commit()
+}
+
+func (p *PostgresRoomRepository) ApplyLayout(ctx context.Context, companyCode int, changes LayoutChanges) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	roomUpsert := upsertClause("company_code, room_code", roomUpdateColumns) + ", version = room_table.version + 1"
+	for _, room := range changes.Rooms {
+		room.CompanyCode = companyCode
+		if _, err := insertRoom(ctx, tx, room, roomUpsert); err != nil {
+			return fmt.Errorf("room_code %d: %w", room.RoomCode, err)
+		}
+	}
+	seatUpsert := upsertClause("company_code, seat_code", seatUpdateColumns) + ", version = seat_table.version + 1"
+	for _, seat := range changes.Seats {
+		seat.CompanyCode = companyCode
+		if _, err := insertSeat(ctx, tx, seat, seatUpsert); err != nil {
+			return fmt.Errorf("seat_code %d: %w", seat.SeatCode, err)
+		}
+	}
+	if len(changes.DeleteSeats) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM seat_table WHERE company_code = $1 AND seat_code = ANY($2)",
+			companyCode, pq.Array(changes.DeleteSeats)); err != nil {
+			return err
+		}
+		if err := checkSeatsNotInUse(ctx, tx, companyCode, changes.DeleteSeats); err != nil {
+			return err
+		}
+	}
+	if len(changes.DeleteRooms) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM room_table WHERE company_code = $1 AND room_code = ANY($2)",
+			companyCode, pq.Array(changes.DeleteRooms)); err != nil {
+			return repoError(err)
+		}
+	}
+	return tx.Commit()
+}
+
+// PostgresSeatRepository는 seat_table을 사용하는 SeatRepository입니다.
+type PostgresSeatRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSeatRepository는 db를 사용하는 SeatRepository를 생성합니다.
+func NewPostgresSeatRepository(db *sql.DB) *PostgresSeatRepository {
+	return &PostgresSeatRepository{db: db}
+}
+
+func (p *PostgresSeatRepository) List(ctx context.Context, companyCode int, filter SeatFilter) ([]Seat, error) {
+	conditions, args := whereConditions(companyCode, filter.Where)
+
+	orderBy := filter.sortColumn()
+	direction, compare := "ASC", ">"
+	if filter.Desc {
+		direction, compare = "DESC", "<"
+	}
+	if filter.After != nil {
+		var key interface{} = filter.After.Key
+		if orderBy != "seat_title" {
+			n, err := filter.After.intKey()
+			if err != nil {
+				return nil, err
+			}
+			key = n
+		}
+		args = append(args, key, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, auto_increment) %s ($%d, $%d)",
+			orderBy, compare, len(args)-1, len(args)))
+	}
+
+	query := "SELECT " + seatColumns + " FROM seat_table WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY " + orderBy + " " + direction + ", auto_increment " + direction
+	if filter.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filter.Limit)
+	}
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seats := []Seat{}
+	for rows.Next() {
+		seat, err := scanSeat(rows)
+		if err != nil {
+			return nil, err
+		}
+		seats = append(seats, seat)
+	}
+	return seats, rows.Err()
+}
+
+func (p *PostgresSeatRepository) Count(ctx context.Context, companyCode int, filter SeatFilter) (int, error) {
+	conditions, args := whereConditions(companyCode, filter.Where)
+	var count int
+	err := p.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM seat_table WHERE "+strings.Join(conditions, " AND "), args...).Scan(&count)
+	return count, err
+}
+
+func (p *PostgresSeatRepository) Get(ctx context.Context, companyCode, seatCode int) (Seat, error) {
+	seat, err := scanSeat(p.db.QueryRowContext(ctx,
+		"SELECT "+seatColumns+" FROM seat_table WHERE seat_code = $1 AND company_code = $2",
+		seatCode, companyCode))
+	return seat, repoError(err)
+}
+
+// seatInsertQuery는 seat 하나를 저장하는 쿼리입니다. 호출자가 ON CONFLICT/RETURNING 절을 덧붙입니다.
+// version은 컬럼 기본값(1)으로 시작합니다.
+const seatInsertQuery = `
+	INSERT INTO seat_table
+	(company_code, seat_code, room_code, seat_title,
+	 title_background_color, title_text_color, seat_background_color,
+	 seat_top, seat_left, seat_width, seat_height,
+	 gender, waiting, release, hide_title,
+	 transparent_background, hide_border, kiosk_disabled,
+	 power_control, breaker_number)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+	        $12, $13, $14, $15, $16, $17, $18, $19, $20)`
+
+// insertSeat은 db 또는 트랜잭션으로 seat 하나를 저장합니다.
+// conflict가 비어 있지 않으면 INSERT 뒤에 덧붙입니다. (예: ON CONFLICT ... DO UPDATE)
+func insertSeat(ctx context.Context, q queryRower, seat Seat, conflict string) (Seat, error) {
+	err := q.QueryRowContext(ctx, seatInsertQuery+conflict+" RETURNING auto_increment, version",
+		seat.CompanyCode, seat.SeatCode, seat.RoomCode, seat.SeatTitle,
+		seat.TitleBackgroundColor, seat.TitleTextColor, seat.SeatBackgroundColor,
+		seat.SeatTop, seat.SeatLeft, seat.SeatWidth, seat.SeatHeight,
+		seat.Gender, seat.Waiting, seat.Release, seat.HideTitle,
+		seat.TransparentBackground, seat.HideBorder, seat.KioskDisabled,
+		seat.PowerControl, seat.BreakerNumber).Scan(&seat.AutoIncrement, &seat.Version)
+	return seat, repoError(err)
+}
+
+func (p *PostgresSeatRepository) Create(ctx context.Context, seat Seat) (Seat, error) {
+	return insertSeat(ctx, p.db, seat, "")
+}
+
+func (p *PostgresSeatRepository) CreateMany(ctx context.Context, seats []Seat) ([]Seat, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created := make([]Seat, 0, len(seats))
+	for _, seat := range seats {
+		seat, err := insertSeat(ctx, tx, seat, "")
+		if err != nil {
+			return nil, fmt.Errorf("seat_code %d: %w", seat.SeatCode, err)
+		}
+		created = append(created, seat)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (p *PostgresSeatRepository) Update(ctx context.Context, companyCode, seatCode int, fields map[string]interface{}, ifMatch *RowVersion) (Seat, error) {
+	set, args := updateClause(fields, seatUpdateColumns)
+	if set == "" {
+		return Seat{}, fmt.Errorf("%w: 변경할 컬럼이 없습니다", ErrInvalidValue)
+	}
+	args = append(args, seatCode, companyCode)
+	query := "UPDATE seat_table SET " + set + ", version = version + 1" +
+		" WHERE seat_code = $" + strconv.Itoa(len(args)-1) + " AND company_code = $" + strconv.Itoa(len(args)) +
+		versionCondition(ifMatch, &args) +
+		" RETURNING " + seatColumns
+	seat, err := scanSeat(p.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows && ifMatch != nil {
+		return Seat{}, versionError(ctx, p.db, "seat_table", "seat_code", companyCode, seatCode)
+	}
+	return seat, repoError(err)
+}
+
+func (p *PostgresSeatRepository) Delete(ctx context.Context, companyCode, seatCode int, ifMatch *RowVersion) (Seat, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Seat{}, err
+	}
+	defer tx.Rollback()
+
+	args := []interface{}{seatCode, companyCode}
+	query := "DELETE FROM seat_table WHERE seat_code = $1 AND company_code = $2" +
+		versionCondition(ifMatch, &args) +
+		" RETURNING " + seatColumns
+	seat, err := scanSeat(tx.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows && ifMatch != nil {
+		return Seat{}, versionError(ctx, tx, "seat_table", "seat_code", companyCode, seatCode)
+	}
+	if err != nil {
+		return Seat{}, repoError(err)
+	}
+	if err := checkSeatsNotInUse(ctx, tx, companyCode, []int{seatCode}); err != nil {
+		return Seat{}, err
+	}
+	return seat, tx.Commit()
+}
@@ -0,0 +1,417 @@
+// reservation.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// Reservation 구조체는 reservation_table의 각 컬럼을 매핑합니다.
+type Reservation struct {
+	ReservationID int       `json:"reservation_id" db:"reservation_id"`
+	CompanyCode   int       `json:"company_code"`
+	SeatCode      int       `json:"seat_code"`
+	MemberID      int       `json:"member_id"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Status        int       `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RegisterReservationRoutes는 좌석 예약 관련 엔드포인트를 등록합니다.
+// 회원은 본인 명의로만 예약/조회/취소할 수 있으며, 좌석별 예약 목록은 점주/직원만 조회합니다.
+func RegisterReservationRoutes(r *mux.Router) {
+	r.Handle("/seats/{seat_code}/reservations", allow(GetSeatReservations, staffRoles...)).Methods("GET")
+	r.Handle("/seats/{seat_code}/reservations", allow(CreateReservation, memberRoles...)).Methods("POST")
+	r.Handle("/members/{member_id}/reservations", allow(GetMemberReservations, memberRoles...)).Methods("GET")
+	r.Handle("/reservations/{reservation_id}/cancel", allow(CancelReservation, memberRoles...)).Methods("POST")
+}
+
+// RegisterReservationJobs는 예약 관련 비동기 작업 처리 함수를 등록합니다.
+func RegisterReservationJobs() {
+	utils.RegisterJobHandler("ReservationNoShowRelease", releaseNoShowReservations)
+}
+
+const reservationColumns = `reservation_id, company_code, seat_code, member_id,
+	start_time, end_time, status, created_at`
+
+// scanReservation은 reservationColumns 순서로 조회한 행을 Reservation으로 변환합니다.
+func scanReservation(row interface{ Scan(...interface{}) error }) (Reservation, error) {
+	var rv Reservation
+	err := row.Scan(&rv.ReservationID, &rv.CompanyCode, &rv.SeatCode, &rv.MemberID,
+		&rv.StartTime, &rv.EndTime, &rv.Status, &rv.CreatedAt)
+	return rv, err
+}
+
+// createReservationRequest는 예약 생성 요청 본문입니다.
+type createReservationRequest struct {
+	MemberID  int       `json:"member_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// CreateReservation: 좌석의 미래 시간대를 예약합니다.
+// 같은 좌석의 다른 예약이나 다른 회원의 사용 중인 세션(예정 종료 시각까지)과 겹치면 409를 반환합니다.
+func CreateReservation(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	seatCode, err := strconv.Atoi(mux.Vars(r)["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req createReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if !bindCallerMember(w, r, &req.MemberID) {
+		return
+	}
+	if !req.EndTime.After(req.StartTime) {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "end_time은 start_time 이후여야 합니다.")
+		return
+	}
+	if !req.StartTime.After(time.Now()) {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "지난 시간은 예약할 수 없습니다.")
+		return
+	}
+
+	member, err := getMember(ctx, companyCode, req.MemberID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "존재하지 않는 member_id입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if member.Status != consts.MEMBER_STATUS_ACTIVE {
+		writeProblem(w, consts.ERR_MEMBER_INACTIVE, "이용할 수 없는 회원 상태입니다.")
+		return
+	}
+
+	// seat 존재 여부와 성별 제한을 함께 확인합니다.
+	allowed, err := seatGenderAllowed(ctx, companyCode, seatCode, member.Gender)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if !allowed {
+		writeProblem(w, consts.ERR_GENDER_MISMATCH, "좌석의 성별 제한과 회원 성별이 일치하지 않습니다.")
+		return
+	}
+
+	// 좌석 행을 잠가 체크인과 엇갈리지 않게 한 뒤, 다른 회원의 사용 중인 세션과 겹치는지 확인합니다.
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := lockSeat(ctx, tx, companyCode, seatCode); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	var occupied bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM seat_session_table
+			WHERE company_code = $1 AND seat_code = $2 AND end_time IS NULL
+			  AND member_id <> $3
+			  AND (planned_end_time IS NULL OR planned_end_time > $4))`,
+		companyCode, seatCode, member.MemberID, req.StartTime).Scan(&occupied)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if occupied {
+		writeProblem(w, consts.ERR_SEAT_RESERVED, "해당 시간대에 좌석을 사용 중인 회원이 있습니다.")
+		return
+	}
+
+	rv := Reservation{
+		CompanyCode: member.CompanyCode,
+		SeatCode:    seatCode,
+		MemberID:    member.MemberID,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Status:      consts.RESERVATION_STATUS_BOOKED,
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO reservation_table
+		(company_code, seat_code, member_id, start_time, end_time, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING reservation_id, created_at`,
+		rv.CompanyCode, rv.SeatCode, rv.MemberID, rv.StartTime, rv.EndTime, rv.Status).
+		Scan(&rv.ReservationID, &rv.CreatedAt)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		if isPQError(err, "23P01") { // exclusion_violation (reservation_no_overlap)
+			writeProblem(w, consts.ERR_SEAT_RESERVED, "해당 시간대에 이미 예약이 있습니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rv)
+}
+
+// CancelReservation: 예약을 취소합니다. 확정 상태의 예약만 취소할 수 있습니다.
+// 회원은 본인의 예약만 취소할 수 있습니다.
+func CancelReservation(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	reservationID, err := strconv.Atoi(mux.Vars(r)["reservation_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 reservation_id")
+		return
+	}
+
+	// 회원 호출자는 본인 예약으로 한정합니다. (0이면 조건 없음)
+	ownerID, _ := callerMemberID(r)
+	row := utils.DB.QueryRowContext(ctx, `
+		UPDATE reservation_table SET status = $1
+		WHERE reservation_id = $2 AND company_code = $3 AND status = $4
+		  AND ($5 = 0 OR member_id = $5)
+		RETURNING `+reservationColumns,
+		consts.RESERVATION_STATUS_CANCELLED, reservationID, utils.CompanyCode(r.Context()),
+		consts.RESERVATION_STATUS_BOOKED, ownerID)
+	rv, err := scanReservation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_CONFLICT, "취소할 수 있는 예약이 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	// 예약이 풀린 좌석을 대기자에게 배정합니다.
+	enqueueWaitlistPromote(rv.CompanyCode, rv.SeatCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rv)
+}
+
+// GetSeatReservations: 좌석의 예약 목록을 조회합니다.
+func GetSeatReservations(w http.ResponseWriter, r *http.Request) {
+	seatCode, err := strconv.Atoi(mux.Vars(r)["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	listReservations(w, r, "seat_code", seatCode)
+}
+
+// GetMemberReservations: 회원의 예약 목록을 조회합니다.
+func GetMemberReservations(w http.ResponseWriter, r *http.Request) {
+	memberID, err := strconv.Atoi(mux.Vars(r)["member_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 member_id")
+		return
+	}
+	if !bindCallerMember(w, r, &memberID) {
+		return
+	}
+	listReservations(w, r, "member_id", memberID)
+}
+
+// validReservationStatus는 status가 RESERVATION_STATUS_* 값인지 확인합니다.
+func validReservationStatus(status int) bool {
+	switch status {
+	case consts.RESERVATION_STATUS_BOOKED, consts.RESERVATION_STATUS_CANCELLED,
+		consts.RESERVATION_STATUS_CHECKED_IN, consts.RESERVATION_STATUS_NO_SHOW:
+		return true
+	}
+	return false
+}
+
+// listReservations는 호출자의 company_code에서 keyField = keyValue 조건으로 예약 목록을 반환합니다.
+// status, from(이 시각 이후 종료), to(이 시각 이전 시작) 쿼리 파라미터로 필터링할 수 있습니다.
+func listReservations(w http.ResponseWriter, r *http.Request, keyField string, keyValue int) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	filters := []string{"company_code = $1", keyField + " = $2"}
+	args := []interface{}{utils.CompanyCode(r.Context()), keyValue}
+	paramIdx := 3
+
+	if v := r.URL.Query().Get("status"); v != "" {
+		status, err := strconv.Atoi(v)
+		if err != nil || !validReservationStatus(status) {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 status 값")
+			return
+		}
+		filters = append(filters, fmt.Sprintf("status = $%d", paramIdx))
+		args = append(args, status)
+		paramIdx++
+	}
+	if from := r.URL.Query().Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 from 값")
+			return
+		}
+		filters = append(filters, fmt.Sprintf("end_time > $%d", paramIdx))
+		args = append(args, t)
+		paramIdx++
+	}
+	if to := r.URL.Query().Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 to 값")
+			return
+		}
+		filters = append(filters, fmt.Sprintf("start_time < $%d", paramIdx))
+		args = append(args, t)
+		paramIdx++
+	}
+
+	query := "SELECT " + reservationColumns + " FROM reservation_table WHERE " +
+		strings.Join(filters, " AND ") + " ORDER BY start_time ASC"
+	rows, err := utils.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []Reservation{}
+	for rows.Next() {
+		rv, err := scanReservation(rows)
+		if err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, rv)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// claimReservation은 체크인 시점에 좌석에 걸린 예약을 확인합니다.
+// 다른 회원의 예약 시간대이면 reservedByOther가 true이며,
+// 본인의 예약이면 해당 예약 ID를 반환합니다. (없으면 0)
+func claimReservation(ctx context.Context, tx *sql.Tx, companyCode, seatCode, memberID int, now time.Time) (ownID int, reservedByOther bool, err error) {
+	grace := time.Duration(consts.RESERVATION_NO_SHOW_GRACE_MINUTES) * time.Minute
+	rows, err := tx.QueryContext(ctx, `
+		SELECT reservation_id, member_id FROM reservation_table
+		WHERE company_code = $1 AND seat_code = $2 AND status = $3
+		  AND start_time <= $4 AND end_time > $5`,
+		companyCode, seatCode, consts.RESERVATION_STATUS_BOOKED, now.Add(grace), now)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, owner int
+		if err := rows.Scan(&id, &owner); err != nil {
+			return 0, false, err
+		}
+		if owner == memberID {
+			ownID = id
+		} else {
+			reservedByOther = true
+		}
+	}
+	return ownID, reservedByOther, rows.Err()
+}
+
+// nextReservationStart는 now 이후에 시작하는 다른 회원의 가장 빠른 확정 예약 시작 시각을 반환합니다. (없으면 nil)
+// 예약 없이 체크인한 세션이 다음 예약 시간대를 침범하지 않도록 예정 종료 시각을 제한할 때 사용합니다.
+func nextReservationStart(ctx context.Context, tx *sql.Tx, companyCode, seatCode, memberID int, now time.Time) (*time.Time, error) {
+	var start time.Time
+	err := tx.QueryRowContext(ctx, `
+		SELECT start_time FROM reservation_table
+		WHERE company_code = $1 AND seat_code = $2 AND status = $3
+		  AND member_id <> $4 AND start_time > $5
+		ORDER BY start_time ASC
+		LIMIT 1`,
+		companyCode, seatCode, consts.RESERVATION_STATUS_BOOKED, memberID, now).Scan(&start)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &start, nil
+}
+
+// markReservationCheckedIn은 예약을 체크인 상태로 변경합니다.
+func markReservationCheckedIn(ctx context.Context, tx *sql.Tx, reservationID int) error {
+	_, err := tx.ExecContext(ctx,
+		"UPDATE reservation_table SET status = $1 WHERE reservation_id = $2 AND status = $3",
+		consts.RESERVATION_STATUS_CHECKED_IN, reservationID, consts.RESERVATION_STATUS_BOOKED)
+	return err
+}
+
+// releaseNoShowReservations는 시작 후 유예 시간이 지나도록 체크인하지 않은 예약을 노쇼로 해제합니다.
+// ReservationNoShowRelease 작업으로 주기 실행됩니다.
+func releaseNoShowReservations(ctx context.Context, job utils.Job) error {
+	grace := time.Duration(consts.RESERVATION_NO_SHOW_GRACE_MINUTES) * time.Minute
+	rows, err := utils.DB.QueryContext(ctx, `
+		UPDATE reservation_table SET status = $1
+		WHERE status = $2 AND start_time < $3
+		RETURNING reservation_id, company_code, seat_code, member_id, `+seatRoomCodeExpr("reservation_table"),
+		consts.RESERVATION_STATUS_NO_SHOW, consts.RESERVATION_STATUS_BOOKED, time.Now().Add(-grace))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	released := 0
+	for rows.Next() {
+		var reservationID, companyCode, seatCode, memberID, roomCode int
+		if err := rows.Scan(&reservationID, &companyCode, &seatCode, &memberID, &roomCode); err != nil {
+			return err
+		}
+		released++
+		enqueueEvent("ReservationReleased", map[string]interface{}{
+			"reservation_id": reservationID,
+			"company_code":   companyCode,
+			"room_code":      roomCode,
+			"seat_code":      seatCode,
+			"member_id":      memberID,
+		})
+		enqueueWaitlistPromote(companyCode, seatCode)
+	}
+	if released > 0 {
+		log.Printf("노쇼 예약 %d건 해제", released)
+	}
+	return rows.Err()
+}
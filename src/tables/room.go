@@ -3,8 +3,8 @@ package tables
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -40,19 +40,31 @@ type Room struct {
 	KioskDisabled         int    `json:"kiosk_disabled"`
 	PowerControl          int    `json:"power_control"`
 	BreakerNumber         int    `json:"breaker_number"`
+	Version               int    `json:"version"`
+}
+
+// rowVersion은 ETag로 사용할 room의 버전입니다.
+func (room Room) rowVersion() RowVersion {
+	return RowVersion{AutoIncrement: room.AutoIncrement, Version: room.Version}
 }
 
 // RegisterRoomRoutes는 room_table 관련 엔드포인트를 등록합니다.
+// 조회는 모든 역할, 생성/수정은 점주/직원, 삭제는 점주만 할 수 있습니다.
+// 키오스크는 kiosk_disabled가 설정된 room을 조회할 수 없습니다.
 func RegisterRoomRoutes(r *mux.Router) {
-	r.HandleFunc("/rooms", GetRooms).Methods("GET")
-	r.HandleFunc("/rooms/{room_code}", GetRoom).Methods("GET")
-	r.HandleFunc("/rooms", CreateRoom).Methods("POST")
-	// UpdateRoom은 전체/부분 업데이트를 모두 지원합니다.
-	r.HandleFunc("/rooms/{room_code}", UpdateRoom).Methods("PUT")
-	r.HandleFunc("/rooms/{room_code}", DeleteRoom).Methods("DELETE")
+	r.Handle("/rooms", allow(GetRooms, anyRole...)).Methods("GET")
+	r.Handle("/rooms/{room_code}", allow(GetRoom, anyRole...)).Methods("GET")
+	r.Handle("/rooms", allow(CreateRoom, staffRoles...)).Methods("POST")
+	// PUT은 전체 교체, PATCH는 merge patch/JSON Patch로 일부 필드만 변경합니다.
+	r.Handle("/rooms/{room_code}", allow(UpdateRoom, staffRoles...)).Methods("PUT")
+	r.Handle("/rooms/{room_code}", allow(PatchRoom, staffRoles...)).Methods("PATCH")
+	r.Handle("/rooms/{room_code}", allow(DeleteRoom, ownerOnly...)).Methods("DELETE")
 }
 
 // GetRooms: "X-Fields" 헤더에 지정된 필드만 조회하거나 전체 필드를 조회합니다.
+// URL 쿼리 파라미터(컬럼명=값, filter 조건식)를 통해 필터링 기능도 지원합니다.
+// limit/cursor 쿼리를 보내면 {"items", "next_cursor"} 형식으로 페이지를 나누어 응답하며,
+// 보내지 않으면 기존처럼 전체 목록을 배열로 응답합니다. include_total=true이면 X-Total-Count 헤더를 추가합니다.
 func GetRooms(w http.ResponseWriter, r *http.Request) {
 	// 요청 컨텍스트에 10초 타임아웃 설정
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
@@ -65,73 +77,72 @@ func GetRooms(w http.ResponseWriter, r *http.Request) {
 		"room_top", "room_left", "room_width", "room_height",
 		"gender", "waiting", "release", "hide_title",
 		"transparent_background", "hide_border", "kiosk_disabled",
-		"power_control", "breaker_number",
+		"power_control", "breaker_number", "version",
 	}
+	fields := selectFields(r.Header.Get("X-Fields"), allowedFields)
 
-	fieldsHeader := r.Header.Get("X-Fields")
-	var fields []string
-	if fieldsHeader != "" {
-		requested := strings.Split(fieldsHeader, ",")
-		allowedSet := make(map[string]bool)
-		for _, f := range allowedFields {
-			allowedSet[f] = true
-		}
-		for _, f := range requested {
-			f = strings.TrimSpace(f)
-			if allowedSet[f] {
-				fields = append(fields, f)
-			}
-		}
-		if len(fields) == 0 {
-			fields = allowedFields
-		}
-	} else {
-		fields = allowedFields
+	// 필터링 조건 처리 (parseFilter 참고)
+	where, err := parseFilter(r, roomFilterColumns)
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, err.Error())
+		return
 	}
 
-	query := "SELECT " + strings.Join(fields, ", ") + " FROM room_table"
-	rows, err := utils.DB.QueryContext(ctx, query)
+	// limit/cursor를 보내면 room_code 순으로 페이지를 나누어 응답합니다.
+	page, paged, err := parsePage(r, "room_code")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, err.Error())
 		return
 	}
-	defer rows.Close()
+	// 다음 페이지가 있는지 알기 위해 하나 더 조회합니다.
+	limit := page.Limit
+	if paged {
+		page.Limit++
+	}
 
-	columns, err := rows.Columns()
+	// 호출자의 company_code에 속한 room만 조회합니다.
+	companyCode := utils.CompanyCode(r.Context())
+	filter := RoomFilter{Where: kioskRoomFilter(r, where), Page: page}
+	rooms, err := roomRepo.List(ctx, companyCode, filter)
+	if errors.Is(err, errInvalidCursor) {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, errInvalidCursor.Error())
+		return
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeInternalError(w, err)
 		return
 	}
-	result := []map[string]interface{}{}
-	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-		if err := rows.Scan(valuePtrs...); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+	var nextCursor *string
+	if paged && len(rooms) > limit {
+		rooms = rooms[:limit]
+		last := rooms[limit-1]
+		next := encodeCursor(Cursor{Sort: "room_code", Key: strconv.Itoa(last.RoomCode), ID: last.AutoIncrement})
+		nextCursor = &next
+	}
+	result, err := projectFields(rooms, fields)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	if includeTotal(r) {
+		total, err := roomRepo.Count(ctx, companyCode, filter)
+		if err != nil {
+			writeInternalError(w, err)
 			return
 		}
-		rowMap := make(map[string]interface{})
-		for i, col := range columns {
-			var v interface{}
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				v = string(b)
-			} else {
-				v = val
-			}
-			rowMap[col] = v
-		}
-		result = append(result, rowMap)
+		setTotalCount(w, total)
 	}
-
 	w.Header().Set("Content-Type", "application/json")
+	if paged {
+		json.NewEncoder(w).Encode(pageResponse{Items: result, NextCursor: nextCursor})
+		return
+	}
 	json.NewEncoder(w).Encode(result)
 }
 
 // GetRoom: 단일 room을 전체 필드로 조회합니다.
+// 응답에 ETag를 넣으며, If-None-Match가 현재 ETag와 같으면 본문 없이 304를 반환합니다.
 func GetRoom(w http.ResponseWriter, r *http.Request) {
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
@@ -140,31 +151,22 @@ func GetRoom(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	roomCode, err := strconv.Atoi(vars["room_code"])
 	if err != nil {
-		http.Error(w, "잘못된 room_code", http.StatusBadRequest)
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
 		return
 	}
 
-	var room Room
-	err = utils.DB.QueryRowContext(ctx, `
-		SELECT auto_increment, company_code, room_code, room_title,
-		       title_background_color, title_text_color, room_background_color,
-		       room_top, room_left, room_width, room_height,
-		       gender, waiting, release, hide_title,
-		       transparent_background, hide_border, kiosk_disabled,
-		       power_control, breaker_number
-		FROM room_table WHERE room_code = $1`, roomCode).
-		Scan(&room.AutoIncrement, &room.CompanyCode, &room.RoomCode, &room.RoomTitle,
-			&room.TitleBackgroundColor, &room.TitleTextColor, &room.RoomBackgroundColor,
-			&room.RoomTop, &room.RoomLeft, &room.RoomWidth, &room.RoomHeight,
-			&room.Gender, &room.Waiting, &room.Release, &room.HideTitle,
-			&room.TransparentBackground, &room.HideBorder, &room.KioskDisabled,
-			&room.PowerControl, &room.BreakerNumber)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			http.Error(w, "Room을 찾을 수 없습니다.", http.StatusNotFound)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+	room, err := roomRepo.Get(ctx, utils.CompanyCode(r.Context()), roomCode)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		writeInternalError(w, err)
+		return
+	}
+	// 키오스크에서 숨긴 room은 없는 room처럼 응답합니다.
+	if err != nil || isKioskCaller(r) && room.KioskDisabled != 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Room을 찾을 수 없습니다.")
+		return
+	}
+	setETag(w, room.rowVersion())
+	if notModified(w, r, room.rowVersion()) {
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -179,9 +181,11 @@ func CreateRoom(w http.ResponseWriter, r *http.Request) {
 
 	var room Room
 	if err := json.NewDecoder(r.Body).Decode(&room); err != nil {
-		http.Error(w, "잘못된 요청 데이터", http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
+	// room은 항상 호출자의 company_code로 생성합니다.
+	room.CompanyCode = utils.CompanyCode(r.Context())
 
 	// 기본값 설정
 	if room.RoomWidth == 0 {
@@ -199,29 +203,17 @@ func CreateRoom(w http.ResponseWriter, r *http.Request) {
 	if room.TitleTextColor == "" {
 		room.TitleTextColor = "#FFFFFF"
 	}
+	// 기본값을 채운 뒤 색상 형식, 음수, 플래그, gender, 제목 길이를 검사합니다.
+	if errs := validateRoom(room); len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
 
-	query := `
-		INSERT INTO room_table 
-		(company_code, room_code, room_title, 
-		 title_background_color, title_text_color, room_background_color,
-		 room_top, room_left, room_width, room_height,
-		 gender, waiting, release, hide_title,
-		 transparent_background, hide_border, kiosk_disabled,
-		 power_control, breaker_number)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
-		        $12, $13, $14, $15, $16, $17, $18, $19)
-	`
 	// 시작 시간 로깅
 	startTime := time.Now()
 	log.Printf("Room 생성 요청 시작: %+v", room)
 
-	_, err := utils.DB.ExecContext(ctx, query,
-		room.CompanyCode, room.RoomCode, room.RoomTitle,
-		room.TitleBackgroundColor, room.TitleTextColor, room.RoomBackgroundColor,
-		room.RoomTop, room.RoomLeft, room.RoomWidth, room.RoomHeight,
-		room.Gender, room.Waiting, room.Release, room.HideTitle,
-		room.TransparentBackground, room.HideBorder, room.KioskDisabled,
-		room.PowerControl, room.BreakerNumber)
+	room, err := roomRepo.Create(ctx, room)
 
 	// 실행 시간 및 오류 로깅
 	duration := time.Since(startTime)
@@ -229,19 +221,24 @@ func CreateRoom(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Printf("DB 오류: %v", err)
-		if strings.Contains(err.Error(), "duplicate key") {
-			http.Error(w, "이미 존재하는 room code입니다", http.StatusBadRequest)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		writeRepoError(w, err, "이미 존재하는 room code입니다")
 		return
 	}
+	enqueueEvent("RoomCreated", map[string]interface{}{
+		"company_code": room.CompanyCode,
+		"room_code":    room.RoomCode,
+	})
 
+	setETag(w, room.rowVersion())
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(room)
 }
 
-// UpdateRoom: 제공된 JSON 데이터에 따라 전체 또는 일부 필드만 업데이트합니다.
+// UpdateRoom: room 전체를 요청 본문으로 교체합니다. (PUT)
+// 변경 가능한 필드는 모두 보내야 하며, 읽기 전용 필드(auto_increment, company_code, room_code, version)는
+// 생략하거나 현재 값과 같아야 합니다. 일부 필드만 바꾸려면 PATCH를 사용합니다.
+// 알 수 없는 필드, 읽기 전용 필드 변경, 타입이 맞지 않는 값은 422와 필드 목록으로 응답합니다.
+// If-Match 헤더를 보내면 현재 ETag가 목록에 있거나 "*"일 때만 처리하며, 아니면 412를 반환합니다.
 func UpdateRoom(w http.ResponseWriter, r *http.Request) {
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
@@ -250,116 +247,171 @@ func UpdateRoom(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	roomCode, err := strconv.Atoi(vars["room_code"])
 	if err != nil {
-		http.Error(w, "잘못된 room_code", http.StatusBadRequest)
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
 		return
 	}
+	companyCode := utils.CompanyCode(r.Context())
 
-	// 요청 본문을 map[string]interface{}로 디코딩하여, 제공된 필드만 업데이트합니다.
-	var updateData map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
-		http.Error(w, "잘못된 요청 데이터", http.StatusBadRequest)
+	// 다른 사용자의 변경을 덮어쓰지 않도록 If-Match 버전을 확인합니다.
+	match, ok := ifMatch(r)
+	if !ok {
+		writePreconditionFailed(w)
 		return
 	}
-	// JSON에 "room_code"가 있다면 URL과 일치하는지 확인 후 제거합니다.
-	if v, ok := updateData["room_code"]; ok {
-		switch v := v.(type) {
-		case float64:
-			if int(v) != roomCode {
-				http.Error(w, "URL과 body의 room_code가 다릅니다.", http.StatusBadRequest)
-				return
-			}
-		default:
-			http.Error(w, "잘못된 room_code 값", http.StatusBadRequest)
-			return
-		}
-		delete(updateData, "room_code")
-	}
-	if len(updateData) == 0 {
-		http.Error(w, "업데이트할 필드가 없습니다.", http.StatusBadRequest)
-		return
-	}
-
-	allowed := map[string]bool{
-		"company_code":           true,
-		"room_title":             true,
-		"room_background_color":  true,
-		"room_top":               true,
-		"room_left":              true,
-		"room_width":             true,
-		"room_height":            true,
-		"title_background_color": true,
-		"title_text_color":       true,
-		"gender":                 true,
-		"waiting":                true,
-		"release":                true,
-		"hide_title":             true,
-		"transparent_background": true,
-		"hide_border":            true,
-		"kiosk_disabled":         true,
-		"power_control":          true,
-		"breaker_number":         true,
-	}
-	updates := []string{}
-	args := []interface{}{}
-	idx := 1
-	for key, value := range updateData {
-		if !allowed[key] {
-			continue
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if body == nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "요청 본문은 JSON 객체여야 합니다")
+		return
+	}
+	current, ok := currentRoom(ctx, w, companyCode, roomCode, match)
+	if !ok {
+		return
+	}
+	currentDoc, err := toDocument(current)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	// 생략한 읽기 전용 필드는 현재 값을 유지합니다.
+	for key, value := range currentDoc {
+		if _, ok := body[key]; !ok && !roomUpdateColumns[key] {
+			body[key] = value
 		}
-		updates = append(updates, key+" = $"+strconv.Itoa(idx))
-		args = append(args, value)
-		idx++
 	}
-	if len(updates) == 0 {
-		http.Error(w, "유효한 업데이트 필드가 없습니다.", http.StatusBadRequest)
+	saveRoom(ctx, w, r, current, currentDoc, body)
+}
+
+// PatchRoom: room의 일부 필드를 JSON Merge Patch(application/merge-patch+json) 또는
+// JSON Patch(application/json-patch+json)로 변경합니다.
+// 알 수 없는 필드, 읽기 전용 필드 변경, 타입이 맞지 않는 값은 422와 필드 목록으로 응답합니다.
+// If-Match 헤더를 보내면 현재 ETag가 목록에 있거나 "*"일 때만 처리하며, 아니면 412를 반환합니다.
+func PatchRoom(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	roomCode, err := strconv.Atoi(vars["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	// 다른 사용자의 변경을 덮어쓰지 않도록 If-Match 버전을 확인합니다.
+	match, ok := ifMatch(r)
+	if !ok {
+		writePreconditionFailed(w)
+		return
+	}
+
+	current, ok := currentRoom(ctx, w, companyCode, roomCode, match)
+	if !ok {
+		return
+	}
+	currentDoc, err := toDocument(current)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	doc, err := applyPatchRequest(r, currentDoc)
+	if err != nil {
+		writePatchError(w, err)
 		return
 	}
+	saveRoom(ctx, w, r, current, currentDoc, doc)
+}
 
-	query := "UPDATE room_table SET " + strings.Join(updates, ", ") + " WHERE room_code = $" + strconv.Itoa(idx)
-	args = append(args, roomCode)
-	_, err = utils.DB.ExecContext(ctx, query, args...)
+// currentRoom은 수정할 room을 조회합니다.
+// 없으면 404, If-Match 버전과 다르면 412로 응답하고 false를 반환합니다.
+func currentRoom(ctx context.Context, w http.ResponseWriter, companyCode, roomCode int, match *etagCondition) (Room, bool) {
+	room, err := roomRepo.Get(ctx, companyCode, roomCode)
+	if errors.Is(err, ErrNotFound) {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Room을 찾을 수 없습니다.")
+		return Room{}, false
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeInternalError(w, err)
+		return Room{}, false
+	}
+	if !match.matches(room.rowVersion()) {
+		writePreconditionFailed(w)
+		return Room{}, false
+	}
+	return room, true
+}
+
+// saveRoom은 변경 후 문서 doc을 검사하고 현재 값과 달라진 필드만 저장합니다. (PUT/PATCH 공통)
+// 조회한 뒤 다른 요청이 먼저 변경했다면 덮어쓰지 않고 412로 응답합니다.
+func saveRoom(ctx context.Context, w http.ResponseWriter, r *http.Request, current Room, currentDoc, doc map[string]interface{}) {
+	if errs := validateDocument(doc, currentDoc, roomUpdateColumns); len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+	var updated Room
+	if err := fromDocument(doc, &updated); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
-	// 업데이트 후 비동기 작업 큐에 작업을 넣어 (예: room 업데이트 알림) 백그라운드 처리를 수행합니다.
-	job := utils.Job{
-		Name: "RoomUpdated",
-		Data: map[string]interface{}{
-			"room_code": roomCode,
-			"time":      time.Now(),
-		},
+	fields := changedFields(doc, currentDoc, roomUpdateColumns)
+	if len(fields) == 0 {
+		setETag(w, current.rowVersion())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(current)
+		return
+	}
+	// 값 검사는 바꾸는 필드만 대상으로 합니다. 기존 값 때문에 다른 필드 수정이 막히지 않도록 합니다.
+	if errs := validateRoom(updated).only(fields); len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
 	}
-	if utils.EnqueueJobHandler != nil {
-		utils.EnqueueJobHandler(job)
+	// room을 줄여 seat이 영역을 벗어나게 되는 경우 force=true일 때만 허용합니다.
+	if !forceLayout(r) && hasAllowedField(fields, roomSizeColumns) {
+		v, err := checkRoomBounds(ctx, updated)
+		if err != nil {
+			writeInternalError(w, err)
+			return
+		}
+		if !v.Empty() {
+			writeLayoutError(w, v)
+			return
+		}
 	}
 
-	// 업데이트된 room을 조회하여 반환합니다.
-	var room Room
-	err = utils.DB.QueryRowContext(ctx, `
-		SELECT auto_increment, company_code, room_code, room_title,
-		       title_background_color, title_text_color, room_background_color,
-		       room_top, room_left, room_width, room_height,
-		       gender, waiting, release, hide_title,
-		       transparent_background, hide_border, kiosk_disabled,
-		       power_control, breaker_number
-		FROM room_table WHERE room_code = $1`, roomCode).
-		Scan(&room.AutoIncrement, &room.CompanyCode, &room.RoomCode, &room.RoomTitle,
-			&room.TitleBackgroundColor, &room.TitleTextColor, &room.RoomBackgroundColor,
-			&room.RoomTop, &room.RoomLeft, &room.RoomWidth, &room.RoomHeight,
-			&room.Gender, &room.Waiting, &room.Release, &room.HideTitle,
-			&room.TransparentBackground, &room.HideBorder, &room.KioskDisabled,
-			&room.PowerControl, &room.BreakerNumber)
+	version := current.rowVersion()
+	room, err := roomRepo.Update(ctx, current.CompanyCode, current.RoomCode, fields, &version)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, ErrNotFound) {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Room을 찾을 수 없습니다.")
+		} else if errors.Is(err, ErrVersionMismatch) {
+			writePreconditionFailed(w)
+		} else {
+			writeRepoError(w, err, "이미 존재하는 room code입니다")
+		}
 		return
 	}
+
+	// 업데이트 후 비동기 작업 큐에 작업을 넣어 (예: room 업데이트 알림) 백그라운드 처리를 수행합니다.
+	enqueueEvent("RoomUpdated", map[string]interface{}{
+		"company_code": room.CompanyCode,
+		"room_code":    room.RoomCode,
+	})
+
+	setETag(w, room.rowVersion())
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(room)
 }
 
 // DeleteRoom: room을 삭제합니다.
+// 좌석이 남아 있으면 409를 반환하며, cascade=true 쿼리로 좌석까지 함께 삭제할 수 있습니다.
+// 함께 삭제할 좌석 중 사용 중이거나 예약/고정석 이용권이 있는 것이 있으면 409를 반환합니다.
+// If-Match 헤더를 보내면 현재 ETag가 목록에 있거나 "*"일 때만 처리하며, 아니면 412를 반환합니다.
 func DeleteRoom(w http.ResponseWriter, r *http.Request) {
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
@@ -368,13 +420,128 @@ func DeleteRoom(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	roomCode, err := strconv.Atoi(vars["room_code"])
 	if err != nil {
-		http.Error(w, "잘못된 room_code", http.StatusBadRequest)
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
 		return
 	}
-	_, err = utils.DB.ExecContext(ctx, "DELETE FROM room_table WHERE room_code = $1", roomCode)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	companyCode := utils.CompanyCode(r.Context())
+
+	// 다른 사용자가 변경한 행을 모르고 지우지 않도록 If-Match 버전을 확인합니다.
+	match, ok := ifMatch(r)
+	if !ok {
+		writePreconditionFailed(w)
 		return
 	}
+
+	// 조건에 맞는 현재 버전을 조회한 뒤, 삭제 시점에도 그 버전일 때만 삭제합니다.
+	var version *RowVersion
+	if match != nil {
+		current, ok := currentRoom(ctx, w, companyCode, roomCode, match)
+		if !ok {
+			return
+		}
+		v := current.rowVersion()
+		version = &v
+	}
+
+	// 좌석이 배정된 room은 기본적으로 삭제를 거부합니다.
+	// ?cascade=true인 경우 room에 속한 seat을 함께 삭제합니다.
+	cascade := r.URL.Query().Get("cascade") == "true"
+
+	seatCount, err := roomRepo.Delete(ctx, companyCode, roomCode, cascade, version)
+	switch {
+	case errors.Is(err, ErrVersionMismatch):
+		writePreconditionFailed(w)
+		return
+	case errors.Is(err, ErrRoomNotEmpty):
+		writeProblem(w, consts.ERR_ROOM_NOT_EMPTY, "좌석이 배정된 room은 삭제할 수 없습니다.")
+		return
+	case errors.Is(err, ErrSeatInUse):
+		writeProblem(w, consts.ERR_SEAT_IN_USE, err.Error())
+		return
+	case errors.Is(err, ErrNotFound):
+		writeProblem(w, consts.ERR_NOT_FOUND, "Room을 찾을 수 없습니다.")
+		return
+	case err != nil:
+		writeInternalError(w, err)
+		return
+	}
+	// cascade로 함께 삭제된 seat은 개별 SeatDeleted 대신 seat_count로 알립니다.
+	enqueueEvent("RoomDeleted", map[string]interface{}{
+		"company_code": companyCode,
+		"room_code":    roomCode,
+		"seat_count":   seatCount,
+	})
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// selectFields는 "X-Fields" 헤더 값 중 allowed에 있는 필드만 돌려줍니다.
+// 헤더가 없거나 허용된 필드가 하나도 없으면 allowed 전체를 돌려줍니다.
+func selectFields(header string, allowed []string) []string {
+	if header == "" {
+		return allowed
+	}
+	allowedSet := make(map[string]bool)
+	for _, f := range allowed {
+		allowedSet[f] = true
+	}
+	var fields []string
+	for _, f := range strings.Split(header, ",") {
+		f = strings.TrimSpace(f)
+		if allowedSet[f] {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return allowed
+	}
+	return fields
+}
+
+// projectFields는 items(구조체 슬라이스)의 각 항목을 JSON 필드 중 fields만 남긴 map으로 변환합니다.
+func projectFields(items interface{}, fields []string) ([]map[string]interface{}, error) {
+	body, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, err
+	}
+	result := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		rowMap := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			rowMap[f] = row[f]
+		}
+		result = append(result, rowMap)
+	}
+	return result, nil
+}
+
+// hasAllowedField는 updateData에 변경 가능한 컬럼이 하나라도 있는지 확인합니다.
+func hasAllowedField(updateData map[string]interface{}, allowed map[string]bool) bool {
+	for key := range updateData {
+		if allowed[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// writeRepoError는 저장소 오류를 problem 문서로 변환합니다.
+// 중복 코드는 400, 제약 위반은 422, 그 외는 원인을 로그에만 남기고 500입니다.
+func writeRepoError(w http.ResponseWriter, err error, duplicateMessage string) {
+	switch {
+	case errors.Is(err, ErrDuplicate):
+		writeProblem(w, consts.ERR_DUPLICATE_CODE, duplicateMessage)
+	case errors.Is(err, ErrRoomNotFound):
+		writeProblem(w, consts.ERR_ROOM_NOT_FOUND, "Room을 찾을 수 없습니다.")
+	case errors.Is(err, ErrSeatInUse):
+		writeProblem(w, consts.ERR_SEAT_IN_USE, err.Error())
+	case errors.Is(err, ErrInvalidValue):
+		log.Printf("필드 값 제약 위반: %v", err)
+		writeProblem(w, consts.ERR_INVALID_FIELDS, "허용되지 않는 필드 값이 있습니다")
+	default:
+		writeInternalError(w, err)
+	}
+}
@@ -0,0 +1,236 @@
+package tables
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// testCompanyCode는 테스트 요청의 호출자 company_code입니다.
+const testCompanyCode = 7
+
+// newTestRouter는 메모리 저장소를 사용하는 room/seat 라우터를 만듭니다.
+func newTestRouter(t *testing.T) (*mux.Router, *MemoryStore) {
+	t.Helper()
+	store := NewMemoryStore()
+	SetRoomRepository(store.Rooms())
+	SetSeatRepository(store.Seats())
+
+	r := mux.NewRouter()
+	RegisterRoomRoutes(r)
+	RegisterSeatRoutes(r)
+	RegisterRoomSeatRoutes(r)
+	return r, store
+}
+
+// testRequest는 테스트 요청 하나입니다. Role이 비어 있으면 점주, CompanyCode가 0이면 testCompanyCode입니다.
+type testRequest struct {
+	Method      string
+	Target      string
+	Body        string
+	Role        string
+	CompanyCode int
+	Header      map[string]string
+}
+
+// serve는 TenantMiddleware가 인증한 것처럼 company_code와 역할을 컨텍스트에 넣어 요청을 처리합니다.
+func serve(h http.Handler, tr testRequest) *httptest.ResponseRecorder {
+	if tr.Role == "" {
+		tr.Role = consts.ROLE_OWNER
+	}
+	if tr.CompanyCode == 0 {
+		tr.CompanyCode = testCompanyCode
+	}
+	req := httptest.NewRequest(tr.Method, tr.Target, strings.NewReader(tr.Body))
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range tr.Header {
+		req.Header.Set(key, value)
+	}
+	ctx := utils.WithCompanyCode(req.Context(), tr.CompanyCode)
+	ctx = utils.WithClaims(ctx, utils.Claims{Role: tr.Role, CompanyCode: tr.CompanyCode, TokenType: consts.TOKEN_TYPE_ACCESS})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req.WithContext(ctx))
+	return rec
+}
+
+// mustServe는 요청을 처리하고 상태 코드가 want가 아니면 테스트를 중단합니다.
+func mustServe(t *testing.T, h http.Handler, tr testRequest, want int) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := serve(h, tr)
+	if rec.Code != want {
+		t.Fatalf("%s %s: status = %d, want %d, body = %s", tr.Method, tr.Target, rec.Code, want, rec.Body.String())
+	}
+	return rec
+}
+
+// problemCode는 problem 문서 응답의 오류 코드입니다.
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p utils.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("problem 문서가 아닙니다: %s", rec.Body.String())
+	}
+	return p.Code
+}
+
+func TestRoomCRUD(t *testing.T) {
+	r, _ := newTestRouter(t)
+
+	rec := mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1, "room_title": "A"}`}, http.StatusCreated)
+	var room Room
+	if err := json.Unmarshal(rec.Body.Bytes(), &room); err != nil {
+		t.Fatal(err)
+	}
+	if room.CompanyCode != testCompanyCode || room.RoomWidth != 100 || room.RoomBackgroundColor != "#FFFFFF" {
+		t.Fatalf("기본값이 채워지지 않았습니다: %+v", room)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag != room.rowVersion().ETag() {
+		t.Fatalf("ETag = %s, want %s", etag, room.rowVersion().ETag())
+	}
+
+	rec = mustServe(t, r, testRequest{Method: "GET", Target: "/rooms"}, http.StatusOK)
+	var rooms []map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &rooms)
+	if len(rooms) != 1 || rooms[0]["room_title"] != "A" {
+		t.Fatalf("GET /rooms = %s", rec.Body.String())
+	}
+
+	mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/1", Header: map[string]string{"If-None-Match": etag}}, http.StatusNotModified)
+
+	// 현재 ETag로 수정하면 버전이 올라가고, 이전 ETag로는 수정할 수 없습니다.
+	rec = mustServe(t, r, testRequest{
+		Method: "PATCH", Target: "/rooms/1", Body: `{"room_title": "B"}`,
+		Header: map[string]string{"Content-Type": "application/merge-patch+json", "If-Match": etag},
+	}, http.StatusOK)
+	if rec.Header().Get("ETag") == etag {
+		t.Fatal("수정 후 ETag가 바뀌지 않았습니다")
+	}
+	rec = mustServe(t, r, testRequest{
+		Method: "PUT", Target: "/rooms/1", Body: `{"room_title": "C"}`,
+		Header: map[string]string{"If-Match": etag},
+	}, http.StatusPreconditionFailed)
+	if code := problemCode(t, rec); code != consts.ERR_PRECONDITION_FAILED {
+		t.Fatalf("code = %s", code)
+	}
+
+	rec = mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/1"}, http.StatusOK)
+	json.Unmarshal(rec.Body.Bytes(), &room)
+	if room.RoomTitle != "B" {
+		t.Fatalf("room_title = %s, want B", room.RoomTitle)
+	}
+
+	mustServe(t, r, testRequest{Method: "DELETE", Target: "/rooms/1"}, http.StatusNoContent)
+	mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/1"}, http.StatusNotFound)
+}
+
+func TestCreateRoomErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"잘못된 JSON", `{"room_code":`, http.StatusBadRequest, consts.ERR_INVALID_BODY},
+		{"타입 불일치", `{"room_code": "x"}`, http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS},
+		{"잘못된 색상", `{"room_code": 2, "room_background_color": "red"}`, http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS},
+		{"음수 크기", `{"room_code": 2, "room_width": -1}`, http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS},
+		{"중복 room_code", `{"room_code": 1}`, http.StatusBadRequest, consts.ERR_DUPLICATE_CODE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestRouter(t)
+			mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1}`}, http.StatusCreated)
+
+			rec := mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: tt.body}, tt.wantStatus)
+			if code := problemCode(t, rec); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRoomRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"회원 조회", consts.ROLE_MEMBER, "GET", "/rooms/1", "", http.StatusOK},
+		{"회원 생성 거부", consts.ROLE_MEMBER, "POST", "/rooms", `{"room_code": 2}`, http.StatusForbidden},
+		{"직원 생성", consts.ROLE_STAFF, "POST", "/rooms", `{"room_code": 2}`, http.StatusCreated},
+		{"직원 삭제 거부", consts.ROLE_STAFF, "DELETE", "/rooms/1", "", http.StatusForbidden},
+		{"점주 삭제", consts.ROLE_OWNER, "DELETE", "/rooms/1", "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestRouter(t)
+			mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1}`}, http.StatusCreated)
+			mustServe(t, r, testRequest{Method: tt.method, Target: tt.target, Body: tt.body, Role: tt.role}, tt.wantStatus)
+		})
+	}
+}
+
+func TestRoomTenantIsolation(t *testing.T) {
+	r, _ := newTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1}`}, http.StatusCreated)
+
+	other := testCompanyCode + 1
+	mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/1", CompanyCode: other}, http.StatusNotFound)
+	mustServe(t, r, testRequest{Method: "DELETE", Target: "/rooms/1", CompanyCode: other}, http.StatusNotFound)
+	// 다른 회사는 같은 room_code를 따로 사용할 수 있습니다.
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1}`, CompanyCode: other}, http.StatusCreated)
+}
+
+func TestDeleteRoomWithSeats(t *testing.T) {
+	r, store := newTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1}`}, http.StatusCreated)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 1}`}, http.StatusCreated)
+
+	rec := mustServe(t, r, testRequest{Method: "DELETE", Target: "/rooms/1"}, http.StatusConflict)
+	if code := problemCode(t, rec); code != consts.ERR_ROOM_NOT_EMPTY {
+		t.Fatalf("code = %s, want %s", code, consts.ERR_ROOM_NOT_EMPTY)
+	}
+
+	// 사용 중인 seat이 있으면 cascade여도 삭제하지 않습니다.
+	store.SetSeatInUse(testCompanyCode, 1, true)
+	rec = mustServe(t, r, testRequest{Method: "DELETE", Target: "/rooms/1?cascade=true"}, http.StatusConflict)
+	if code := problemCode(t, rec); code != consts.ERR_SEAT_IN_USE {
+		t.Fatalf("code = %s, want %s", code, consts.ERR_SEAT_IN_USE)
+	}
+	if len(store.seats) != 1 {
+		t.Fatalf("거부된 cascade 삭제 후 seat %d개가 남았습니다", len(store.seats))
+	}
+
+	store.SetSeatInUse(testCompanyCode, 1, false)
+	mustServe(t, r, testRequest{Method: "DELETE", Target: "/rooms/1?cascade=true"}, http.StatusNoContent)
+	if len(store.seats) != 0 {
+		t.Fatalf("cascade 삭제 후 seat %d개가 남았습니다", len(store.seats))
+	}
+}
+
+func TestKioskHidesDisabledRooms(t *testing.T) {
+	r, _ := newTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1}`}, http.StatusCreated)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 2, "kiosk_disabled": 1}`}, http.StatusCreated)
+
+	rec := mustServe(t, r, testRequest{Method: "GET", Target: "/rooms", Role: consts.ROLE_KIOSK}, http.StatusOK)
+	var rooms []Room
+	json.Unmarshal(rec.Body.Bytes(), &rooms)
+	if len(rooms) != 1 || rooms[0].RoomCode != 1 {
+		t.Fatalf("키오스크 GET /rooms = %s", rec.Body.String())
+	}
+	mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/2", Role: consts.ROLE_KIOSK}, http.StatusNotFound)
+	mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/2", Role: consts.ROLE_STAFF}, http.StatusOK)
+}
@@ -3,9 +3,8 @@ package tables
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -24,6 +23,7 @@ type Seat struct {
 	AutoIncrement         int    `json:"auto_increment" db:"auto_increment"`
 	CompanyCode           int    `json:"company_code"`
 	SeatCode              int    `json:"seat_code"`
+	RoomCode              int    `json:"room_code"`
 	SeatTitle             string `json:"seat_title"`
 	TitleBackgroundColor  string `json:"title_background_color"`
 	TitleTextColor        string `json:"title_text_color"`
@@ -41,20 +41,38 @@ type Seat struct {
 	KioskDisabled         int    `json:"kiosk_disabled"`
 	PowerControl          int    `json:"power_control"`
 	BreakerNumber         int    `json:"breaker_number"`
+	Version               int    `json:"version"`
+}
+
+// rowVersion은 ETag로 사용할 seat의 버전입니다.
+func (seat Seat) rowVersion() RowVersion {
+	return RowVersion{AutoIncrement: seat.AutoIncrement, Version: seat.Version}
 }
 
 // RegisterSeatRoutes는 seat_table 관련 엔드포인트를 등록합니다.
+// 조회는 모든 역할, 생성/수정은 점주/직원, 삭제는 점주만 할 수 있습니다.
+// 키오스크는 kiosk_disabled가 설정된 seat과 그런 room 안의 seat을 조회할 수 없습니다.
 func RegisterSeatRoutes(r *mux.Router) {
-	r.HandleFunc("/seats", GetSeats).Methods("GET")
-	r.HandleFunc("/seats/{seat_code}", GetSeat).Methods("GET")
-	r.HandleFunc("/seats", CreateSeat).Methods("POST")
-	// UpdateSeat은 전체/부분 업데이트를 모두 지원합니다.
-	r.HandleFunc("/seats/{seat_code}", UpdateSeat).Methods("PUT")
-	r.HandleFunc("/seats/{seat_code}", DeleteSeat).Methods("DELETE")
+	r.Handle("/seats", allow(GetSeats, anyRole...)).Methods("GET")
+	r.Handle("/seats/{seat_code}", allow(GetSeat, anyRole...)).Methods("GET")
+	r.Handle("/seats", allow(CreateSeat, staffRoles...)).Methods("POST")
+	// PUT은 전체 교체, PATCH는 merge patch/JSON Patch로 일부 필드만 변경합니다.
+	r.Handle("/seats/{seat_code}", allow(UpdateSeat, staffRoles...)).Methods("PUT")
+	r.Handle("/seats/{seat_code}", allow(PatchSeat, staffRoles...)).Methods("PATCH")
+	r.Handle("/seats/{seat_code}", allow(DeleteSeat, ownerOnly...)).Methods("DELETE")
+}
+
+// RegisterRoomSeatRoutes는 room에 속한 seat 관련 중첩 엔드포인트를 등록합니다.
+func RegisterRoomSeatRoutes(r *mux.Router) {
+	r.Handle("/rooms/{room_code}/seats", allow(GetRoomSeats, anyRole...)).Methods("GET")
+	r.Handle("/rooms/{room_code}/seats", allow(CreateRoomSeat, staffRoles...)).Methods("POST")
+	r.Handle("/rooms/{room_code}/seats:bulk", allow(CreateRoomSeatsBulk, staffRoles...)).Methods("POST")
 }
 
 // GetSeats: "X-Fields" 헤더에 지정된 필드만 조회하거나 전체 필드를 조회합니다.
-// URL 쿼리 파라미터를 통해 필터링 기능도 지원합니다.
+// URL 쿼리 파라미터(컬럼명=값, filter 조건식, search)를 통해 필터링 기능도 지원합니다.
+// limit/cursor 쿼리를 보내면 {"items", "next_cursor"} 형식으로 페이지를 나누어 응답하며,
+// 보내지 않으면 기존처럼 전체 목록을 배열로 응답합니다. include_total=true이면 X-Total-Count 헤더를 추가합니다.
 func GetSeats(w http.ResponseWriter, r *http.Request) {
 	// 요청 컨텍스트에 10초 타임아웃 설정
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
@@ -62,162 +80,140 @@ func GetSeats(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	allowedFields := []string{
-		"auto_increment", "company_code", "seat_code", "seat_title",
+		"auto_increment", "company_code", "seat_code", "room_code", "seat_title",
 		"title_background_color", "title_text_color", "seat_background_color",
 		"seat_top", "seat_left", "seat_width", "seat_height",
 		"gender", "waiting", "release", "hide_title",
 		"transparent_background", "hide_border", "kiosk_disabled",
-		"power_control", "breaker_number",
+		"power_control", "breaker_number", "version",
 	}
 
 	// 필드 선택 처리
-	fieldsHeader := r.Header.Get("X-Fields")
-	var fields []string
-	if fieldsHeader != "" {
-		requested := strings.Split(fieldsHeader, ",")
-		allowedSet := make(map[string]bool)
-		for _, f := range allowedFields {
-			allowedSet[f] = true
-		}
-		for _, f := range requested {
-			f = strings.TrimSpace(f)
-			if allowedSet[f] {
-				fields = append(fields, f)
-			}
-		}
-		if len(fields) == 0 {
-			fields = allowedFields
-		}
-	} else {
-		fields = allowedFields
-	}
-
-	// 필터링 조건 처리
-	filters := []string{}
-	args := []interface{}{}
-	paramIdx := 1
-
-	// 지원하는 필터 파라미터 목록 (chain_code 제거됨)
-	filterParams := map[string]string{
-		"company_code":   "company_code",
-		"seat_code":      "seat_code",
-		"gender":         "gender",
-		"waiting":        "waiting",
-		"release":        "release",
-		"kiosk_disabled": "kiosk_disabled",
-		"power_control":  "power_control",
-	}
-
-	// URL 쿼리 파라미터에서 필터 조건 추출
-	for param, dbField := range filterParams {
-		if value := r.URL.Query().Get(param); value != "" {
-			filters = append(filters, fmt.Sprintf("%s = $%d", dbField, paramIdx))
-			args = append(args, value)
-			paramIdx++
-		}
-	}
+	fields := selectFields(r.Header.Get("X-Fields"), allowedFields)
 
-	// 검색 기능 추가 (seat_title에 대한 부분 검색)
+	// 필터링 조건 처리 (호출자의 company_code에 속한 seat만 조회합니다)
+	// 컬럼명=값 일치 조건과 filter 조건식을 사용할 수 있습니다. (parseFilter 참고)
+	companyCode := utils.CompanyCode(r.Context())
+	where, err := parseFilter(r, seatFilterColumns)
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, err.Error())
+		return
+	}
 	if search := r.URL.Query().Get("search"); search != "" {
-		filters = append(filters, fmt.Sprintf("seat_title LIKE $%d", paramIdx))
-		args = append(args, "%"+search+"%")
-		paramIdx++
+		where = filterAnd(where, filterCondition{Column: "seat_title", Op: "like", Values: []interface{}{"%" + search + "%"}})
 	}
 
-	// 쿼리 구성
-	query := "SELECT " + strings.Join(fields, ", ") + " FROM seat_table"
-	if len(filters) > 0 {
-		query += " WHERE " + strings.Join(filters, " AND ")
+	// /rooms/{room_code}/seats 경로로 호출된 경우 URL의 room_code 조건을 함께 적용합니다.
+	if v, ok := mux.Vars(r)["room_code"]; ok {
+		roomCode, err := strconv.Atoi(v)
+		if err != nil {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+			return
+		}
+		where = filterAnd(where, filterEq("room_code", roomCode))
+	}
+	where, err = kioskSeatFilter(ctx, r, companyCode, where)
+	if err != nil {
+		writeInternalError(w, err)
+		return
 	}
+	filter := SeatFilter{Where: where}
 
-	// 정렬 옵션 처리
+	// 정렬 옵션 처리 (기본 정렬은 seat_code 기준)
 	if sort := r.URL.Query().Get("sort"); sort != "" {
-		direction := "ASC"
-		if strings.HasPrefix(sort, "-") {
-			sort = sort[1:]
-			direction = "DESC"
-		}
-
-		// 허용된 정렬 필드인지 확인
-		allowedSortFields := map[string]bool{
-			"seat_code":      true,
-			"seat_title":     true,
-			"auto_increment": true,
-		}
-
-		if allowedSortFields[sort] {
-			query += fmt.Sprintf(" ORDER BY %s %s", sort, direction)
-		}
-	} else {
-		// 기본 정렬은 seat_code 기준
-		query += " ORDER BY seat_code ASC"
+		filter.Desc = strings.HasPrefix(sort, "-")
+		filter.Sort = strings.TrimPrefix(sort, "-")
+	}
+	sortSpec := filter.sortColumn()
+	if filter.Desc {
+		sortSpec = "-" + sortSpec
 	}
 
-	// 로깅 추가
-	log.Printf("실행 쿼리: %s, 인자: %v", query, args)
-
-	// 쿼리 실행
-	var rows *sql.Rows
-	var err error
-	if len(args) > 0 {
-		rows, err = utils.DB.QueryContext(ctx, query, args...)
-	} else {
-		rows, err = utils.DB.QueryContext(ctx, query)
+	// limit/cursor를 보내면 정렬 순서대로 페이지를 나누어 응답합니다.
+	page, paged, err := parsePage(r, sortSpec)
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, err.Error())
+		return
+	}
+	// 다음 페이지가 있는지 알기 위해 하나 더 조회합니다.
+	filter.Page = page
+	if paged {
+		filter.Limit++
 	}
 
+	seats, err := seatRepo.List(ctx, companyCode, filter)
+	if errors.Is(err, errInvalidCursor) {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, errInvalidCursor.Error())
+		return
+	}
 	if err != nil {
 		log.Printf("데이터베이스 쿼리 오류: %v", err)
-		http.Error(w, "데이터 조회 중 오류가 발생했습니다", http.StatusInternalServerError)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
 		return
 	}
-	defer rows.Close()
+	var nextCursor *string
+	if paged && len(seats) > page.Limit {
+		seats = seats[:page.Limit]
+		last := seats[page.Limit-1]
+		next := encodeCursor(Cursor{Sort: sortSpec, Key: seatSortKey(last, filter.sortColumn()), ID: last.AutoIncrement})
+		nextCursor = &next
+	}
 
-	// 결과 처리
-	columns, err := rows.Columns()
+	result, err := projectFields(seats, fields)
 	if err != nil {
-		log.Printf("컬럼 정보 조회 오류: %v", err)
-		http.Error(w, "데이터 처리 중 오류가 발생했습니다", http.StatusInternalServerError)
+		log.Printf("필드 선택 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
 		return
 	}
 
-	result := []map[string]interface{}{}
-	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
+	// include_occupancy=true이면 각 seat에 현재 사용 세션을 함께 반환합니다.
+	if r.URL.Query().Get("include_occupancy") == "true" {
+		seatCodes := make([]int64, 0, len(seats))
+		for _, seat := range seats {
+			seatCodes = append(seatCodes, int64(seat.SeatCode))
 		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
-			log.Printf("행 스캔 오류: %v", err)
-			http.Error(w, "데이터 처리 중 오류가 발생했습니다", http.StatusInternalServerError)
+		sessions, err := openSessionsBySeat(ctx, companyCode, seatCodes)
+		if err != nil {
+			log.Printf("세션 조회 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
 			return
 		}
-
-		rowMap := make(map[string]interface{})
-		for i, col := range columns {
-			var v interface{}
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				v = string(b)
+		for i, seat := range seats {
+			if session, ok := sessions[int64(seat.SeatCode)]; ok {
+				result[i]["occupied"] = true
+				result[i]["session"] = session
 			} else {
-				v = val
+				result[i]["occupied"] = false
+				result[i]["session"] = nil
 			}
-			rowMap[col] = v
 		}
-		result = append(result, rowMap)
 	}
 
-	// 결과 반환
+	if includeTotal(r) {
+		total, err := seatRepo.Count(ctx, companyCode, filter)
+		if err != nil {
+			log.Printf("데이터베이스 쿼리 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+			return
+		}
+		setTotalCount(w, total)
+	}
+
+	// 결과 반환 (페이지 요청이면 next_cursor를 함께 반환합니다)
+	var body interface{} = result
+	if paged {
+		body = pageResponse{Items: result, NextCursor: nextCursor}
+	}
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(result); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		log.Printf("JSON 인코딩 오류: %v", err)
-		http.Error(w, "응답 생성 중 오류가 발생했습니다", http.StatusInternalServerError)
+		writeProblem(w, consts.ERR_INTERNAL, "응답 생성 중 오류가 발생했습니다")
 		return
 	}
 }
 
 // GetSeat: 단일 seat를 전체 필드로 조회합니다.
+// 응답에 ETag를 넣으며, If-None-Match가 현재 ETag와 같으면 본문 없이 304를 반환합니다.
 func GetSeat(w http.ResponseWriter, r *http.Request) {
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
@@ -226,88 +222,132 @@ func GetSeat(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	seatCode, err := strconv.Atoi(vars["seat_code"])
 	if err != nil {
-		http.Error(w, "잘못된 seat_code", http.StatusBadRequest)
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
 		return
 	}
 
-	var seat Seat
-	err = utils.DB.QueryRowContext(ctx, `
-        SELECT auto_increment, company_code, seat_code, seat_title,
-               title_background_color, title_text_color, seat_background_color,
-               seat_top, seat_left, seat_width, seat_height,
-               gender, waiting, release, hide_title,
-               transparent_background, hide_border, kiosk_disabled,
-               power_control, breaker_number
-        FROM seat_table WHERE seat_code = $1`, seatCode).
-		Scan(&seat.AutoIncrement, &seat.CompanyCode, &seat.SeatCode, &seat.SeatTitle,
-			&seat.TitleBackgroundColor, &seat.TitleTextColor, &seat.SeatBackgroundColor,
-			&seat.SeatTop, &seat.SeatLeft, &seat.SeatWidth, &seat.SeatHeight,
-			&seat.Gender, &seat.Waiting, &seat.Release, &seat.HideTitle,
-			&seat.TransparentBackground, &seat.HideBorder, &seat.KioskDisabled,
-			&seat.PowerControl, &seat.BreakerNumber)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			http.Error(w, "Seat를 찾을 수 없습니다.", http.StatusNotFound)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+	seat, err := seatRepo.Get(ctx, utils.CompanyCode(r.Context()), seatCode)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		writeInternalError(w, err)
+		return
+	}
+	// 키오스크에서 숨긴 seat(및 숨긴 room 안의 seat)은 없는 seat처럼 응답합니다.
+	hidden := err != nil
+	if !hidden && isKioskCaller(r) {
+		hidden = seat.KioskDisabled != 0
+		if !hidden {
+			room, err := roomRepo.Get(ctx, seat.CompanyCode, seat.RoomCode)
+			if err != nil {
+				writeInternalError(w, err)
+				return
+			}
+			hidden = room.KioskDisabled != 0
 		}
+	}
+	if hidden {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+		return
+	}
+	setETag(w, seat.rowVersion())
+	if notModified(w, r, seat.rowVersion()) {
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(seat)
 }
 
-// CreateSeat: 새로운 seat을 생성합니다.
-func CreateSeat(w http.ResponseWriter, r *http.Request) {
-	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+// GetRoomSeats: 지정한 room에 속한 seat 목록을 조회합니다.
+// 필드 선택, 필터링, 정렬은 GetSeats와 동일하게 동작합니다.
+func GetRoomSeats(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
 	defer cancel()
 
+	roomCode, err := strconv.Atoi(mux.Vars(r)["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
+	}
+	room, err := roomRepo.Get(ctx, utils.CompanyCode(r.Context()), roomCode)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		writeInternalError(w, err)
+		return
+	}
+	if err != nil || isKioskCaller(r) && room.KioskDisabled != 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Room을 찾을 수 없습니다.")
+		return
+	}
+
+	GetSeats(w, r)
+}
+
+// CreateSeat: 새로운 seat을 생성합니다.
+func CreateSeat(w http.ResponseWriter, r *http.Request) {
 	var seat Seat
 	if err := json.NewDecoder(r.Body).Decode(&seat); err != nil {
-		http.Error(w, "잘못된 요청 데이터", http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
+	createSeat(w, r, seat)
+}
 
-	// 기본값 설정
-	if seat.SeatWidth == 0 {
-		seat.SeatWidth = 100
+// CreateRoomSeat: URL의 room에 속하는 새로운 seat을 생성합니다.
+func CreateRoomSeat(w http.ResponseWriter, r *http.Request) {
+	roomCode, err := strconv.Atoi(mux.Vars(r)["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
 	}
-	if seat.SeatHeight == 0 {
-		seat.SeatHeight = 100
+
+	var seat Seat
+	if err := json.NewDecoder(r.Body).Decode(&seat); err != nil {
+		writeDecodeError(w, err)
+		return
 	}
-	if seat.SeatBackgroundColor == "" {
-		seat.SeatBackgroundColor = "#FFFFFF"
+	if seat.RoomCode != 0 && seat.RoomCode != roomCode {
+		writeProblem(w, consts.ERR_INVALID_BODY, "URL과 body의 room_code가 다릅니다.")
+		return
 	}
-	if seat.TitleBackgroundColor == "" {
-		seat.TitleBackgroundColor = "#000000"
+	seat.RoomCode = roomCode
+	createSeat(w, r, seat)
+}
+
+// createSeat은 기본값을 채우고 room_code를 검증한 뒤 seat을 저장합니다.
+func createSeat(w http.ResponseWriter, r *http.Request, seat Seat) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	// seat은 항상 호출자의 company_code로 생성하며, 같은 회사의 room에 속해야 합니다.
+	// room이 없으면 seat_room_fk 외래 키 위반(ErrRoomNotFound)으로 저장이 거부됩니다.
+	seat.CompanyCode = utils.CompanyCode(r.Context())
+
+	// 기본값 설정
+	applySeatDefaults(&seat)
+	// 기본값을 채운 뒤 색상 형식, 음수, 플래그, gender, 제목 길이를 검사합니다.
+	if errs := validateSeat(seat); len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
 	}
-	if seat.TitleTextColor == "" {
-		seat.TitleTextColor = "#FFFFFF"
+
+	// 다른 seat과 겹치거나 room 영역을 벗어나는 배치는 force=true일 때만 허용합니다.
+	if !forceLayout(r) {
+		v, err := checkSeatLayout(ctx, seat.CompanyCode, seat.RoomCode, []Seat{seat})
+		if err != nil {
+			writeRepoError(w, err, "")
+			return
+		}
+		if !v.Empty() {
+			writeLayoutError(w, v)
+			return
+		}
 	}
 
-	query := `
-        INSERT INTO seat_table 
-        (company_code, seat_code, seat_title, 
-         title_background_color, title_text_color, seat_background_color,
-         seat_top, seat_left, seat_width, seat_height,
-         gender, waiting, release, hide_title,
-         transparent_background, hide_border, kiosk_disabled,
-         power_control, breaker_number)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
-                $12, $13, $14, $15, $16, $17, $18, $19)
-    `
 	// 시작 시간 로깅
 	startTime := time.Now()
 	log.Printf("Seat 생성 요청 시작: %+v", seat)
 
-	_, err := utils.DB.ExecContext(ctx, query,
-		seat.CompanyCode, seat.SeatCode, seat.SeatTitle,
-		seat.TitleBackgroundColor, seat.TitleTextColor, seat.SeatBackgroundColor,
-		seat.SeatTop, seat.SeatLeft, seat.SeatWidth, seat.SeatHeight,
-		seat.Gender, seat.Waiting, seat.Release, seat.HideTitle,
-		seat.TransparentBackground, seat.HideBorder, seat.KioskDisabled,
-		seat.PowerControl, seat.BreakerNumber)
+	seat, err := seatRepo.Create(ctx, seat)
 
 	// 실행 시간 및 오류 로깅
 	duration := time.Since(startTime)
@@ -315,19 +355,44 @@ func CreateSeat(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Printf("DB 오류: %v", err)
-		if strings.Contains(err.Error(), "duplicate key") {
-			http.Error(w, "이미 존재하는 seat code입니다", http.StatusBadRequest)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		writeRepoError(w, err, "이미 존재하는 seat code입니다")
 		return
 	}
+	enqueueEvent("SeatCreated", map[string]interface{}{
+		"company_code": seat.CompanyCode,
+		"room_code":    seat.RoomCode,
+		"seat_code":    seat.SeatCode,
+	})
 
+	setETag(w, seat.rowVersion())
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(seat)
 }
 
-// UpdateSeat: 제공된 JSON 데이터에 따라 전체 또는 일부 필드만 업데이트합니다.
+// applySeatDefaults는 비어 있는 크기와 색상에 기본값을 채웁니다.
+func applySeatDefaults(seat *Seat) {
+	if seat.SeatWidth == 0 {
+		seat.SeatWidth = 100
+	}
+	if seat.SeatHeight == 0 {
+		seat.SeatHeight = 100
+	}
+	if seat.SeatBackgroundColor == "" {
+		seat.SeatBackgroundColor = "#FFFFFF"
+	}
+	if seat.TitleBackgroundColor == "" {
+		seat.TitleBackgroundColor = "#000000"
+	}
+	if seat.TitleTextColor == "" {
+		seat.TitleTextColor = "#FFFFFF"
+	}
+}
+
+// UpdateSeat: seat 전체를 요청 본문으로 교체합니다. (PUT)
+// 변경 가능한 필드는 모두 보내야 하며, 읽기 전용 필드(auto_increment, company_code, seat_code, version)는
+// 생략하거나 현재 값과 같아야 합니다. 일부 필드만 바꾸려면 PATCH를 사용합니다.
+// 알 수 없는 필드, 읽기 전용 필드 변경, 타입이 맞지 않는 값은 422와 필드 목록으로 응답합니다.
+// If-Match 헤더를 보내면 현재 ETag가 목록에 있거나 "*"일 때만 처리하며, 아니면 412를 반환합니다.
 func UpdateSeat(w http.ResponseWriter, r *http.Request) {
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
@@ -336,116 +401,174 @@ func UpdateSeat(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	seatCode, err := strconv.Atoi(vars["seat_code"])
 	if err != nil {
-		http.Error(w, "잘못된 seat_code", http.StatusBadRequest)
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
 		return
 	}
+	companyCode := utils.CompanyCode(r.Context())
 
-	// 요청 본문을 map[string]interface{}로 디코딩하여, 제공된 필드만 업데이트합니다.
-	var updateData map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
-		http.Error(w, "잘못된 요청 데이터", http.StatusBadRequest)
+	// 다른 사용자의 변경을 덮어쓰지 않도록 If-Match 버전을 확인합니다.
+	match, ok := ifMatch(r)
+	if !ok {
+		writePreconditionFailed(w)
 		return
 	}
-	// JSON에 "seat_code"가 있다면 URL과 일치하는지 확인 후 제거합니다.
-	if v, ok := updateData["seat_code"]; ok {
-		switch v := v.(type) {
-		case float64:
-			if int(v) != seatCode {
-				http.Error(w, "URL과 body의 seat_code가 다릅니다.", http.StatusBadRequest)
-				return
-			}
-		default:
-			http.Error(w, "잘못된 seat_code 값", http.StatusBadRequest)
-			return
-		}
-		delete(updateData, "seat_code")
-	}
-	if len(updateData) == 0 {
-		http.Error(w, "업데이트할 필드가 없습니다.", http.StatusBadRequest)
-		return
-	}
-
-	allowed := map[string]bool{
-		"company_code":           true,
-		"seat_title":             true,
-		"seat_background_color":  true,
-		"seat_top":               true,
-		"seat_left":              true,
-		"seat_width":             true,
-		"seat_height":            true,
-		"title_background_color": true,
-		"title_text_color":       true,
-		"gender":                 true,
-		"waiting":                true,
-		"release":                true,
-		"hide_title":             true,
-		"transparent_background": true,
-		"hide_border":            true,
-		"kiosk_disabled":         true,
-		"power_control":          true,
-		"breaker_number":         true,
-	}
-	updates := []string{}
-	args := []interface{}{} // 올바른 방식으로 빈 인터페이스 슬라이스 초기화
-	idx := 1
-	for key, value := range updateData {
-		if !allowed[key] {
-			continue
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if body == nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "요청 본문은 JSON 객체여야 합니다")
+		return
+	}
+	current, ok := currentSeat(ctx, w, companyCode, seatCode, match)
+	if !ok {
+		return
+	}
+	currentDoc, err := toDocument(current)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	// 생략한 읽기 전용 필드는 현재 값을 유지합니다.
+	for key, value := range currentDoc {
+		if _, ok := body[key]; !ok && !seatUpdateColumns[key] {
+			body[key] = value
 		}
-		updates = append(updates, key+" = $"+strconv.Itoa(idx))
-		args = append(args, value)
-		idx++
 	}
-	if len(updates) == 0 {
-		http.Error(w, "유효한 업데이트 필드가 없습니다.", http.StatusBadRequest)
+	saveSeat(ctx, w, r, current, currentDoc, body)
+}
+
+// PatchSeat: seat의 일부 필드를 JSON Merge Patch(application/merge-patch+json) 또는
+// JSON Patch(application/json-patch+json)로 변경합니다.
+// 알 수 없는 필드, 읽기 전용 필드 변경, 타입이 맞지 않는 값은 422와 필드 목록으로 응답합니다.
+// If-Match 헤더를 보내면 현재 ETag가 목록에 있거나 "*"일 때만 처리하며, 아니면 412를 반환합니다.
+func PatchSeat(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	seatCode, err := strconv.Atoi(vars["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	// 다른 사용자의 변경을 덮어쓰지 않도록 If-Match 버전을 확인합니다.
+	match, ok := ifMatch(r)
+	if !ok {
+		writePreconditionFailed(w)
 		return
 	}
 
-	query := "UPDATE seat_table SET " + strings.Join(updates, ", ") + " WHERE seat_code = $" + strconv.Itoa(idx)
-	args = append(args, seatCode)
-	_, err = utils.DB.ExecContext(ctx, query, args...)
+	current, ok := currentSeat(ctx, w, companyCode, seatCode, match)
+	if !ok {
+		return
+	}
+	currentDoc, err := toDocument(current)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	doc, err := applyPatchRequest(r, currentDoc)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writePatchError(w, err)
 		return
 	}
+	saveSeat(ctx, w, r, current, currentDoc, doc)
+}
 
-	// 업데이트 후 비동기 작업 큐에 작업을 넣어 (예: seat 업데이트 알림) 백그라운드 처리를 수행합니다.
-	job := utils.Job{
-		Name: "SeatUpdated",
-		Data: map[string]interface{}{
-			"seat_code": seatCode,
-			"time":      time.Now(),
-		},
+// currentSeat은 수정할 seat을 조회합니다.
+// 없으면 404, If-Match 버전과 다르면 412로 응답하고 false를 반환합니다.
+func currentSeat(ctx context.Context, w http.ResponseWriter, companyCode, seatCode int, match *etagCondition) (Seat, bool) {
+	seat, err := seatRepo.Get(ctx, companyCode, seatCode)
+	if errors.Is(err, ErrNotFound) {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+		return Seat{}, false
+	}
+	if err != nil {
+		writeInternalError(w, err)
+		return Seat{}, false
 	}
-	if utils.EnqueueJobHandler != nil {
-		utils.EnqueueJobHandler(job)
+	if !match.matches(seat.rowVersion()) {
+		writePreconditionFailed(w)
+		return Seat{}, false
 	}
+	return seat, true
+}
 
-	// 업데이트된 seat을 조회하여 반환합니다.
-	var seat Seat
-	err = utils.DB.QueryRowContext(ctx, `
-        SELECT auto_increment, company_code, seat_code, seat_title,
-               title_background_color, title_text_color, seat_background_color,
-               seat_top, seat_left, seat_width, seat_height,
-               gender, waiting, release, hide_title,
-               transparent_background, hide_border, kiosk_disabled,
-               power_control, breaker_number
-        FROM seat_table WHERE seat_code = $1`, seatCode).
-		Scan(&seat.AutoIncrement, &seat.CompanyCode, &seat.SeatCode, &seat.SeatTitle,
-			&seat.TitleBackgroundColor, &seat.TitleTextColor, &seat.SeatBackgroundColor,
-			&seat.SeatTop, &seat.SeatLeft, &seat.SeatWidth, &seat.SeatHeight,
-			&seat.Gender, &seat.Waiting, &seat.Release, &seat.HideTitle,
-			&seat.TransparentBackground, &seat.HideBorder, &seat.KioskDisabled,
-			&seat.PowerControl, &seat.BreakerNumber)
+// saveSeat은 변경 후 문서 doc을 검사하고 현재 값과 달라진 필드만 저장합니다. (PUT/PATCH 공통)
+// 조회한 뒤 다른 요청이 먼저 변경했다면 덮어쓰지 않고 412로 응답합니다.
+func saveSeat(ctx context.Context, w http.ResponseWriter, r *http.Request, current Seat, currentDoc, doc map[string]interface{}) {
+	if errs := validateDocument(doc, currentDoc, seatUpdateColumns); len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+	var updated Seat
+	if err := fromDocument(doc, &updated); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	fields := changedFields(doc, currentDoc, seatUpdateColumns)
+	if len(fields) == 0 {
+		setETag(w, current.rowVersion())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(current)
+		return
+	}
+	// 값 검사는 바꾸는 필드만 대상으로 합니다. 기존 값 때문에 다른 필드 수정이 막히지 않도록 합니다.
+	if errs := validateSeat(updated).only(fields); len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+	// 위치, 크기, room을 바꾸는 경우 변경 후 배치를 검사합니다.
+	if !forceLayout(r) && hasAllowedField(fields, seatLayoutColumns) {
+		v, err := checkSeatLayout(ctx, updated.CompanyCode, updated.RoomCode, []Seat{updated})
+		if err != nil {
+			writeRepoError(w, err, "")
+			return
+		}
+		if !v.Empty() {
+			writeLayoutError(w, v)
+			return
+		}
+	}
+
+	version := current.rowVersion()
+	seat, err := seatRepo.Update(ctx, current.CompanyCode, current.SeatCode, fields, &version)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, ErrNotFound) {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+		} else if errors.Is(err, ErrVersionMismatch) {
+			writePreconditionFailed(w)
+		} else {
+			writeRepoError(w, err, "이미 존재하는 seat code입니다")
+		}
 		return
 	}
+
+	// 업데이트 후 비동기 작업 큐에 작업을 넣어 (예: seat 업데이트 알림) 백그라운드 처리를 수행합니다.
+	enqueueEvent("SeatUpdated", map[string]interface{}{
+		"company_code": seat.CompanyCode,
+		"room_code":    seat.RoomCode,
+		"seat_code":    seat.SeatCode,
+	})
+	// 대기열 사용/개방 설정이 바뀌면 비어 있는 좌석을 대기자에게 배정합니다.
+	if hasAllowedField(fields, seatWaitlistColumns) {
+		enqueueWaitlistPromote(seat.CompanyCode, seat.SeatCode)
+	}
+
+	setETag(w, seat.rowVersion())
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(seat)
 }
 
-// DeleteSeat: seat을 삭제합니다.
+// DeleteSeat: seat을 삭제합니다. 사용 중이거나 예약/고정석 이용권이 있는 seat이면 409를 반환합니다.
+// If-Match 헤더를 보내면 현재 ETag가 목록에 있거나 "*"일 때만 처리하며, 아니면 412를 반환합니다.
 func DeleteSeat(w http.ResponseWriter, r *http.Request) {
 	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
@@ -454,13 +577,51 @@ func DeleteSeat(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	seatCode, err := strconv.Atoi(vars["seat_code"])
 	if err != nil {
-		http.Error(w, "잘못된 seat_code", http.StatusBadRequest)
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	// 다른 사용자가 변경한 행을 모르고 지우지 않도록 If-Match 버전을 확인합니다.
+	match, ok := ifMatch(r)
+	if !ok {
+		writePreconditionFailed(w)
+		return
+	}
+
+	// 조건에 맞는 현재 버전을 조회한 뒤, 삭제 시점에도 그 버전일 때만 삭제합니다.
+	var version *RowVersion
+	if match != nil {
+		current, ok := currentSeat(ctx, w, companyCode, seatCode, match)
+		if !ok {
+			return
+		}
+		v := current.rowVersion()
+		version = &v
+	}
+
+	// 삭제된 seat의 room_code를 이벤트에 담습니다.
+	seat, err := seatRepo.Delete(ctx, companyCode, seatCode, version)
+	if errors.Is(err, ErrNotFound) {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+		return
+	}
+	if errors.Is(err, ErrVersionMismatch) {
+		writePreconditionFailed(w)
+		return
+	}
+	if errors.Is(err, ErrSeatInUse) {
+		writeProblem(w, consts.ERR_SEAT_IN_USE, "사용 중이거나 예약/고정석 이용권이 있는 좌석은 삭제할 수 없습니다.")
 		return
 	}
-	_, err = utils.DB.ExecContext(ctx, "DELETE FROM seat_table WHERE seat_code = $1", seatCode)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeInternalError(w, err)
 		return
 	}
+	enqueueEvent("SeatDeleted", map[string]interface{}{
+		"company_code": companyCode,
+		"room_code":    seat.RoomCode,
+		"seat_code":    seatCode,
+	})
 	w.WriteHeader(http.StatusNoContent)
 }
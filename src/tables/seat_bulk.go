@@ -0,0 +1,187 @@
+// seat_bulk.go
+package tables
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// SeatGrid는 room 안에 seat을 격자 모양으로 배치하는 요청입니다.
+// 좌석 위치는 (top, left)에서 시작하여 seat 크기와 간격만큼 떨어져 계산됩니다.
+type SeatGrid struct {
+	Rows          int `json:"rows"`
+	Columns       int `json:"columns"`
+	StartSeatCode int `json:"start_seat_code"` // 첫 seat_code (기본 1), 행 우선으로 1씩 증가합니다.
+	// TitlePattern은 seat_title 형식입니다. (기본 "{seat_code}")
+	// {seat_code}, {row}, {col}, {n}(1부터 시작하는 순번)을 사용할 수 있으며 row/col도 1부터 시작합니다.
+	TitlePattern string `json:"title_pattern"`
+	Top          int    `json:"top"`
+	Left         int    `json:"left"`
+	SpacingX     int    `json:"spacing_x"` // 좌우 seat 사이 간격
+	SpacingY     int    `json:"spacing_y"` // 상하 seat 사이 간격
+	// Seat은 모든 seat에 공통으로 적용할 속성입니다. (크기, 색상, gender 등)
+	// seat_code, room_code, seat_title, seat_top, seat_left는 무시됩니다.
+	Seat Seat `json:"seat"`
+}
+
+// seats는 격자 요청을 seat 목록으로 펼칩니다.
+func (g SeatGrid) seats() ([]Seat, error) {
+	if g.Rows <= 0 || g.Columns <= 0 {
+		return nil, fmt.Errorf("rows와 columns는 1 이상이어야 합니다")
+	}
+	if g.Rows > consts.BULK_SEAT_LIMIT || g.Columns > consts.BULK_SEAT_LIMIT || g.Rows*g.Columns > consts.BULK_SEAT_LIMIT {
+		return nil, fmt.Errorf("한 번에 생성할 수 있는 seat은 최대 %d개입니다", consts.BULK_SEAT_LIMIT)
+	}
+	if g.SpacingX < 0 || g.SpacingY < 0 || g.Top < 0 || g.Left < 0 {
+		return nil, fmt.Errorf("위치와 간격은 0 이상이어야 합니다")
+	}
+	if g.StartSeatCode == 0 {
+		g.StartSeatCode = 1
+	}
+	if g.TitlePattern == "" {
+		g.TitlePattern = "{seat_code}"
+	}
+
+	template := g.Seat
+	applySeatDefaults(&template)
+
+	seats := make([]Seat, 0, g.Rows*g.Columns)
+	for row := 0; row < g.Rows; row++ {
+		for col := 0; col < g.Columns; col++ {
+			n := row*g.Columns + col
+			seat := template
+			seat.SeatCode = g.StartSeatCode + n
+			seat.SeatTop = g.Top + row*(template.SeatHeight+g.SpacingY)
+			seat.SeatLeft = g.Left + col*(template.SeatWidth+g.SpacingX)
+			seat.SeatTitle = strings.NewReplacer(
+				"{seat_code}", strconv.Itoa(seat.SeatCode),
+				"{row}", strconv.Itoa(row+1),
+				"{col}", strconv.Itoa(col+1),
+				"{n}", strconv.Itoa(n+1),
+			).Replace(g.TitlePattern)
+			seats = append(seats, seat)
+		}
+	}
+	return seats, nil
+}
+
+// CreateRoomSeatsBulk: URL의 room에 seat을 일괄 생성합니다.
+// 본문은 seat 배열 또는 SeatGrid 객체이며, 모든 seat을 한 트랜잭션으로 저장하여
+// 하나라도 실패하면 아무것도 생성하지 않습니다. 배치 검사는 force=true로 건너뛸 수 있습니다.
+func CreateRoomSeatsBulk(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.LONG_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	roomCode, err := strconv.Atoi(mux.Vars(r)["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	// 배열이면 seat 목록, 객체이면 격자 배치 요청으로 처리합니다.
+	var seats []Seat
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &seats); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		if len(seats) > consts.BULK_SEAT_LIMIT {
+			writeProblem(w, consts.ERR_INVALID_BODY, fmt.Sprintf("한 번에 생성할 수 있는 seat은 최대 %d개입니다", consts.BULK_SEAT_LIMIT))
+			return
+		}
+		for i := range seats {
+			applySeatDefaults(&seats[i])
+		}
+	} else {
+		var grid SeatGrid
+		if err := json.Unmarshal(trimmed, &grid); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		if seats, err = grid.seats(); err != nil {
+			writeProblem(w, consts.ERR_INVALID_BODY, err.Error())
+			return
+		}
+	}
+	if len(seats) == 0 {
+		writeProblem(w, consts.ERR_INVALID_BODY, "생성할 seat이 없습니다.")
+		return
+	}
+
+	// 모든 seat은 호출자의 company_code와 URL의 room에 속합니다.
+	// 필드 값 오류는 "[번호].필드" 형식으로 모아서 한 번에 응답합니다.
+	seen := make(map[int]bool, len(seats))
+	errs := make(fieldErrors)
+	for i := range seats {
+		seat := &seats[i]
+		if seat.RoomCode != 0 && seat.RoomCode != roomCode {
+			writeProblem(w, consts.ERR_INVALID_BODY, "URL과 body의 room_code가 다릅니다.")
+			return
+		}
+		if seen[seat.SeatCode] {
+			writeProblem(w, consts.ERR_DUPLICATE_CODE, fmt.Sprintf("요청에 중복된 seat_code가 있습니다: %d", seat.SeatCode))
+			return
+		}
+		seen[seat.SeatCode] = true
+		seat.CompanyCode = companyCode
+		seat.RoomCode = roomCode
+		errs.merge(fmt.Sprintf("[%d].", i), validateSeat(*seat))
+	}
+	if len(errs) > 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+
+	// 요청한 seat끼리 또는 기존 seat과 겹치거나 room 영역을 벗어나면 하나도 만들지 않습니다.
+	if !forceLayout(r) {
+		v, err := checkSeatLayout(ctx, companyCode, roomCode, seats)
+		if err != nil {
+			writeRepoError(w, err, "")
+			return
+		}
+		if !v.Empty() {
+			writeLayoutError(w, v)
+			return
+		}
+	}
+
+	created, err := seatRepo.CreateMany(ctx, seats)
+	if err != nil {
+		log.Printf("Seat 일괄 생성 오류: %v", err)
+		writeRepoError(w, err, "이미 존재하는 seat code가 있습니다")
+		return
+	}
+	log.Printf("Seat 일괄 생성: company_code=%d room_code=%d, %d개", companyCode, roomCode, len(created))
+
+	for _, seat := range created {
+		enqueueEvent("SeatCreated", map[string]interface{}{
+			"company_code": seat.CompanyCode,
+			"room_code":    seat.RoomCode,
+			"seat_code":    seat.SeatCode,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
@@ -0,0 +1,768 @@
+// seat_session.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// SeatSession 구조체는 seat_session_table의 각 컬럼을 매핑합니다.
+// EndTime이 nil이면 아직 체크아웃하지 않은(사용 중인) 세션입니다.
+type SeatSession struct {
+	AutoIncrement  int        `json:"auto_increment" db:"auto_increment"`
+	CompanyCode    int        `json:"company_code"`
+	SeatCode       int        `json:"seat_code"`
+	MemberID       int        `json:"member_id"`
+	PassID         *int       `json:"pass_id"`
+	StartTime      time.Time  `json:"start_time"`
+	PlannedEndTime *time.Time `json:"planned_end_time"`
+	EndTime        *time.Time `json:"end_time"`
+}
+
+// RegisterSeatSessionRoutes는 좌석 체크인/체크아웃 엔드포인트를 등록합니다.
+// 회원은 본인 명의의 세션만 다룰 수 있습니다.
+func RegisterSeatSessionRoutes(r *mux.Router) {
+	r.Handle("/seats/{seat_code}/checkin", allow(CheckInSeat, memberRoles...)).Methods("POST")
+	r.Handle("/seats/{seat_code}/checkout", allow(CheckOutSeat, memberRoles...)).Methods("POST")
+	r.Handle("/seats/{seat_code}/extend", allow(ExtendSeatSession, memberRoles...)).Methods("POST")
+	r.Handle("/seats/{seat_code}/move", allow(MoveSeatSession, memberRoles...)).Methods("POST")
+}
+
+// RegisterSeatSessionJobs는 좌석 이용 세션 관련 비동기 작업 처리 함수를 등록합니다.
+func RegisterSeatSessionJobs() {
+	utils.RegisterJobHandler("SeatSessionExpire", closeExpiredSessions)
+}
+
+// checkInRequest는 체크인 요청 본문입니다.
+// planned_end_time 대신 duration_minutes로 이용 시간을 지정할 수도 있습니다.
+type checkInRequest struct {
+	MemberID        int        `json:"member_id"`
+	PlannedEndTime  *time.Time `json:"planned_end_time"`
+	DurationMinutes int        `json:"duration_minutes"`
+}
+
+// CheckInSeat: 좌석에 새로운 이용 세션을 시작합니다.
+// 이미 열린 세션이 있는 좌석이면 409를, 사용 가능한 이용권이 없으면 403을 반환합니다.
+// 예정 종료 시각은 이용권의 잔여 시간/만료 시각과 다른 회원의 다음 예약 시작 시각을 넘지 않습니다.
+func CheckInSeat(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	seatCode, err := strconv.Atoi(mux.Vars(r)["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req checkInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if !bindCallerMember(w, r, &req.MemberID) {
+		return
+	}
+	if req.MemberID <= 0 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "member_id가 필요합니다.")
+		return
+	}
+
+	// 정상 이용 중인 회원만 체크인할 수 있습니다.
+	member, err := getMember(ctx, companyCode, req.MemberID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "존재하지 않는 member_id입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if member.Status != consts.MEMBER_STATUS_ACTIVE {
+		writeProblem(w, consts.ERR_MEMBER_INACTIVE, "이용할 수 없는 회원 상태입니다.")
+		return
+	}
+
+	// seat/room의 성별 제한 확인 (다른 회사의 seat는 존재하지 않는 것으로 취급합니다)
+	allowed, err := seatGenderAllowed(ctx, companyCode, seatCode, member.Gender)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if !allowed {
+		writeProblem(w, consts.ERR_GENDER_MISMATCH, "좌석의 성별 제한과 회원 성별이 일치하지 않습니다.")
+		return
+	}
+
+	// 예약/대기열 확인부터 세션 생성까지 좌석 행을 잠근 하나의 트랜잭션에서 처리해
+	// 같은 좌석에 대한 동시 체크인/예약이 서로의 확인 결과를 무효화하지 못하게 합니다.
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer tx.Rollback()
+
+	roomCode, err := lockSeat(ctx, tx, companyCode, seatCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Seat를 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	now := time.Now()
+
+	// 다른 회원이 예약한 시간대에는 체크인할 수 없습니다.
+	reservationID, reservedByOther, err := claimReservation(ctx, tx, companyCode, seatCode, req.MemberID, now)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if reservedByOther {
+		writeProblem(w, consts.ERR_SEAT_RESERVED, "다른 회원이 예약한 좌석입니다.")
+		return
+	}
+
+	// 대기열에서 다른 회원에게 배정된 좌석에도 체크인할 수 없습니다.
+	waitlistID, heldByOther, err := claimWaitlistHold(ctx, tx, companyCode, seatCode, req.MemberID, now)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if heldByOther {
+		writeProblem(w, consts.ERR_SEAT_RESERVED, "대기자에게 배정된 좌석입니다.")
+		return
+	}
+
+	// 잔여 시간이 남아 있고 만료되지 않은 이용권이 있어야 체크인할 수 있습니다.
+	pass, err := findUsablePass(ctx, tx, companyCode, req.MemberID, seatCode, now)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_PASS_UNAVAILABLE, "사용 가능한 이용권이 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	session := SeatSession{
+		CompanyCode:    companyCode,
+		SeatCode:       seatCode,
+		MemberID:       req.MemberID,
+		PassID:         &pass.PassID,
+		StartTime:      now,
+		PlannedEndTime: req.PlannedEndTime,
+	}
+	if session.PlannedEndTime == nil && req.DurationMinutes > 0 {
+		end := session.StartTime.Add(time.Duration(req.DurationMinutes) * time.Minute)
+		session.PlannedEndTime = &end
+	}
+	// 이용권의 잔여 시간/만료 시각을 넘지 않도록 예정 종료 시각을 제한합니다.
+	if pass.RemainingMinutes != nil {
+		limit := now.Add(time.Duration(*pass.RemainingMinutes) * time.Minute)
+		if session.PlannedEndTime == nil || session.PlannedEndTime.After(limit) {
+			session.PlannedEndTime = &limit
+		}
+	}
+	if pass.ExpiresAt != nil {
+		if session.PlannedEndTime == nil || session.PlannedEndTime.After(*pass.ExpiresAt) {
+			expires := *pass.ExpiresAt
+			session.PlannedEndTime = &expires
+		}
+	}
+	// 다른 회원의 다음 예약이 시작되기 전에 끝나도록 예정 종료 시각을 제한합니다.
+	next, err := nextReservationStart(ctx, tx, companyCode, seatCode, req.MemberID, now)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if next != nil && (session.PlannedEndTime == nil || session.PlannedEndTime.After(*next)) {
+		session.PlannedEndTime = next
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO seat_session_table
+		(company_code, seat_code, member_id, pass_id, start_time, planned_end_time)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING auto_increment`,
+		session.CompanyCode, session.SeatCode, session.MemberID, session.PassID,
+		session.StartTime, session.PlannedEndTime).Scan(&session.AutoIncrement)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		if isPQError(err, "23505") { // unique_violation (seat_session_open_company_seat_idx)
+			writeProblem(w, consts.ERR_SEAT_OCCUPIED, "이미 사용 중인 좌석입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	// 본인 예약/대기열 배정으로 체크인한 경우 같은 트랜잭션에서 상태를 갱신합니다.
+	if reservationID != 0 {
+		if err := markReservationCheckedIn(ctx, tx, reservationID); err != nil {
+			writeInternalError(w, err)
+			return
+		}
+	}
+	if waitlistID != 0 {
+		if err := markWaitlistSeated(ctx, tx, waitlistID); err != nil {
+			writeInternalError(w, err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	enqueueSeatSessionJob("SeatCheckedIn", session, roomCode)
+	// power_control이 켜진 좌석/룸의 차단기를 켭니다.
+	enqueuePowerSync(session.CompanyCode, session.SeatCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+// CheckOutSeat: 좌석의 열린 세션을 종료하고 사용 시간을 이용권에서 차감합니다.
+// 열린 세션이 없으면(회원 호출자는 본인의 열린 세션이 없으면) 409를 반환합니다.
+func CheckOutSeat(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	seatCode, err := strconv.Atoi(mux.Vars(r)["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	// 회원/키오스크 호출자는 본인 세션으로 한정합니다. (0이면 조건 없음)
+	// 소유자 확인을 UPDATE 조건에 넣어 확인과 종료 사이에 세션이 바뀌지 않도록 합니다.
+	ownerID, _ := callerMemberID(r)
+
+	// 세션 종료와 이용권 차감은 하나의 트랜잭션으로 처리합니다.
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer tx.Rollback()
+
+	var session SeatSession
+	var roomCode int
+	err = tx.QueryRowContext(ctx, `
+		UPDATE seat_session_table SET end_time = $1
+		WHERE company_code = $2 AND seat_code = $3 AND end_time IS NULL
+		  AND ($4 = 0 OR member_id = $4)
+		RETURNING auto_increment, company_code, seat_code, member_id, pass_id,
+		          start_time, planned_end_time, end_time, `+seatRoomCodeExpr("seat_session_table"),
+		time.Now(), companyCode, seatCode, ownerID).
+		Scan(&session.AutoIncrement, &session.CompanyCode, &session.SeatCode, &session.MemberID,
+			&session.PassID, &session.StartTime, &session.PlannedEndTime, &session.EndTime, &roomCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NO_OPEN_SESSION, "사용 중인 세션이 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	if session.PassID != nil {
+		used := session.EndTime.Sub(session.StartTime)
+		if err := deductPassMinutes(ctx, tx, *session.PassID, used); err != nil {
+			writeInternalError(w, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	enqueueSeatSessionJob("SeatCheckedOut", session, roomCode)
+	// power_control이 켜진 좌석/룸의 차단기를 끕니다. (공유 좌석이 사용 중이면 유지)
+	enqueuePowerSync(session.CompanyCode, session.SeatCode)
+	// 비워진 좌석을 대기자에게 배정합니다.
+	enqueueWaitlistPromote(session.CompanyCode, session.SeatCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// closeExpiredSessions는 예정 종료 시각이 지난 열린 세션을 예정 종료 시각으로 종료하고 이용권에서 사용 시간을 차감합니다.
+// SeatSessionExpire 작업으로 주기 실행됩니다.
+func closeExpiredSessions(ctx context.Context, job utils.Job) error {
+	rows, err := utils.DB.QueryContext(ctx, `
+		SELECT auto_increment FROM seat_session_table
+		WHERE end_time IS NULL AND planned_end_time < $1`, time.Now())
+	if err != nil {
+		return err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	closed := 0
+	for _, id := range ids {
+		session, roomCode, ok, err := closeExpiredSession(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		closed++
+		enqueueSeatSessionJob("SeatCheckedOut", session, roomCode)
+		enqueuePowerSync(session.CompanyCode, session.SeatCode)
+		enqueueWaitlistPromote(session.CompanyCode, session.SeatCode)
+	}
+	if closed > 0 {
+		log.Printf("예정 종료 시각이 지난 세션 %d건 종료", closed)
+	}
+	return nil
+}
+
+// closeExpiredSession은 세션 하나를 예정 종료 시각으로 종료하고 이용권을 차감합니다.
+// 종료한 세션과 seat의 room_code를 반환하며, 그 사이 체크아웃/연장되어 더 이상 만료 대상이 아니면 ok가 false입니다.
+func closeExpiredSession(ctx context.Context, id int) (session SeatSession, roomCode int, ok bool, err error) {
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return session, 0, false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE seat_session_table SET end_time = planned_end_time
+		WHERE auto_increment = $1 AND end_time IS NULL AND planned_end_time < $2
+		RETURNING auto_increment, company_code, seat_code, member_id, pass_id,
+		          start_time, planned_end_time, end_time, `+seatRoomCodeExpr("seat_session_table"),
+		id, time.Now()).
+		Scan(&session.AutoIncrement, &session.CompanyCode, &session.SeatCode, &session.MemberID,
+			&session.PassID, &session.StartTime, &session.PlannedEndTime, &session.EndTime, &roomCode)
+	if err == sql.ErrNoRows {
+		return session, 0, false, nil
+	}
+	if err != nil {
+		return session, 0, false, err
+	}
+
+	if session.PassID != nil {
+		used := session.EndTime.Sub(session.StartTime)
+		if err := deductPassMinutes(ctx, tx, *session.PassID, used); err != nil {
+			return session, 0, false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return session, 0, false, err
+	}
+	return session, roomCode, true, nil
+}
+
+// extendSessionRequest는 이용 시간 연장 요청 본문입니다.
+// member_id를 지정하면 세션 이용자와 일치하는지 확인합니다.
+type extendSessionRequest struct {
+	MemberID int `json:"member_id"`
+	Minutes  int `json:"minutes"`
+}
+
+// ExtendSeatSession: 좌석의 열린 세션의 예정 종료 시각을 연장합니다.
+// 이용권 잔여 시간/만료 시각을 넘기거나 다른 회원의 예약과 겹치면 연장할 수 없습니다.
+func ExtendSeatSession(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	seatCode, err := strconv.Atoi(mux.Vars(r)["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req extendSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if !bindCallerMember(w, r, &req.MemberID) {
+		return
+	}
+	if req.Minutes <= 0 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "minutes는 1 이상이어야 합니다.")
+		return
+	}
+
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer tx.Rollback()
+
+	session, err := getOpenSession(ctx, tx, companyCode, seatCode, true)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NO_OPEN_SESSION, "사용 중인 세션이 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if req.MemberID != 0 && req.MemberID != session.MemberID {
+		writeProblem(w, consts.ERR_FORBIDDEN, "본인의 세션만 연장할 수 있습니다.")
+		return
+	}
+
+	now := time.Now()
+	base := now
+	if session.PlannedEndTime != nil && session.PlannedEndTime.After(now) {
+		base = *session.PlannedEndTime
+	}
+	newEnd := base.Add(time.Duration(req.Minutes) * time.Minute)
+
+	// 이용권 잔여 시간은 체크아웃 시 차감되므로 세션 시작 시각을 기준으로 한도를 계산합니다.
+	if session.PassID != nil {
+		pass, err := scanPass(tx.QueryRowContext(ctx,
+			"SELECT "+passColumns+" FROM pass_table WHERE pass_id = $1", *session.PassID))
+		if err != nil {
+			writeInternalError(w, err)
+			return
+		}
+		if pass.RemainingMinutes != nil &&
+			newEnd.After(session.StartTime.Add(time.Duration(*pass.RemainingMinutes)*time.Minute)) {
+			writeProblem(w, consts.ERR_PASS_UNAVAILABLE, "이용권 잔여 시간이 부족합니다.")
+			return
+		}
+		if pass.ExpiresAt != nil && newEnd.After(*pass.ExpiresAt) {
+			writeProblem(w, consts.ERR_PASS_UNAVAILABLE, "이용권 만료 시각을 넘겨 연장할 수 없습니다.")
+			return
+		}
+	}
+
+	var conflict bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM reservation_table
+		WHERE company_code = $1 AND seat_code = $2 AND member_id <> $3 AND status = $4
+		  AND start_time < $5 AND end_time > $6)`,
+		companyCode, seatCode, session.MemberID, consts.RESERVATION_STATUS_BOOKED, newEnd, now).Scan(&conflict)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if conflict {
+		writeProblem(w, consts.ERR_SEAT_RESERVED, "연장 시간이 다른 회원의 예약과 겹칩니다.")
+		return
+	}
+
+	var roomCode int
+	err = tx.QueryRowContext(ctx,
+		"UPDATE seat_session_table SET planned_end_time = $1 WHERE auto_increment = $2 RETURNING "+
+			seatRoomCodeExpr("seat_session_table"),
+		newEnd, session.AutoIncrement).Scan(&roomCode)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	session.PlannedEndTime = &newEnd
+
+	enqueueSeatSessionJob("SeatSessionExtended", session, roomCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// moveSessionRequest는 좌석 이동 요청 본문입니다.
+// member_id를 지정하면 세션 이용자와 일치하는지 확인합니다.
+type moveSessionRequest struct {
+	MemberID   int `json:"member_id"`
+	ToSeatCode int `json:"to_seat_code"`
+}
+
+// MoveSeatSession: 열린 세션을 다른 좌석으로 옮깁니다.
+// 이용 시간은 이어서 계산되며, 이동할 좌석이 사용 중이거나 다른 회원에게 예약/대기 배정된 경우 409를 반환합니다.
+// 이동할 좌석의 본인 예약/대기 배정은 체크인 처리하고, 예정 종료 시각은 그 좌석의 다음 예약 시작 시각을 넘지 않습니다.
+func MoveSeatSession(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	seatCode, err := strconv.Atoi(mux.Vars(r)["seat_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 seat_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req moveSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if !bindCallerMember(w, r, &req.MemberID) {
+		return
+	}
+	if req.ToSeatCode == 0 || req.ToSeatCode == seatCode {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "잘못된 to_seat_code")
+		return
+	}
+
+	// 이동할 좌석 확인과 세션 이동은 하나의 트랜잭션으로 처리합니다.
+	// 세션과 이동할 좌석 행을 잠가 같은 좌석으로의 동시 이동이나 체크아웃과 엇갈리지 않도록 합니다.
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer tx.Rollback()
+
+	session, err := getOpenSession(ctx, tx, companyCode, seatCode, true)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NO_OPEN_SESSION, "사용 중인 세션이 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if req.MemberID != 0 && req.MemberID != session.MemberID {
+		writeProblem(w, consts.ERR_FORBIDDEN, "본인의 세션만 이동할 수 있습니다.")
+		return
+	}
+
+	// 원래 좌석의 체크아웃 알림에 담을 room_code입니다.
+	var fromRoomCode int
+	err = tx.QueryRowContext(ctx,
+		"SELECT room_code FROM seat_table WHERE company_code = $1 AND seat_code = $2",
+		companyCode, seatCode).Scan(&fromRoomCode)
+	if err != nil && err != sql.ErrNoRows {
+		writeInternalError(w, err)
+		return
+	}
+
+	toRoomCode, err := lockSeat(ctx, tx, companyCode, req.ToSeatCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "이동할 Seat를 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	member, err := getMember(ctx, companyCode, session.MemberID)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	allowed, err := seatGenderAllowed(ctx, companyCode, req.ToSeatCode, member.Gender)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if !allowed {
+		writeProblem(w, consts.ERR_GENDER_MISMATCH, "좌석의 성별 제한과 회원 성별이 일치하지 않습니다.")
+		return
+	}
+
+	// 고정석 이용권은 지정 좌석에서만 사용할 수 있습니다.
+	if session.PassID != nil {
+		var passSeat sql.NullInt64
+		err := tx.QueryRowContext(ctx,
+			"SELECT seat_code FROM pass_table WHERE pass_id = $1", *session.PassID).Scan(&passSeat)
+		if err != nil && err != sql.ErrNoRows {
+			writeInternalError(w, err)
+			return
+		}
+		if passSeat.Valid && int(passSeat.Int64) != req.ToSeatCode {
+			writeProblem(w, consts.ERR_PASS_UNAVAILABLE, "고정석 이용권은 좌석을 이동할 수 없습니다.")
+			return
+		}
+	}
+
+	now := time.Now()
+	reservationID, reservedByOther, err := claimReservation(ctx, tx, companyCode, req.ToSeatCode, session.MemberID, now)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	waitlistID, heldByOther, err := claimWaitlistHold(ctx, tx, companyCode, req.ToSeatCode, session.MemberID, now)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if reservedByOther || heldByOther {
+		writeProblem(w, consts.ERR_SEAT_RESERVED, "이동할 좌석이 다른 회원에게 예약/배정되어 있습니다.")
+		return
+	}
+
+	// 이동할 좌석의 다른 회원 다음 예약이 시작되기 전에 끝나도록 예정 종료 시각을 다시 제한합니다.
+	next, err := nextReservationStart(ctx, tx, companyCode, req.ToSeatCode, session.MemberID, now)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if next != nil && (session.PlannedEndTime == nil || session.PlannedEndTime.After(*next)) {
+		session.PlannedEndTime = next
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE seat_session_table SET seat_code = $1, planned_end_time = $2
+		WHERE auto_increment = $3 AND end_time IS NULL`,
+		req.ToSeatCode, session.PlannedEndTime, session.AutoIncrement)
+	if err != nil {
+		if isPQError(err, "23505") { // unique_violation (seat_session_open_company_seat_idx)
+			writeProblem(w, consts.ERR_SEAT_OCCUPIED, "이동할 좌석이 이미 사용 중입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NO_OPEN_SESSION, "사용 중인 세션이 없습니다.")
+		return
+	}
+
+	// 이동할 좌석의 본인 예약/대기열 배정은 같은 트랜잭션에서 체크인 처리합니다.
+	if reservationID != 0 {
+		if err := markReservationCheckedIn(ctx, tx, reservationID); err != nil {
+			writeInternalError(w, err)
+			return
+		}
+	}
+	if waitlistID != 0 {
+		if err := markWaitlistSeated(ctx, tx, waitlistID); err != nil {
+			writeInternalError(w, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	session.SeatCode = req.ToSeatCode
+
+	enqueueSeatSessionJob("SeatCheckedOut", SeatSession{CompanyCode: companyCode, SeatCode: seatCode, MemberID: session.MemberID}, fromRoomCode)
+	enqueueSeatSessionJob("SeatCheckedIn", session, toRoomCode)
+	enqueuePowerSync(companyCode, seatCode)
+	enqueuePowerSync(companyCode, req.ToSeatCode)
+	enqueueWaitlistPromote(companyCode, seatCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// lockSeat은 트랜잭션 안에서 seat 행을 잠그고 seat의 room_code를 반환합니다. seat가 없으면 sql.ErrNoRows를 반환합니다.
+// 같은 좌석의 체크인/좌석 이동/예약 생성이 예약·세션 확인부터 반영까지 차례로 처리되게 합니다.
+func lockSeat(ctx context.Context, tx *sql.Tx, companyCode, seatCode int) (roomCode int, err error) {
+	err = tx.QueryRowContext(ctx,
+		"SELECT room_code FROM seat_table WHERE company_code = $1 AND seat_code = $2 FOR UPDATE",
+		companyCode, seatCode).Scan(&roomCode)
+	return roomCode, err
+}
+
+// seatRoomCodeExpr는 table 행(company_code, seat_code)의 seat이 속한 room_code를 읽는 SQL 식입니다.
+// 세션/예약을 변경하는 쿼리의 RETURNING 절에서 이벤트에 담을 room_code를 함께 읽을 때 사용합니다. (seat이 없으면 0)
+func seatRoomCodeExpr(table string) string {
+	return "COALESCE((SELECT s.room_code FROM seat_table s WHERE s.company_code = " + table +
+		".company_code AND s.seat_code = " + table + ".seat_code), 0)"
+}
+
+// sessionQueryer는 *sql.DB와 *sql.Tx가 공통으로 제공하는 단건 조회 메서드입니다.
+type sessionQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getOpenSession은 좌석의 열린 세션을 조회합니다. 없으면 sql.ErrNoRows를 반환합니다.
+// forUpdate가 true이면 트랜잭션 안에서 행을 잠급니다.
+func getOpenSession(ctx context.Context, q sessionQueryer, companyCode, seatCode int, forUpdate bool) (SeatSession, error) {
+	query := `
+		SELECT auto_increment, company_code, seat_code, member_id, pass_id,
+		       start_time, planned_end_time, end_time
+		FROM seat_session_table
+		WHERE company_code = $1 AND seat_code = $2 AND end_time IS NULL`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+	var s SeatSession
+	err := q.QueryRowContext(ctx, query, companyCode, seatCode).
+		Scan(&s.AutoIncrement, &s.CompanyCode, &s.SeatCode, &s.MemberID,
+			&s.PassID, &s.StartTime, &s.PlannedEndTime, &s.EndTime)
+	return s, err
+}
+
+// enqueueSeatSessionJob은 세션 변경 알림 작업을 큐에 넣습니다.
+// roomCode는 세션 seat이 속한 room이며, 이벤트 구독자의 room 필터에 사용됩니다.
+func enqueueSeatSessionJob(name string, session SeatSession, roomCode int) {
+	enqueueEvent(name, map[string]interface{}{
+		"company_code": session.CompanyCode,
+		"room_code":    roomCode,
+		"seat_code":    session.SeatCode,
+		"member_id":    session.MemberID,
+	})
+}
+
+// openSessionsBySeat는 주어진 seat_code들의 열린 세션을 seat_code 기준으로 반환합니다.
+func openSessionsBySeat(ctx context.Context, companyCode int, seatCodes []int64) (map[int64]SeatSession, error) {
+	result := make(map[int64]SeatSession)
+	if len(seatCodes) == 0 {
+		return result, nil
+	}
+
+	rows, err := utils.DB.QueryContext(ctx, `
+		SELECT auto_increment, company_code, seat_code, member_id, pass_id,
+		       start_time, planned_end_time, end_time
+		FROM seat_session_table
+		WHERE end_time IS NULL AND company_code = $1 AND seat_code = ANY($2)`,
+		companyCode, pq.Array(seatCodes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s SeatSession
+		if err := rows.Scan(&s.AutoIncrement, &s.CompanyCode, &s.SeatCode, &s.MemberID,
+			&s.PassID, &s.StartTime, &s.PlannedEndTime, &s.EndTime); err != nil {
+			return nil, err
+		}
+		result[int64(s.SeatCode)] = s
+	}
+	return result, rows.Err()
+}
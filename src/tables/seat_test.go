@@ -0,0 +1,152 @@
+package tables
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"AllinB/src/consts"
+)
+
+// newSeatTestRouter는 기본 크기(100x100) seat가 가로로 10개 들어가는 room 1이 준비된 테스트 라우터를 만듭니다.
+func newSeatTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	r, _ := newTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1, "room_width": 1000}`}, http.StatusCreated)
+	return r
+}
+
+func TestSeatCRUD(t *testing.T) {
+	r := newSeatTestRouter(t)
+
+	rec := mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 1, "seat_title": "1번"}`}, http.StatusCreated)
+	var seat Seat
+	if err := json.Unmarshal(rec.Body.Bytes(), &seat); err != nil {
+		t.Fatal(err)
+	}
+	if seat.RoomCode != 1 || seat.CompanyCode != testCompanyCode {
+		t.Fatalf("room_code/company_code가 채워지지 않았습니다: %+v", seat)
+	}
+	mustServe(t, r, testRequest{Method: "POST", Target: "/seats", Body: `{"seat_code": 2, "room_code": 1, "seat_left": 100}`}, http.StatusCreated)
+
+	rec = mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/1/seats"}, http.StatusOK)
+	var seats []Seat
+	json.Unmarshal(rec.Body.Bytes(), &seats)
+	if len(seats) != 2 {
+		t.Fatalf("GET /rooms/1/seats = %s", rec.Body.String())
+	}
+	mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/2/seats"}, http.StatusNotFound)
+
+	rec = mustServe(t, r, testRequest{Method: "GET", Target: "/seats/1"}, http.StatusOK)
+	etag := rec.Header().Get("ETag")
+	mustServe(t, r, testRequest{
+		Method: "PATCH", Target: "/seats/1", Body: `{"seat_title": "창가"}`,
+		Header: map[string]string{"Content-Type": "application/merge-patch+json", "If-Match": etag},
+	}, http.StatusOK)
+	mustServe(t, r, testRequest{
+		Method: "PATCH", Target: "/seats/1", Body: `{"seat_title": "복도"}`,
+		Header: map[string]string{"Content-Type": "application/merge-patch+json", "If-Match": etag},
+	}, http.StatusPreconditionFailed)
+
+	rec = mustServe(t, r, testRequest{Method: "GET", Target: "/seats/1"}, http.StatusOK)
+	json.Unmarshal(rec.Body.Bytes(), &seat)
+	if seat.SeatTitle != "창가" {
+		t.Fatalf("seat_title = %s, want 창가", seat.SeatTitle)
+	}
+
+	mustServe(t, r, testRequest{Method: "DELETE", Target: "/seats/1"}, http.StatusNoContent)
+	mustServe(t, r, testRequest{Method: "GET", Target: "/seats/1"}, http.StatusNotFound)
+	mustServe(t, r, testRequest{Method: "DELETE", Target: "/seats/1"}, http.StatusNotFound)
+}
+
+func TestDeleteSeatInUse(t *testing.T) {
+	r, store := newTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 1}`}, http.StatusCreated)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 1}`}, http.StatusCreated)
+
+	store.SetSeatInUse(testCompanyCode, 1, true)
+	rec := mustServe(t, r, testRequest{Method: "DELETE", Target: "/seats/1"}, http.StatusConflict)
+	if code := problemCode(t, rec); code != consts.ERR_SEAT_IN_USE {
+		t.Fatalf("code = %s, want %s", code, consts.ERR_SEAT_IN_USE)
+	}
+	mustServe(t, r, testRequest{Method: "GET", Target: "/seats/1"}, http.StatusOK)
+
+	store.SetSeatInUse(testCompanyCode, 1, false)
+	mustServe(t, r, testRequest{Method: "DELETE", Target: "/seats/1"}, http.StatusNoContent)
+}
+
+func TestSeatErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"없는 room에 생성", "POST", "/seats", `{"seat_code": 2, "room_code": 9}`, http.StatusConflict, consts.ERR_ROOM_NOT_FOUND},
+		{"없는 room 경로에 생성", "POST", "/rooms/9/seats", `{"seat_code": 2}`, http.StatusConflict, consts.ERR_ROOM_NOT_FOUND},
+		{"없는 room으로 이동", "PATCH", "/seats/1", `{"room_code": 9}`, http.StatusConflict, consts.ERR_ROOM_NOT_FOUND},
+		{"중복 seat_code", "POST", "/rooms/1/seats", `{"seat_code": 1}`, http.StatusBadRequest, consts.ERR_DUPLICATE_CODE},
+		{"잘못된 JSON", "POST", "/rooms/1/seats", `{`, http.StatusBadRequest, consts.ERR_INVALID_BODY},
+		{"잘못된 색상", "POST", "/rooms/1/seats", `{"seat_code": 2, "seat_left": 100, "seat_background_color": "blue"}`, http.StatusUnprocessableEntity, consts.ERR_INVALID_FIELDS},
+		{"잘못된 seat_code 경로", "GET", "/seats/abc", "", http.StatusBadRequest, consts.ERR_INVALID_PARAMETER},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSeatTestRouter(t)
+			mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 1}`}, http.StatusCreated)
+
+			req := testRequest{Method: tt.method, Target: tt.target, Body: tt.body}
+			if tt.method == "PATCH" {
+				req.Header = map[string]string{"Content-Type": "application/merge-patch+json"}
+			}
+			rec := mustServe(t, r, req, tt.wantStatus)
+			if code := problemCode(t, rec); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestSeatTenantIsolation(t *testing.T) {
+	r := newSeatTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 1}`}, http.StatusCreated)
+
+	other := testCompanyCode + 1
+	mustServe(t, r, testRequest{Method: "GET", Target: "/seats/1", CompanyCode: other}, http.StatusNotFound)
+	mustServe(t, r, testRequest{Method: "GET", Target: "/rooms/1/seats", CompanyCode: other}, http.StatusNotFound)
+	// 다른 회사의 room을 가리키는 seat는 만들 수 없습니다.
+	mustServe(t, r, testRequest{Method: "POST", Target: "/seats", Body: `{"seat_code": 1, "room_code": 1}`, CompanyCode: other}, http.StatusConflict)
+}
+
+func TestKioskHidesDisabledSeats(t *testing.T) {
+	r := newSeatTestRouter(t)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms", Body: `{"room_code": 2, "kiosk_disabled": 1}`}, http.StatusCreated)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 1}`}, http.StatusCreated)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/1/seats", Body: `{"seat_code": 2, "seat_left": 100, "kiosk_disabled": 1}`}, http.StatusCreated)
+	mustServe(t, r, testRequest{Method: "POST", Target: "/rooms/2/seats", Body: `{"seat_code": 3}`}, http.StatusCreated)
+
+	tests := []struct {
+		name      string
+		role      string
+		wantSeats int
+	}{
+		{"키오스크", consts.ROLE_KIOSK, 1},
+		{"직원", consts.ROLE_STAFF, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := mustServe(t, r, testRequest{Method: "GET", Target: "/seats", Role: tt.role}, http.StatusOK)
+			var seats []Seat
+			json.Unmarshal(rec.Body.Bytes(), &seats)
+			if len(seats) != tt.wantSeats {
+				t.Fatalf("GET /seats = %s, want %d개", rec.Body.String(), tt.wantSeats)
+			}
+		})
+	}
+
+	for _, target := range []string{"/seats/2", "/seats/3", "/rooms/2/seats"} {
+		mustServe(t, r, testRequest{Method: "GET", Target: target, Role: consts.ROLE_KIOSK}, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,207 @@
+// staff.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// Staff 구조체는 staff_table(점주/직원 로그인 계정)의 각 컬럼을 매핑합니다.
+// 비밀번호는 bcrypt 해시로만 저장하며 응답에 포함하지 않습니다.
+type Staff struct {
+	StaffID     int       `json:"staff_id" db:"staff_id"`
+	CompanyCode int       `json:"company_code"`
+	LoginID     string    `json:"login_id"`
+	StaffName   string    `json:"staff_name"`
+	Role        string    `json:"role"`
+	Enabled     int       `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// EnsureOwner는 회사에 점주 계정이 하나도 없으면 지정한 로그인 정보로 점주 계정을 만듭니다.
+// 최초 설치 시 로그인할 계정을 준비하는 용도이며, 이미 점주가 있으면 아무것도 하지 않습니다.
+func EnsureOwner(ctx context.Context, companyCode int, loginID, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	res, err := utils.DB.ExecContext(ctx, `
+		INSERT INTO staff_table (company_code, login_id, password_hash, staff_name, role)
+		SELECT $1, $2, $3, $2, $4
+		WHERE NOT EXISTS (SELECT 1 FROM staff_table WHERE company_code = $1 AND role = $4)`,
+		companyCode, loginID, string(hash), consts.ROLE_OWNER)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("점주 계정 생성: company_code=%d, login_id=%s", companyCode, loginID)
+	}
+	return nil
+}
+
+// RegisterStaffRoutes는 점주/직원 계정 관리 엔드포인트를 등록합니다. (점주 전용)
+func RegisterStaffRoutes(r *mux.Router) {
+	r.Handle("/staff", allow(GetStaff, ownerOnly...)).Methods("GET")
+	r.Handle("/staff", allow(CreateStaff, ownerOnly...)).Methods("POST")
+	r.Handle("/staff/{staff_id}", allow(DeleteStaff, ownerOnly...)).Methods("DELETE")
+}
+
+// GetStaff: 호출자 회사의 점주/직원 계정 목록을 조회합니다.
+func GetStaff(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	rows, err := utils.DB.QueryContext(ctx, `
+		SELECT staff_id, company_code, login_id, staff_name, role, enabled, created_at
+		FROM staff_table WHERE company_code = $1
+		ORDER BY staff_id ASC`, utils.CompanyCode(r.Context()))
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []Staff{}
+	for rows.Next() {
+		var s Staff
+		if err := rows.Scan(&s.StaffID, &s.CompanyCode, &s.LoginID, &s.StaffName,
+			&s.Role, &s.Enabled, &s.CreatedAt); err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, s)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// createStaffRequest는 계정 생성 요청 본문입니다. role을 생략하면 직원 계정을 만듭니다.
+type createStaffRequest struct {
+	LoginID   string `json:"login_id"`
+	Password  string `json:"password"`
+	StaffName string `json:"staff_name"`
+	Role      string `json:"role"`
+}
+
+// CreateStaff: 호출자 회사에 점주/직원 계정을 생성합니다.
+func CreateStaff(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var req createStaffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	req.LoginID = strings.TrimSpace(req.LoginID)
+	if req.LoginID == "" || len(req.Password) < 8 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "login_id와 8자 이상의 password가 필요합니다.")
+		return
+	}
+	if req.Role == "" {
+		req.Role = consts.ROLE_STAFF
+	}
+	if req.Role != consts.ROLE_OWNER && req.Role != consts.ROLE_STAFF {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "잘못된 role 값")
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	staff := Staff{
+		CompanyCode: utils.CompanyCode(r.Context()),
+		LoginID:     req.LoginID,
+		StaffName:   strings.TrimSpace(req.StaffName),
+		Role:        req.Role,
+		Enabled:     1,
+	}
+	err = utils.DB.QueryRowContext(ctx, `
+		INSERT INTO staff_table (company_code, login_id, password_hash, staff_name, role, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING staff_id, created_at`,
+		staff.CompanyCode, staff.LoginID, string(hash), staff.StaffName, staff.Role, staff.Enabled).
+		Scan(&staff.StaffID, &staff.CreatedAt)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		if isPQError(err, "23505") { // unique_violation (staff_company_login_idx)
+			writeProblem(w, consts.ERR_CONFLICT, "이미 사용 중인 login_id입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(staff)
+}
+
+// DeleteStaff: 계정을 삭제합니다. 본인 계정은 삭제할 수 없습니다.
+func DeleteStaff(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	staffID, err := strconv.Atoi(mux.Vars(r)["staff_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 staff_id")
+		return
+	}
+	if claims, _ := utils.ClaimsFromContext(r.Context()); claims.SubjectID() == staffID {
+		writeProblem(w, consts.ERR_CONFLICT, "본인 계정은 삭제할 수 없습니다.")
+		return
+	}
+
+	res, err := utils.DB.ExecContext(ctx,
+		"DELETE FROM staff_table WHERE staff_id = $1 AND company_code = $2",
+		staffID, utils.CompanyCode(r.Context()))
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Staff를 찾을 수 없습니다.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticateStaff는 회사/로그인 ID/비밀번호로 활성화된 점주/직원 계정을 확인합니다.
+// 계정이 없거나 비밀번호가 틀리면 sql.ErrNoRows를 반환합니다.
+func authenticateStaff(ctx context.Context, companyCode int, loginID, password string) (Staff, error) {
+	var s Staff
+	var hash string
+	err := utils.DB.QueryRowContext(ctx, `
+		SELECT staff_id, company_code, login_id, staff_name, role, enabled, created_at, password_hash
+		FROM staff_table WHERE company_code = $1 AND login_id = $2 AND enabled <> 0`,
+		companyCode, loginID).
+		Scan(&s.StaffID, &s.CompanyCode, &s.LoginID, &s.StaffName, &s.Role, &s.Enabled, &s.CreatedAt, &hash)
+	if err != nil {
+		return s, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return s, sql.ErrNoRows
+	}
+	return s, nil
+}
@@ -0,0 +1,59 @@
+package tables
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// TestResolveTenantIgnoresCompanyCodeHeader는 클라이언트가 보낸 X-Company-Code 헤더로
+// 테넌트가 정해지지 않는지 확인합니다.
+func TestResolveTenantIgnoresCompanyCodeHeader(t *testing.T) {
+	utils.SetJWTSecret([]byte("test-secret"))
+	token, _, err := utils.IssueToken(utils.Claims{
+		Subject:     "1",
+		Role:        consts.ROLE_STAFF,
+		CompanyCode: 7,
+		TokenType:   consts.TOKEN_TYPE_ACCESS,
+	}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := utils.TenantMiddleware(ResolveTenant)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strconv.Itoa(utils.CompanyCode(r.Context()))))
+	}))
+
+	tests := []struct {
+		name       string
+		auth       string
+		wantStatus int
+		wantTenant string
+	}{
+		{"헤더만 있으면 거부", "", http.StatusUnauthorized, ""},
+		{"토큰의 company_code 사용", "Bearer " + token, http.StatusOK, "7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/rooms", nil)
+			req.Header.Set("X-Company-Code", "9")
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantTenant != "" && rec.Body.String() != tt.wantTenant {
+				t.Fatalf("company_code = %s, want %s", rec.Body.String(), tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// validation.go
+package tables
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+
+	"AllinB/src/consts"
+)
+
+// fieldErrors는 필드명 → 필드 오류 코드(consts.FIELD_*)입니다.
+type fieldErrors map[string]string
+
+// merge는 other의 오류를 prefix를 붙인 필드명으로 추가합니다. (예: "rooms[0].")
+func (e fieldErrors) merge(prefix string, other fieldErrors) {
+	for field, code := range other {
+		e[prefix+field] = code
+	}
+}
+
+// only는 fields에 있는 필드의 오류만 남깁니다.
+// 수정 요청에서 바꾸지 않은 기존 값 때문에 요청이 거부되지 않도록 할 때 사용합니다.
+func (e fieldErrors) only(fields map[string]interface{}) fieldErrors {
+	result := make(fieldErrors)
+	for field, code := range e {
+		if _, ok := fields[field]; ok {
+			result[field] = code
+		}
+	}
+	return result
+}
+
+// hexColor는 #RGB, #RRGGBB, #RRGGBBAA 형식의 색상입니다.
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})$`)
+
+// validateRoom은 room의 필드 값을 검사합니다.
+// 색상 형식, 위치/크기/차단기 번호의 음수 여부, 0/1 플래그, gender, 제목 길이를 확인합니다.
+func validateRoom(room Room) fieldErrors {
+	errs := make(fieldErrors)
+	checkTitle(errs, "room_title", room.RoomTitle)
+	checkColors(errs, map[string]string{
+		"title_background_color": room.TitleBackgroundColor,
+		"title_text_color":       room.TitleTextColor,
+		"room_background_color":  room.RoomBackgroundColor,
+	})
+	checkNonNegative(errs, map[string]int{
+		"room_top":       room.RoomTop,
+		"room_left":      room.RoomLeft,
+		"room_width":     room.RoomWidth,
+		"room_height":    room.RoomHeight,
+		"breaker_number": room.BreakerNumber,
+	})
+	checkFlags(errs, map[string]int{
+		"waiting":                room.Waiting,
+		"release":                room.Release,
+		"hide_title":             room.HideTitle,
+		"transparent_background": room.TransparentBackground,
+		"hide_border":            room.HideBorder,
+		"kiosk_disabled":         room.KioskDisabled,
+		"power_control":          room.PowerControl,
+	})
+	if !validGender(room.Gender) {
+		errs["gender"] = consts.FIELD_INVALID_GENDER
+	}
+	return errs
+}
+
+// validateSeat은 seat의 필드 값을 검사합니다. 규칙은 validateRoom과 같습니다.
+func validateSeat(seat Seat) fieldErrors {
+	errs := make(fieldErrors)
+	checkTitle(errs, "seat_title", seat.SeatTitle)
+	checkColors(errs, map[string]string{
+		"title_background_color": seat.TitleBackgroundColor,
+		"title_text_color":       seat.TitleTextColor,
+		"seat_background_color":  seat.SeatBackgroundColor,
+	})
+	checkNonNegative(errs, map[string]int{
+		"seat_top":       seat.SeatTop,
+		"seat_left":      seat.SeatLeft,
+		"seat_width":     seat.SeatWidth,
+		"seat_height":    seat.SeatHeight,
+		"breaker_number": seat.BreakerNumber,
+	})
+	checkFlags(errs, map[string]int{
+		"waiting":                seat.Waiting,
+		"release":                seat.Release,
+		"hide_title":             seat.HideTitle,
+		"transparent_background": seat.TransparentBackground,
+		"hide_border":            seat.HideBorder,
+		"kiosk_disabled":         seat.KioskDisabled,
+		"power_control":          seat.PowerControl,
+	})
+	if !validGender(seat.Gender) {
+		errs["gender"] = consts.FIELD_INVALID_GENDER
+	}
+	return errs
+}
+
+// checkTitle은 제목이 TITLE_MAX_LENGTH 글자 이하인지 확인합니다. (바이트가 아닌 글자 수)
+func checkTitle(errs fieldErrors, field, title string) {
+	if utf8.RuneCountInString(title) > consts.TITLE_MAX_LENGTH {
+		errs[field] = consts.FIELD_TOO_LONG
+	}
+}
+
+// checkColors는 색상 필드가 16진수 색상 형식인지 확인합니다.
+func checkColors(errs fieldErrors, colors map[string]string) {
+	for field, color := range colors {
+		if !hexColor.MatchString(color) {
+			errs[field] = consts.FIELD_INVALID_COLOR
+		}
+	}
+}
+
+// checkNonNegative는 값이 0 이상인지 확인합니다.
+func checkNonNegative(errs fieldErrors, values map[string]int) {
+	for field, v := range values {
+		if v < 0 {
+			errs[field] = consts.FIELD_NEGATIVE
+		}
+	}
+}
+
+// checkFlags는 플래그 필드가 0 또는 1인지 확인합니다.
+func checkFlags(errs fieldErrors, flags map[string]int) {
+	for field, v := range flags {
+		if v != 0 && v != 1 {
+			errs[field] = consts.FIELD_INVALID_FLAG
+		}
+	}
+}
+
+// decodeFieldErrors는 JSON 디코딩 오류가 필드 타입 불일치이면 필드 오류로 바꿉니다.
+func decodeFieldErrors(err error) (fieldErrors, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return nil, false
+	}
+	code := consts.FIELD_NOT_STRING
+	switch typeErr.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		code = consts.FIELD_NOT_INTEGER
+	}
+	return fieldErrors{typeErr.Field: code}, true
+}
+
+// writeDecodeError는 요청 본문 디코딩 오류로 응답합니다.
+// 필드 타입이 맞지 않으면 422와 필드 오류, 그 외에는 400입니다.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	if errs, ok := decodeFieldErrors(err); ok {
+		writeFieldErrors(w, errs)
+		return
+	}
+	writeProblem(w, consts.ERR_INVALID_BODY, "요청 본문이 올바른 JSON이 아닙니다")
+}
@@ -0,0 +1,523 @@
+// waitlist.go
+package tables
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// WaitlistEntry 구조체는 waitlist_table의 각 컬럼을 매핑합니다.
+// SeatCode와 PromotedAt은 좌석이 배정(승격)된 뒤에만 값이 있습니다.
+type WaitlistEntry struct {
+	WaitlistID  int        `json:"waitlist_id" db:"waitlist_id"`
+	CompanyCode int        `json:"company_code"`
+	RoomCode    int        `json:"room_code"`
+	MemberID    int        `json:"member_id"`
+	Position    int        `json:"position"`
+	Status      int        `json:"status"`
+	SeatCode    *int       `json:"seat_code"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PromotedAt  *time.Time `json:"promoted_at"`
+}
+
+// RegisterWaitlistRoutes는 room 대기열 관련 엔드포인트를 등록합니다.
+// 회원은 본인 명의로 대기 등록만 할 수 있으며, 조회/재정렬/취소는 점주/직원이 합니다.
+func RegisterWaitlistRoutes(r *mux.Router) {
+	r.Handle("/rooms/{room_code}/waitlist", allow(GetWaitlist, staffRoles...)).Methods("GET")
+	r.Handle("/rooms/{room_code}/waitlist", allow(JoinWaitlist, memberRoles...)).Methods("POST")
+	r.Handle("/rooms/{room_code}/waitlist/order", allow(ReorderWaitlist, staffRoles...)).Methods("PUT")
+	r.Handle("/rooms/{room_code}/waitlist/{waitlist_id}", allow(LeaveWaitlist, staffRoles...)).Methods("DELETE")
+}
+
+// RegisterWaitlistJobs는 대기열 관련 비동기 작업 처리 함수를 등록합니다.
+func RegisterWaitlistJobs() {
+	utils.RegisterJobPayloadHandler("WaitlistPromote", promoteWaitlist)
+	utils.RegisterJobHandler("WaitlistHoldExpire", expireWaitlistHolds)
+}
+
+// waitlistPromotePayload는 WaitlistPromote 작업의 데이터입니다.
+type waitlistPromotePayload struct {
+	CompanyCode int `json:"company_code"`
+	SeatCode    int `json:"seat_code"`
+}
+
+// seatWaitlistColumns는 변경 시 좌석을 대기자에게 다시 배정해 볼 seat_table 컬럼입니다.
+var seatWaitlistColumns = map[string]bool{
+	"waiting": true,
+	"release": true,
+}
+
+const waitlistColumns = `waitlist_id, company_code, room_code, member_id,
+	position, status, seat_code, created_at, promoted_at`
+
+// scanWaitlistEntry는 waitlistColumns 순서로 조회한 행을 WaitlistEntry로 변환합니다.
+func scanWaitlistEntry(row interface{ Scan(...interface{}) error }) (WaitlistEntry, error) {
+	var e WaitlistEntry
+	err := row.Scan(&e.WaitlistID, &e.CompanyCode, &e.RoomCode, &e.MemberID,
+		&e.Position, &e.Status, &e.SeatCode, &e.CreatedAt, &e.PromotedAt)
+	return e, err
+}
+
+// GetWaitlist: room의 대기열을 순서대로 조회합니다.
+// 기본은 대기 중인 항목만 반환하며, status 쿼리 파라미터로 다른 상태를 조회할 수 있습니다.
+func GetWaitlist(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	roomCode, err := strconv.Atoi(mux.Vars(r)["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+	status := consts.WAITLIST_STATUS_WAITING
+	if v := r.URL.Query().Get("status"); v != "" {
+		if status, err = strconv.Atoi(v); err != nil {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 status 값")
+			return
+		}
+	}
+
+	rows, err := utils.DB.QueryContext(ctx, `
+		SELECT `+waitlistColumns+` FROM waitlist_table
+		WHERE company_code = $1 AND room_code = $2 AND status = $3
+		ORDER BY position ASC, waitlist_id ASC`, companyCode, roomCode, status)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []WaitlistEntry{}
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, e)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// joinWaitlistRequest는 대기열 등록 요청 본문입니다.
+type joinWaitlistRequest struct {
+	MemberID int `json:"member_id"`
+}
+
+// JoinWaitlist: 회원을 room 대기열의 맨 뒤에 등록합니다.
+// room의 Waiting 플래그가 꺼져 있으면 409를 반환합니다.
+func JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	roomCode, err := strconv.Atoi(mux.Vars(r)["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req joinWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if !bindCallerMember(w, r, &req.MemberID) {
+		return
+	}
+
+	var roomWaiting, roomGender int
+	err = utils.DB.QueryRowContext(ctx,
+		"SELECT waiting, gender FROM room_table WHERE room_code = $1 AND company_code = $2",
+		roomCode, companyCode).
+		Scan(&roomWaiting, &roomGender)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "Room을 찾을 수 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if roomWaiting == 0 {
+		writeProblem(w, consts.ERR_CONFLICT, "대기열을 사용하지 않는 room입니다.")
+		return
+	}
+
+	member, err := getMember(ctx, companyCode, req.MemberID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "존재하지 않는 member_id입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if member.Status != consts.MEMBER_STATUS_ACTIVE {
+		writeProblem(w, consts.ERR_MEMBER_INACTIVE, "이용할 수 없는 회원 상태입니다.")
+		return
+	}
+	if !genderAllowed(roomGender, member.Gender) {
+		writeProblem(w, consts.ERR_GENDER_MISMATCH, "룸의 성별 제한과 회원 성별이 일치하지 않습니다.")
+		return
+	}
+
+	row := utils.DB.QueryRowContext(ctx, `
+		INSERT INTO waitlist_table (company_code, room_code, member_id, position, status)
+		SELECT $1, $2, $3, COALESCE(MAX(position), 0) + 1, $4
+		FROM waitlist_table WHERE company_code = $1 AND room_code = $2 AND status = $4
+		RETURNING `+waitlistColumns,
+		member.CompanyCode, roomCode, member.MemberID, consts.WAITLIST_STATUS_WAITING)
+	entry, err := scanWaitlistEntry(row)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		if isPQError(err, "23505") { // unique_violation (waitlist_company_room_member_idx)
+			writeProblem(w, consts.ERR_CONFLICT, "이미 대기 중인 회원입니다")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// reorderWaitlistRequest는 대기열 재정렬 요청 본문입니다.
+type reorderWaitlistRequest struct {
+	WaitlistIDs []int `json:"waitlist_ids"`
+}
+
+// ReorderWaitlist: 대기 중인 항목의 순서를 전달받은 waitlist_ids 순서로 변경합니다.
+// 요청 목록은 현재 대기 중인 항목 전체와 정확히 일치해야 합니다.
+func ReorderWaitlist(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	roomCode, err := strconv.Atoi(mux.Vars(r)["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req reorderWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT waitlist_id FROM waitlist_table
+		WHERE company_code = $1 AND room_code = $2 AND status = $3 FOR UPDATE`,
+		companyCode, roomCode, consts.WAITLIST_STATUS_WAITING)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	current := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			writeInternalError(w, err)
+			return
+		}
+		current[id] = true
+	}
+	rows.Close()
+
+	if len(req.WaitlistIDs) != len(current) {
+		writeProblem(w, consts.ERR_CONFLICT, "waitlist_ids가 현재 대기열과 일치하지 않습니다.")
+		return
+	}
+	seen := make(map[int]bool)
+	for _, id := range req.WaitlistIDs {
+		if !current[id] || seen[id] {
+			writeProblem(w, consts.ERR_CONFLICT, fmt.Sprintf("waitlist_id %d가 현재 대기열과 일치하지 않습니다.", id))
+			return
+		}
+		seen[id] = true
+	}
+
+	for i, id := range req.WaitlistIDs {
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE waitlist_table SET position = $1 WHERE waitlist_id = $2", i+1, id); err != nil {
+			writeInternalError(w, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	GetWaitlist(w, r)
+}
+
+// LeaveWaitlist: 대기 항목을 취소합니다.
+// 좌석이 배정된 항목을 취소하면 해당 좌석을 다음 대기자에게 다시 배정합니다.
+func LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	roomCode, err := strconv.Atoi(vars["room_code"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 room_code")
+		return
+	}
+	waitlistID, err := strconv.Atoi(vars["waitlist_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 waitlist_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var seatCode sql.NullInt64
+	err = utils.DB.QueryRowContext(ctx, `
+		UPDATE waitlist_table SET status = $1
+		WHERE waitlist_id = $2 AND company_code = $3 AND room_code = $4 AND status IN ($5, $6)
+		RETURNING seat_code`,
+		consts.WAITLIST_STATUS_CANCELLED, waitlistID, companyCode, roomCode,
+		consts.WAITLIST_STATUS_WAITING, consts.WAITLIST_STATUS_PROMOTED).Scan(&seatCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			writeProblem(w, consts.ERR_NOT_FOUND, "취소할 수 있는 대기 항목이 없습니다.")
+		} else {
+			writeInternalError(w, err)
+		}
+		return
+	}
+	if seatCode.Valid {
+		enqueueWaitlistPromote(companyCode, int(seatCode.Int64))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// enqueueWaitlistPromote는 빈 좌석을 대기자에게 배정하는 작업을 큐에 넣습니다.
+func enqueueWaitlistPromote(companyCode, seatCode int) {
+	job := utils.Job{
+		Name: "WaitlistPromote",
+		Data: map[string]interface{}{
+			"company_code": companyCode,
+			"seat_code":    seatCode,
+			"time":         time.Now(),
+		},
+		Priority: consts.JOB_PRIORITY_NORMAL,
+	}
+	if utils.EnqueueJobHandler != nil {
+		utils.EnqueueJobHandler(job)
+	}
+}
+
+// promoteWaitlist는 비어 있는 좌석을 해당 room 대기열의 첫 번째(성별 제한을 만족하는) 회원에게 배정합니다.
+// room과 seat 모두 Waiting 플래그가 켜져 있어야 하며, 배정된 좌석은 WAITLIST_HOLD_MINUTES 동안 잡아둡니다.
+// WaitlistPromote 작업으로 실행됩니다.
+func promoteWaitlist(ctx context.Context, payload waitlistPromotePayload) error {
+	companyCode, seatCode := payload.CompanyCode, payload.SeatCode
+	if companyCode <= 0 {
+		return fmt.Errorf("company_code가 없는 WaitlistPromote 작업")
+	}
+	if seatCode <= 0 {
+		return fmt.Errorf("seat_code가 없는 WaitlistPromote 작업")
+	}
+
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var roomCode, seatWaiting, seatGender, roomWaiting, roomGender int
+	err = tx.QueryRowContext(ctx, `
+		SELECT s.room_code, s.waiting, s.gender, r.waiting, r.gender
+		FROM seat_table s
+		JOIN room_table r ON r.room_code = s.room_code AND r.company_code = s.company_code
+		WHERE s.company_code = $1 AND s.seat_code = $2`, companyCode, seatCode).
+		Scan(&roomCode, &seatWaiting, &seatGender, &roomWaiting, &roomGender)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if seatWaiting == 0 || roomWaiting == 0 {
+		return nil
+	}
+
+	// 이미 사용 중이거나 다른 대기자에게 배정된 좌석이면 건너뜁니다.
+	now := time.Now()
+	holdSince := now.Add(-time.Duration(consts.WAITLIST_HOLD_MINUTES) * time.Minute)
+	var busy bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM seat_session_table
+		              WHERE company_code = $1 AND seat_code = $2 AND end_time IS NULL)
+		    OR EXISTS(SELECT 1 FROM waitlist_table
+		              WHERE company_code = $1 AND seat_code = $2 AND status = $3 AND promoted_at > $4)`,
+		companyCode, seatCode, consts.WAITLIST_STATUS_PROMOTED, holdSince).Scan(&busy)
+	if err != nil {
+		return err
+	}
+	if busy {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT w.waitlist_id, w.member_id, m.gender
+		FROM waitlist_table w
+		JOIN member_table m ON m.member_id = w.member_id AND m.company_code = w.company_code
+		WHERE w.company_code = $1 AND w.room_code = $2 AND w.status = $3
+		ORDER BY w.position ASC, w.waitlist_id ASC
+		FOR UPDATE OF w SKIP LOCKED`, companyCode, roomCode, consts.WAITLIST_STATUS_WAITING)
+	if err != nil {
+		return err
+	}
+	waitlistID, memberID := 0, 0
+	for rows.Next() {
+		var id, mid, gender int
+		if err := rows.Scan(&id, &mid, &gender); err != nil {
+			rows.Close()
+			return err
+		}
+		if genderAllowed(seatGender, gender) && genderAllowed(roomGender, gender) {
+			waitlistID, memberID = id, mid
+			break
+		}
+	}
+	rows.Close()
+	if waitlistID == 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE waitlist_table SET status = $1, seat_code = $2, promoted_at = $3
+		WHERE waitlist_id = $4`,
+		consts.WAITLIST_STATUS_PROMOTED, seatCode, now, waitlistID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("대기자 좌석 배정: company_code=%d, waitlist_id=%d, seat_code=%d", companyCode, waitlistID, seatCode)
+	if utils.EnqueueJobHandler != nil {
+		utils.EnqueueJobHandler(utils.Job{
+			Name: "WaitlistPromoted",
+			Data: map[string]interface{}{
+				"waitlist_id":  waitlistID,
+				"company_code": companyCode,
+				"room_code":    roomCode,
+				"seat_code":    seatCode,
+				"member_id":    memberID,
+				"time":         now,
+			},
+		})
+	}
+	return nil
+}
+
+// claimWaitlistHold는 체크인 시점에 좌석이 대기자에게 배정되어 있는지 확인합니다.
+// 다른 회원에게 배정된 좌석이면 heldByOther가 true이며,
+// 본인에게 배정된 좌석이면 해당 대기 항목 ID를 반환합니다. (없으면 0)
+func claimWaitlistHold(ctx context.Context, tx *sql.Tx, companyCode, seatCode, memberID int, now time.Time) (ownID int, heldByOther bool, err error) {
+	holdSince := now.Add(-time.Duration(consts.WAITLIST_HOLD_MINUTES) * time.Minute)
+	rows, err := tx.QueryContext(ctx, `
+		SELECT waitlist_id, member_id FROM waitlist_table
+		WHERE company_code = $1 AND seat_code = $2 AND status = $3 AND promoted_at > $4`,
+		companyCode, seatCode, consts.WAITLIST_STATUS_PROMOTED, holdSince)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, owner int
+		if err := rows.Scan(&id, &owner); err != nil {
+			return 0, false, err
+		}
+		if owner == memberID {
+			ownID = id
+		} else {
+			heldByOther = true
+		}
+	}
+	return ownID, heldByOther, rows.Err()
+}
+
+// markWaitlistSeated는 배정된 좌석에 체크인한 대기 항목을 완료 처리합니다.
+func markWaitlistSeated(ctx context.Context, tx *sql.Tx, waitlistID int) error {
+	_, err := tx.ExecContext(ctx,
+		"UPDATE waitlist_table SET status = $1 WHERE waitlist_id = $2 AND status = $3",
+		consts.WAITLIST_STATUS_SEATED, waitlistID, consts.WAITLIST_STATUS_PROMOTED)
+	return err
+}
+
+// expireWaitlistHolds는 WAITLIST_HOLD_MINUTES 안에 체크인하지 않은 좌석 배정을 해제하고
+// 그 좌석을 다음 대기자에게 배정하는 작업을 큐에 넣습니다.
+// WaitlistHoldExpire 작업으로 주기 실행됩니다.
+func expireWaitlistHolds(ctx context.Context, job utils.Job) error {
+	holdSince := time.Now().Add(-time.Duration(consts.WAITLIST_HOLD_MINUTES) * time.Minute)
+	rows, err := utils.DB.QueryContext(ctx, `
+		UPDATE waitlist_table SET status = $1
+		WHERE status = $2 AND promoted_at <= $3
+		RETURNING waitlist_id, company_code, room_code, seat_code, member_id`,
+		consts.WAITLIST_STATUS_EXPIRED, consts.WAITLIST_STATUS_PROMOTED, holdSince)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	expired := 0
+	for rows.Next() {
+		var waitlistID, companyCode, roomCode, memberID int
+		var seatCode sql.NullInt64
+		if err := rows.Scan(&waitlistID, &companyCode, &roomCode, &seatCode, &memberID); err != nil {
+			return err
+		}
+		expired++
+		enqueueEvent("WaitlistHoldExpired", map[string]interface{}{
+			"waitlist_id":  waitlistID,
+			"company_code": companyCode,
+			"room_code":    roomCode,
+			"seat_code":    int(seatCode.Int64),
+			"member_id":    memberID,
+		})
+		if seatCode.Valid {
+			enqueueWaitlistPromote(companyCode, int(seatCode.Int64))
+		}
+	}
+	if expired > 0 {
+		log.Printf("대기열 좌석 배정 %d건 해제", expired)
+	}
+	return rows.Err()
+}
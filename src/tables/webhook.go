@@ -0,0 +1,619 @@
+// webhook.go
+package tables
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+
+	"AllinB/src/consts"
+	"AllinB/src/utils"
+)
+
+// Webhook 구조체는 webhook_table의 각 컬럼을 매핑합니다.
+// Events가 비어 있으면 모든 이벤트를 전달합니다.
+// 서명 키(Secret)는 등록 시 한 번만 응답합니다.
+type Webhook struct {
+	WebhookID   int       `json:"webhook_id"`
+	CompanyCode int       `json:"company_code"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Enabled     int       `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Secret      string    `json:"secret,omitempty"`
+}
+
+// WebhookDelivery 구조체는 webhook_delivery_table의 각 컬럼(전송 시도 기록)을 매핑합니다.
+type WebhookDelivery struct {
+	DeliveryID  int64     `json:"delivery_id"`
+	WebhookID   int       `json:"webhook_id"`
+	CompanyCode int       `json:"company_code"`
+	JobID       int64     `json:"job_id"`
+	EventType   string    `json:"event_type"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code"`
+	Success     int       `json:"success"`
+	Error       string    `json:"error"`
+	DurationMs  int       `json:"duration_ms"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// webhook 요청 헤더
+// 수신 측은 X-AllinB-Timestamp + "." + 요청 본문을 서명 키로 HMAC-SHA256하여 X-AllinB-Signature와 비교합니다.
+const (
+	webhookEventHeader     = "X-AllinB-Event"
+	webhookDeliveryHeader  = "X-AllinB-Delivery"
+	webhookTimestampHeader = "X-AllinB-Timestamp"
+	webhookSignatureHeader = "X-AllinB-Signature"
+)
+
+// webhookClient는 webhook 전송에 사용하는 HTTP 클라이언트입니다.
+// 리다이렉트는 따라가지 않고 실패로 처리합니다.
+// 등록 후 DNS가 바뀌어도 내부망으로 요청하지 않도록, 실제 연결할 IP를 webhookDialControl로 확인합니다.
+// 같은 이유로 환경 변수의 프록시도 사용하지 않습니다.
+var webhookClient = &http.Client{
+	Timeout: time.Duration(consts.WEBHOOK_TIMEOUT) * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: time.Duration(consts.WEBHOOK_TIMEOUT) * time.Second,
+			Control: webhookDialControl,
+		}).DialContext,
+		TLSHandshakeTimeout: time.Duration(consts.WEBHOOK_TIMEOUT) * time.Second,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// errWebhookAddressBlocked는 webhook이 내부망 주소로 연결하려 할 때 반환됩니다.
+var errWebhookAddressBlocked = errors.New("내부망 주소로는 webhook을 보낼 수 없습니다")
+
+// blockedWebhookIP는 webhook이 연결하면 안 되는 주소인지 확인합니다.
+// loopback, 사설망(RFC 1918, fc00::/7), link-local(169.254.0.0/16, 클라우드 메타데이터 169.254.169.254 포함),
+// 미지정/멀티캐스트 주소를 막습니다.
+func blockedWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// webhookDialControl은 이름 해석이 끝난 뒤 연결 직전에 호출되어 내부망 주소로의 연결을 거부합니다.
+func webhookDialControl(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || blockedWebhookIP(ip) {
+		return fmt.Errorf("%s: %w", host, errWebhookAddressBlocked)
+	}
+	return nil
+}
+
+// RegisterWebhookRoutes는 webhook 구독 관리 엔드포인트를 등록합니다. (점주 전용)
+func RegisterWebhookRoutes(r *mux.Router) {
+	r.Handle("/webhooks", allow(GetWebhooks, ownerOnly...)).Methods("GET")
+	r.Handle("/webhooks", allow(CreateWebhook, ownerOnly...)).Methods("POST")
+	r.Handle("/webhooks/{webhook_id}", allow(GetWebhook, ownerOnly...)).Methods("GET")
+	r.Handle("/webhooks/{webhook_id}", allow(UpdateWebhook, ownerOnly...)).Methods("PUT")
+	r.Handle("/webhooks/{webhook_id}", allow(DeleteWebhook, ownerOnly...)).Methods("DELETE")
+	r.Handle("/webhooks/{webhook_id}/deliveries", allow(GetWebhookDeliveries, ownerOnly...)).Methods("GET")
+}
+
+// RegisterWebhookJobs는 webhook 전송 작업 처리 함수를 등록합니다.
+func RegisterWebhookJobs() {
+	utils.RegisterJobPayloadHandler("WebhookDeliver", deliverWebhook)
+}
+
+const webhookColumns = `webhook_id, company_code, url, events, enabled, created_at, updated_at`
+
+// scanWebhook은 webhookColumns 순서로 조회한 행을 Webhook으로 변환합니다.
+func scanWebhook(row interface{ Scan(...interface{}) error }) (Webhook, error) {
+	var h Webhook
+	err := row.Scan(&h.WebhookID, &h.CompanyCode, &h.URL, pq.Array(&h.Events), &h.Enabled,
+		&h.CreatedAt, &h.UpdatedAt)
+	if h.Events == nil {
+		h.Events = []string{}
+	}
+	return h, err
+}
+
+// validWebhookURL은 http/https 절대 URL인지 확인합니다.
+// 호스트가 IP 주소이면 내부망 주소가 아니어야 합니다. (이름은 전송 시 webhookDialControl이 확인합니다)
+func validWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+	if strings.EqualFold(u.Hostname(), "localhost") {
+		return false
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && blockedWebhookIP(ip) {
+		return false
+	}
+	return true
+}
+
+// normalizeWebhookEvents는 이벤트 필터를 검증하고 중복을 제거합니다.
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	known := make(map[string]bool, len(eventJobNames))
+	for _, name := range eventJobNames {
+		known[name] = true
+	}
+	seen := make(map[string]bool, len(events))
+	result := []string{}
+	for _, name := range events {
+		if !known[name] {
+			return nil, fmt.Errorf("알 수 없는 이벤트: %s", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// GetWebhooks: 호출자의 company_code에 등록된 webhook 목록을 조회합니다.
+func GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	rows, err := utils.DB.QueryContext(ctx,
+		"SELECT "+webhookColumns+" FROM webhook_table WHERE company_code = $1 ORDER BY webhook_id ASC",
+		utils.CompanyCode(r.Context()))
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []Webhook{}
+	for rows.Next() {
+		h, err := scanWebhook(rows)
+		if err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, h)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetWebhook: webhook 하나를 조회합니다.
+func GetWebhook(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	webhookID, err := strconv.Atoi(mux.Vars(r)["webhook_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 webhook_id")
+		return
+	}
+	h, err := scanWebhook(utils.DB.QueryRowContext(ctx,
+		"SELECT "+webhookColumns+" FROM webhook_table WHERE webhook_id = $1 AND company_code = $2",
+		webhookID, utils.CompanyCode(r.Context())))
+	if err == sql.ErrNoRows {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Webhook을 찾을 수 없습니다.")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h)
+}
+
+// CreateWebhook: webhook을 등록하고 서명 키를 발급합니다.
+// 발급된 secret은 이 응답에서만 확인할 수 있습니다.
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var h Webhook
+	if err := json.NewDecoder(r.Body).Decode(&h); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	// webhook은 항상 호출자의 company_code로 등록합니다.
+	h.CompanyCode = utils.CompanyCode(r.Context())
+	h.URL = strings.TrimSpace(h.URL)
+	if !validWebhookURL(h.URL) {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "url은 내부망이 아닌 http 또는 https 주소여야 합니다.")
+		return
+	}
+	events, err := normalizeWebhookEvents(h.Events)
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, err.Error())
+		return
+	}
+	h.Events = events
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	h.Secret = hex.EncodeToString(raw)
+	h.Enabled = 1
+
+	err = utils.DB.QueryRowContext(ctx, `
+		INSERT INTO webhook_table (company_code, url, secret, events, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING webhook_id, created_at, updated_at`,
+		h.CompanyCode, h.URL, h.Secret, pq.Array(h.Events), h.Enabled).
+		Scan(&h.WebhookID, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		log.Printf("DB 오류: %v", err)
+		writeInternalError(w, err)
+		return
+	}
+	log.Printf("webhook 등록: webhook_id=%d, company_code=%d", h.WebhookID, h.CompanyCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(h)
+}
+
+// UpdateWebhook: url, events, enabled 중 요청에 포함된 필드만 변경합니다.
+func UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	webhookID, err := strconv.Atoi(mux.Vars(r)["webhook_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 webhook_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var req struct {
+		CompanyCode *int      `json:"company_code"`
+		URL         *string   `json:"url"`
+		Events      *[]string `json:"events"`
+		Enabled     *int      `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, consts.ERR_INVALID_BODY, "잘못된 요청 데이터")
+		return
+	}
+	if req.CompanyCode != nil && *req.CompanyCode != companyCode {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "company_code는 변경할 수 없습니다.")
+		return
+	}
+
+	var updates []string
+	var args []interface{}
+	if req.URL != nil {
+		u := strings.TrimSpace(*req.URL)
+		if !validWebhookURL(u) {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, "url은 내부망이 아닌 http 또는 https 주소여야 합니다.")
+			return
+		}
+		args = append(args, u)
+		updates = append(updates, "url = $"+strconv.Itoa(len(args)))
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(*req.Events)
+		if err != nil {
+			writeProblem(w, consts.ERR_INVALID_REQUEST, err.Error())
+			return
+		}
+		args = append(args, pq.Array(events))
+		updates = append(updates, "events = $"+strconv.Itoa(len(args)))
+	}
+	if req.Enabled != nil {
+		enabled := 0
+		if *req.Enabled != 0 {
+			enabled = 1
+		}
+		args = append(args, enabled)
+		updates = append(updates, "enabled = $"+strconv.Itoa(len(args)))
+	}
+	if len(updates) == 0 {
+		writeProblem(w, consts.ERR_INVALID_REQUEST, "업데이트할 필드가 없습니다")
+		return
+	}
+	updates = append(updates, "updated_at = now()")
+
+	args = append(args, webhookID, companyCode)
+	query := "UPDATE webhook_table SET " + strings.Join(updates, ", ") +
+		" WHERE webhook_id = $" + strconv.Itoa(len(args)-1) + " AND company_code = $" + strconv.Itoa(len(args)) +
+		" RETURNING " + webhookColumns
+	h, err := scanWebhook(utils.DB.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Webhook을 찾을 수 없습니다.")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h)
+}
+
+// DeleteWebhook: webhook과 전송 기록을 삭제합니다.
+// 이미 큐에 들어간 전송 작업은 webhook이 없으므로 전송하지 않고 끝납니다.
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	webhookID, err := strconv.Atoi(mux.Vars(r)["webhook_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 webhook_id")
+		return
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	tx, err := utils.DB.BeginTx(ctx, nil)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		"DELETE FROM webhook_table WHERE webhook_id = $1 AND company_code = $2", webhookID, companyCode)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Webhook을 찾을 수 없습니다.")
+		return
+	}
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM webhook_delivery_table WHERE webhook_id = $1 AND company_code = $2", webhookID, companyCode); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries: webhook의 최근 전송 시도 기록을 최신순으로 조회합니다.
+// limit 쿼리로 개수를 지정할 수 있습니다. (기본 50, 최대 500)
+func GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	timeout := time.Duration(consts.DEFAULT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	webhookID, err := strconv.Atoi(mux.Vars(r)["webhook_id"])
+	if err != nil {
+		writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 webhook_id")
+		return
+	}
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 || limit > 500 {
+			writeProblem(w, consts.ERR_INVALID_PARAMETER, "잘못된 limit")
+			return
+		}
+	}
+	companyCode := utils.CompanyCode(r.Context())
+
+	var exists bool
+	err = utils.DB.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM webhook_table WHERE webhook_id = $1 AND company_code = $2)",
+		webhookID, companyCode).Scan(&exists)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if !exists {
+		writeProblem(w, consts.ERR_NOT_FOUND, "Webhook을 찾을 수 없습니다.")
+		return
+	}
+
+	rows, err := utils.DB.QueryContext(ctx, `
+		SELECT delivery_id, webhook_id, company_code, job_id, event_type, attempt,
+		       status_code, success, error, duration_ms, created_at
+		FROM webhook_delivery_table
+		WHERE webhook_id = $1 AND company_code = $2
+		ORDER BY delivery_id DESC
+		LIMIT $3`, webhookID, companyCode, limit)
+	if err != nil {
+		log.Printf("데이터베이스 쿼리 오류: %v", err)
+		writeProblem(w, consts.ERR_INTERNAL, "데이터 조회 중 오류가 발생했습니다")
+		return
+	}
+	defer rows.Close()
+
+	result := []WebhookDelivery{}
+	for rows.Next() {
+		var d WebhookDelivery
+		if err := rows.Scan(&d.DeliveryID, &d.WebhookID, &d.CompanyCode, &d.JobID, &d.EventType, &d.Attempt,
+			&d.StatusCode, &d.Success, &d.Error, &d.DurationMs, &d.CreatedAt); err != nil {
+			log.Printf("행 스캔 오류: %v", err)
+			writeProblem(w, consts.ERR_INTERNAL, "데이터 처리 중 오류가 발생했습니다")
+			return
+		}
+		result = append(result, d)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// webhookEventBody는 webhook으로 전송하는 요청 본문입니다.
+type webhookEventBody struct {
+	Type        string                 `json:"type"`
+	CompanyCode int                    `json:"company_code"`
+	RoomCode    int                    `json:"room_code,omitempty"`
+	SeatCode    int                    `json:"seat_code,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	Time        time.Time              `json:"time"`
+}
+
+// webhookDeliveryPayload는 WebhookDeliver 작업의 데이터입니다.
+// 재시도 시에도 같은 본문을 보내도록 직렬화된 본문(Body)을 그대로 저장합니다.
+type webhookDeliveryPayload struct {
+	CompanyCode int    `json:"company_code"`
+	WebhookID   int    `json:"webhook_id"`
+	EventType   string `json:"event_type"`
+	Body        string `json:"body"`
+}
+
+// enqueueWebhookDeliveries는 이벤트를 구독 중인 활성 webhook마다 WebhookDeliver 작업을 큐에 넣습니다.
+func enqueueWebhookDeliveries(ctx context.Context, body webhookEventBody) error {
+	rows, err := utils.DB.QueryContext(ctx, `
+		SELECT webhook_id FROM webhook_table
+		WHERE company_code = $1 AND enabled <> 0
+		  AND (cardinality(events) = 0 OR $2 = ANY(events))`,
+		body.CompanyCode, body.Type)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var webhookIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		webhookIDs = append(webhookIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(webhookIDs) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	for _, id := range webhookIDs {
+		if utils.EnqueueJobHandler != nil {
+			utils.EnqueueJobHandler(utils.Job{
+				Name: "WebhookDeliver",
+				Data: map[string]interface{}{
+					"company_code": body.CompanyCode,
+					"webhook_id":   id,
+					"event_type":   body.Type,
+					"body":         string(raw),
+				},
+				Priority: consts.JOB_PRIORITY_LOW,
+			})
+		}
+	}
+	return nil
+}
+
+// signWebhook은 timestamp + "." + body의 HMAC-SHA256 서명을 "sha256=<hex>" 형식으로 반환합니다.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverWebhook은 서명한 이벤트를 webhook URL로 POST하고 시도 결과를 webhook_delivery_table에 기록합니다.
+// 2xx가 아닌 응답이나 전송 오류는 작업 실패로 반환하여 큐의 재시도(지수 백오프)와 dead letter 처리를 따릅니다.
+// WebhookDeliver 작업으로 실행됩니다.
+func deliverWebhook(ctx context.Context, p webhookDeliveryPayload) error {
+	info, _ := utils.JobInfoFromContext(ctx)
+
+	var target, secret string
+	var enabled int
+	err := utils.DB.QueryRowContext(ctx,
+		"SELECT url, secret, enabled FROM webhook_table WHERE webhook_id = $1 AND company_code = $2",
+		p.WebhookID, p.CompanyCode).Scan(&target, &secret, &enabled)
+	if err == sql.ErrNoRows {
+		// 전송 전에 삭제된 webhook입니다.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if enabled == 0 {
+		return nil
+	}
+
+	body := []byte(p.Body)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(p.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, p.EventType)
+	req.Header.Set(webhookDeliveryHeader, strconv.FormatInt(info.ID, 10))
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, signWebhook(secret, timestamp, body))
+
+	start := time.Now()
+	statusCode := 0
+	resp, deliverErr := webhookClient.Do(req)
+	if deliverErr == nil {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+		statusCode = resp.StatusCode
+		if statusCode < 200 || statusCode >= 300 {
+			deliverErr = fmt.Errorf("webhook 응답 상태 %d", statusCode)
+		}
+	}
+	duration := time.Since(start)
+
+	// 작업 컨텍스트가 만료되었을 수 있으므로 전송 기록은 별도 컨텍스트로 저장합니다.
+	recordTimeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	recordCtx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+	success, errText := 1, ""
+	if deliverErr != nil {
+		success, errText = 0, deliverErr.Error()
+	}
+	if _, err := utils.DB.ExecContext(recordCtx, `
+		INSERT INTO webhook_delivery_table
+		(webhook_id, company_code, job_id, event_type, attempt, status_code, success, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		p.WebhookID, p.CompanyCode, info.ID, p.EventType, info.Attempt,
+		statusCode, success, errText, duration.Milliseconds()); err != nil {
+		log.Printf("webhook 전송 기록 실패: webhook_id=%d: %v", p.WebhookID, err)
+	}
+
+	if deliverErr != nil {
+		return fmt.Errorf("webhook 전송 실패: %w", deliverErr)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package tables
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidWebhookURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://hooks.example.com/allinb", true},
+		{"http://203.0.113.10:8080/hook", true},
+		{"ftp://hooks.example.com/allinb", false},
+		{"/relative", false},
+		{"http://localhost:8080/hook", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://10.0.0.5/hook", false},
+		{"http://172.16.3.4/hook", false},
+		{"http://192.168.0.10/hook", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[::1]/hook", false},
+		{"http://[fd00::1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+	}
+	for _, tt := range tests {
+		if got := validWebhookURL(tt.url); got != tt.want {
+			t.Errorf("validWebhookURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+// 등록 시 검사를 통과한 이름이 나중에 내부망 주소로 바뀌어도 연결 단계에서 거부해야 합니다.
+func TestWebhookClientRefusesInternalAddress(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	resp, err := webhookClient.Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("loopback 주소로 요청이 전송되었습니다")
+	}
+	if !errors.Is(err, errWebhookAddressBlocked) {
+		t.Fatalf("err = %v, want %v", err, errWebhookAddressBlocked)
+	}
+	if called {
+		t.Fatal("서버가 요청을 받았습니다")
+	}
+}
@@ -0,0 +1,46 @@
+// auth.go
+package utils
+
+import (
+	"context"
+	"net/http"
+
+	"AllinB/src/consts"
+)
+
+// claimsContextKey는 인증된 호출자의 claims를 요청 컨텍스트에 저장할 때 사용하는 키입니다.
+type claimsContextKey struct{}
+
+// WithClaims는 claims가 저장된 컨텍스트를 반환합니다.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext는 인증 과정에서 저장된 호출자의 claims를 반환합니다.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsContextKey{}).(Claims)
+	return c, ok
+}
+
+// RequireRole: 지정한 역할의 호출자만 통과시키는 미들웨어
+// 인증 정보가 없으면 401, 허용되지 않은 역할이면 403을 problem 문서로 응답합니다.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				WriteError(w, consts.ERR_UNAUTHENTICATED, "인증이 필요합니다.")
+				return
+			}
+			if !allowed[claims.Role] {
+				WriteError(w, consts.ERR_FORBIDDEN, "이 작업을 수행할 권한이 없습니다.")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// event_broker.go
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"AllinB/src/consts"
+)
+
+// Event는 실시간 스트림(/events)으로 전달되는 상태 변경 이벤트입니다.
+// ID는 프로세스 안에서 단조 증가하며, 클라이언트는 재연결 시 마지막으로 받은 ID부터 이어받을 수 있습니다.
+type Event struct {
+	ID          int64                  `json:"id"`
+	Type        string                 `json:"type"`
+	CompanyCode int                    `json:"company_code"`
+	RoomCode    int                    `json:"room_code,omitempty"`
+	SeatCode    int                    `json:"seat_code,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	Time        time.Time              `json:"time"`
+}
+
+// EventFilter는 구독자가 받을 이벤트를 고르는 함수입니다.
+type EventFilter func(Event) bool
+
+// EventSubscription은 브로커 구독입니다.
+// 구독자가 이벤트를 제때 읽지 못해 버퍼가 가득 차면 C가 닫히며, 클라이언트는 다시 연결해 이어받아야 합니다.
+type EventSubscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter EventFilter
+}
+
+// EventBroker는 최근 이벤트를 링 버퍼에 보관하고 구독자에게 전달하는 프로세스 내 브로커입니다.
+type EventBroker struct {
+	mu     sync.Mutex
+	nextID int64
+	buffer []Event // 최근 이벤트 (오래된 순)
+	size   int
+	subs   map[*EventSubscription]struct{}
+}
+
+// NewEventBroker는 최근 이벤트를 size개까지 보관하는 브로커를 생성합니다.
+func NewEventBroker(size int) *EventBroker {
+	return &EventBroker{
+		nextID: 1,
+		size:   size,
+		subs:   make(map[*EventSubscription]struct{}),
+	}
+}
+
+// Events는 서버 전체에서 사용하는 이벤트 브로커입니다.
+var Events = NewEventBroker(consts.EVENT_BUFFER_SIZE)
+
+// Publish는 이벤트에 ID를 부여하고 버퍼에 저장한 뒤 구독자에게 전달합니다.
+func (b *EventBroker) Publish(ev Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ev.ID = b.nextID
+	b.nextID++
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	b.buffer = append(b.buffer, ev)
+	if len(b.buffer) > b.size {
+		b.buffer = b.buffer[len(b.buffer)-b.size:]
+	}
+
+	for sub := range b.subs {
+		if sub.filter != nil && !sub.filter(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			// 느린 구독자는 끊고, 재연결 시 last-event-id로 이어받게 합니다.
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return ev
+}
+
+// Subscribe는 filter를 만족하는 이벤트를 받는 구독을 등록합니다.
+// lastID가 0보다 크면 그 이후의 버퍼된 이벤트를 backlog로 함께 반환합니다.
+// lastID 이후 이벤트 중 일부가 이미 버퍼에서 밀려났다면 complete는 false이며,
+// 클라이언트는 전체 상태를 다시 조회해야 합니다.
+func (b *EventBroker) Subscribe(lastID int64, filter EventFilter) (sub *EventSubscription, backlog []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		if lastID >= b.nextID {
+			// 서버 재시작 등으로 알 수 없는 ID입니다.
+			complete = false
+		} else if len(b.buffer) > 0 && b.buffer[0].ID > lastID+1 {
+			complete = false
+		}
+		for _, ev := range b.buffer {
+			if ev.ID > lastID && (filter == nil || filter(ev)) {
+				backlog = append(backlog, ev)
+			}
+		}
+	}
+
+	ch := make(chan Event, consts.EVENT_SUBSCRIBER_BUFFER)
+	sub = &EventSubscription{C: ch, ch: ch, filter: filter}
+	b.subs[sub] = struct{}{}
+	return sub, backlog, complete
+}
+
+// Unsubscribe는 구독을 해제합니다.
+func (b *EventBroker) Unsubscribe(sub *EventSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
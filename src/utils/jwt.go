@@ -0,0 +1,110 @@
+// jwt.go
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidToken은 토큰 형식/서명이 잘못되었거나 만료되었을 때 반환됩니다.
+var ErrInvalidToken = errors.New("유효하지 않은 토큰입니다")
+
+// Claims는 JWT 페이로드입니다.
+// Subject는 역할에 따라 staff_id, kiosk_id 또는 member_id입니다.
+type Claims struct {
+	Subject     string `json:"sub"`
+	Role        string `json:"role"`
+	CompanyCode int    `json:"company_code"`
+	TokenType   string `json:"typ"`
+	IssuedAt    int64  `json:"iat"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+// SubjectID는 Subject를 정수 ID로 반환합니다. (숫자가 아니면 0)
+func (c Claims) SubjectID() int {
+	id, _ := strconv.Atoi(c.Subject)
+	return id
+}
+
+// jwtHeader는 HS256으로 서명하는 JWT의 고정 헤더입니다.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+var (
+	jwtSecret   []byte
+	jwtSecretMu sync.RWMutex
+)
+
+// SetJWTSecret은 토큰 서명에 사용할 비밀 키를 설정합니다.
+func SetJWTSecret(secret []byte) {
+	jwtSecretMu.Lock()
+	defer jwtSecretMu.Unlock()
+	jwtSecret = secret
+}
+
+// signJWT는 header.payload 문자열의 HS256 서명을 계산합니다.
+func signJWT(signingInput string) ([]byte, error) {
+	jwtSecretMu.RLock()
+	defer jwtSecretMu.RUnlock()
+	if len(jwtSecret) == 0 {
+		return nil, errors.New("JWT 비밀 키가 설정되지 않았습니다")
+	}
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil), nil
+}
+
+// IssueToken은 claims에 발급/만료 시각을 채워 서명된 토큰을 만듭니다.
+func IssueToken(claims Claims, ttl time.Duration) (string, Claims, error) {
+	now := time.Now()
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = now.Add(ttl).Unix()
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", claims, err
+	}
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sig, err := signJWT(signingInput)
+	if err != nil {
+		return "", claims, err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), claims, nil
+}
+
+// ParseToken은 토큰의 서명과 만료 시각을 검증하고 claims를 반환합니다.
+// HS256 이외의 알고리즘으로 서명된 토큰은 거부합니다.
+func ParseToken(token string) (Claims, error) {
+	var claims Claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return claims, ErrInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	expected, err := signJWT(parts[0] + "." + parts[1])
+	if err != nil {
+		return claims, err
+	}
+	if !hmac.Equal(sig, expected) {
+		return claims, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(payload, &claims) != nil {
+		return claims, ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return claims, ErrInvalidToken
+	}
+	return claims, nil
+}
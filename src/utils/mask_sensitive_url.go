@@ -19,3 +19,59 @@ func MaskSensitiveURL(url string) string {
 
 	return credentials[0] + ":" + credentials[1] + ":******@" + parts[1]
 }
+
+// MaskPhoneNumber는 전화번호의 가운데 자리를 가립니다. (예: 010-1234-5678 → 010-****-5678)
+func MaskPhoneNumber(phone string) string {
+	if phone == "" {
+		return ""
+	}
+	digits := 0
+	for _, c := range phone {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	if digits < 7 {
+		return "[비표준 전화번호 형식]"
+	}
+
+	// 앞 3자리와 뒤 4자리 숫자만 남기고 나머지 숫자를 가립니다.
+	var b strings.Builder
+	seen := 0
+	for _, c := range phone {
+		if c >= '0' && c <= '9' {
+			seen++
+			if seen > 3 && seen <= digits-4 {
+				b.WriteRune('*')
+				continue
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// sensitiveHeaders는 로그에 값을 남기지 않는 요청 헤더입니다. (http.CanonicalHeaderKey 형식)
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Kiosk-Key":         true,
+}
+
+// MaskHeaderValues는 인증 정보가 담긴 헤더의 값을 가립니다.
+// Authorization 계열은 인증 방식(예: Bearer)만 남기고, 그 외 민감한 헤더는 값 전체를 가립니다.
+func MaskHeaderValues(name string, values []string) []string {
+	if !sensitiveHeaders[name] {
+		return values
+	}
+	masked := make([]string, len(values))
+	for i, value := range values {
+		if scheme, _, ok := strings.Cut(value, " "); ok && strings.HasSuffix(name, "Authorization") {
+			masked[i] = scheme + " ******"
+		} else {
+			masked[i] = "******"
+		}
+	}
+	return masked
+}
@@ -28,10 +28,10 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		// 요청 방법, 경로, 클라이언트 IP 로깅
 		log.Printf("[요청] %s %s FROM %s", r.Method, r.URL.Path, clientIP)
 
-		// 요청 헤더 로깅 (디버깅 목적)
+		// 요청 헤더 로깅 (디버깅 목적, 토큰/키오스크 키 등 인증 정보는 가립니다)
 		if os.Getenv("DEBUG") == "true" {
 			for name, values := range r.Header {
-				log.Printf("[헤더] %s: %s", name, values)
+				log.Printf("[헤더] %s: %s", name, MaskHeaderValues(name, values))
 			}
 		}
 
@@ -66,13 +66,20 @@ func (rw *responseWrapper) Write(b []byte) (int, error) {
 	return size, err
 }
 
+// Unwrap은 원래 ResponseWriter를 반환합니다.
+// http.ResponseController가 이를 통해 Flush/Hijack을 사용할 수 있습니다. (SSE, WebSocket)
+func (rw *responseWrapper) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // CorsMiddleware: CORS 관련 헤더를 추가하는 미들웨어
 func CorsMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// 실제 운영환경에서는 허용할 도메인을 제한하세요.
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Fields")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Fields, X-Kiosk-Id, X-Kiosk-Key, Last-Event-ID, If-Match, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, ETag")
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
 			return
@@ -4,7 +4,11 @@ package utils
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"AllinB/src/consts"
@@ -23,6 +27,29 @@ type Job struct {
 	Priority int // 높을수록 우선순위 높음
 }
 
+// Int는 Data[key]를 int로 반환합니다.
+// 큐를 거치며 숫자 타입이 바뀔 수 있어 int, int64, float64를 모두 허용합니다.
+func (j Job) Int(key string) (int, bool) {
+	switch v := j.Data[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// Decode는 Data를 payload 구조체(json 태그 기준)로 변환합니다.
+func (j Job) Decode(payload interface{}) error {
+	raw, err := json.Marshal(j.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, payload)
+}
+
 // 작업 큐에 추가하기 위한 함수 참조
 var EnqueueJobHandler EnqueueJobFunc
 
@@ -31,58 +58,315 @@ func SetEnqueueJobFunc(fn EnqueueJobFunc) {
 	EnqueueJobHandler = fn
 }
 
-// jobQueue는 버퍼링된 채널로, 최대 100개의 작업을 저장할 수 있습니다.
-var jobQueue = make(chan Job, 100)
+// JobHandlerFunc는 이름으로 등록되어 작업을 실제로 처리하는 함수입니다.
+type JobHandlerFunc func(ctx context.Context, job Job) error
+
+// jobHandlers는 Job.Name별로 등록된 처리 함수입니다.
+var (
+	jobHandlers   = make(map[string]JobHandlerFunc)
+	jobHandlersMu sync.RWMutex
+)
+
+// ErrNoJobHandler는 처리 함수가 등록되지 않은 작업을 실행하려 할 때 반환됩니다.
+var ErrNoJobHandler = errors.New("등록된 작업 처리 함수가 없습니다")
+
+// RegisterJobHandler는 Job.Name에 대한 처리 함수를 등록합니다.
+func RegisterJobHandler(name string, fn JobHandlerFunc) {
+	jobHandlersMu.Lock()
+	defer jobHandlersMu.Unlock()
+	jobHandlers[name] = fn
+}
+
+// RegisterJobPayloadHandler는 Job.Data를 payload 타입 T로 변환하여 전달하는 처리 함수를 등록합니다.
+// 변환에 실패한 작업은 오류로 처리됩니다.
+func RegisterJobPayloadHandler[T any](name string, fn func(ctx context.Context, payload T) error) {
+	RegisterJobHandler(name, func(ctx context.Context, job Job) error {
+		var payload T
+		if err := job.Decode(&payload); err != nil {
+			return fmt.Errorf("%s 작업 데이터 변환 실패: %w", name, err)
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// jobHandler는 Job.Name에 등록된 처리 함수를 반환합니다.
+func jobHandler(name string) (JobHandlerFunc, bool) {
+	jobHandlersMu.RLock()
+	defer jobHandlersMu.RUnlock()
+	fn, ok := jobHandlers[name]
+	return fn, ok
+}
+
+// ScheduleJob은 interval마다 작업을 큐에 넣는 주기 실행기를 시작합니다.
+func ScheduleJob(job Job, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			EnqueueJob(job)
+		}
+	}()
+}
+
+// jobWakeup은 같은 프로세스에서 작업이 추가되었을 때 대기 중인 워커를 깨웁니다.
+var jobWakeup = make(chan struct{}, 1)
 
-// EnqueueJob은 작업을 큐에 추가합니다.
+// EnqueueJob은 작업을 job_table에 저장합니다.
+// 저장된 작업은 서버가 재시작되어도 유지되며, 실패하면 백오프 후 재시도됩니다.
 func EnqueueJob(job Job) {
+	timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	data, err := json.Marshal(job.Data)
+	if err != nil {
+		log.Printf("Job enqueue failed: %s: %v", job.Name, err)
+		return
+	}
+	var jobID int64
+	err = DB.QueryRowContext(ctx, `
+		INSERT INTO job_table (name, data, priority, status, max_attempts)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING job_id`,
+		job.Name, data, job.Priority, consts.JOB_STATUS_PENDING, consts.JOB_MAX_ATTEMPTS).Scan(&jobID)
+	if err != nil {
+		log.Printf("Job enqueue failed: %s: %v", job.Name, err)
+		return
+	}
+	log.Printf("Job enqueued: %s (job_id=%d)", job.Name, jobID)
+
 	select {
-	case jobQueue <- job:
-		log.Printf("Job enqueued: %s", job.Name)
+	case jobWakeup <- struct{}{}:
 	default:
-		log.Printf("Job queue full, dropping job: %s", job.Name)
+	}
+}
+
+// leasedJob은 워커가 임대한 작업과 임대 시점의 시도 횟수입니다.
+// attempts는 완료/실패를 기록할 때 임대가 아직 유효한지 확인하는 데 사용합니다.
+type leasedJob struct {
+	Job
+	ID          int64
+	Attempts    int
+	MaxAttempts int
+}
+
+// leaseJob은 실행할 작업 하나를 임대합니다. 실행할 작업이 없으면 sql.ErrNoRows를 반환합니다.
+// 대기 중이며 실행 시각이 된 작업과, 임대 시간(visibility timeout)이 지난 실행 중 작업을 대상으로 합니다.
+func leaseJob(ctx context.Context) (leasedJob, error) {
+	var lj leasedJob
+	var data []byte
+	visibility := time.Duration(consts.JOB_VISIBILITY_TIMEOUT) * time.Second
+	err := DB.QueryRowContext(ctx, `
+		UPDATE job_table SET status = $1, attempts = attempts + 1,
+		       leased_until = now() + $2 * interval '1 second', updated_at = now()
+		WHERE job_id = (
+			SELECT job_id FROM job_table
+			WHERE (status = $3 AND run_at <= now())
+			   OR (status = $1 AND leased_until < now())
+			ORDER BY priority DESC, run_at ASC, job_id ASC
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1)
+		RETURNING job_id, name, data, priority, attempts, max_attempts`,
+		consts.JOB_STATUS_RUNNING, visibility.Seconds(), consts.JOB_STATUS_PENDING).
+		Scan(&lj.ID, &lj.Name, &data, &lj.Priority, &lj.Attempts, &lj.MaxAttempts)
+	if err != nil {
+		return lj, err
+	}
+	if err := json.Unmarshal(data, &lj.Data); err != nil {
+		// 재시도해도 해석할 수 없으므로 바로 dead letter로 옮깁니다.
+		lj.MaxAttempts = lj.Attempts
+		cause := fmt.Errorf("job_id %d 데이터 해석 실패: %w", lj.ID, err)
+		if ferr := failJob(ctx, lj, cause); ferr != nil {
+			return lj, ferr
+		}
+		return lj, cause
+	}
+	return lj, nil
+}
+
+// completeJob은 임대한 작업을 완료 처리합니다.
+func completeJob(ctx context.Context, lj leasedJob) error {
+	_, err := DB.ExecContext(ctx, `
+		UPDATE job_table SET status = $1, leased_until = NULL, last_error = NULL, updated_at = now()
+		WHERE job_id = $2 AND status = $3 AND attempts = $4`,
+		consts.JOB_STATUS_DONE, lj.ID, consts.JOB_STATUS_RUNNING, lj.Attempts)
+	return err
+}
+
+// failJob은 실패한 작업을 백오프 후 재시도하도록 되돌리거나,
+// 최대 시도 횟수를 넘겼으면 dead letter 상태로 옮깁니다.
+func failJob(ctx context.Context, lj leasedJob, cause error) error {
+	status := consts.JOB_STATUS_PENDING
+	if lj.Attempts >= lj.MaxAttempts {
+		status = consts.JOB_STATUS_DEAD
+	}
+	delay := retryDelay(lj.Attempts)
+	_, err := DB.ExecContext(ctx, `
+		UPDATE job_table SET status = $1, leased_until = NULL, last_error = $2,
+		       run_at = now() + $3 * interval '1 second', updated_at = now()
+		WHERE job_id = $4 AND status = $5 AND attempts = $6`,
+		status, cause.Error(), delay.Seconds(), lj.ID, consts.JOB_STATUS_RUNNING, lj.Attempts)
+	if err == nil {
+		if status == consts.JOB_STATUS_DEAD {
+			log.Printf("Job dead-lettered: %s (job_id=%d, attempts=%d): %v", lj.Name, lj.ID, lj.Attempts, cause)
+		} else {
+			log.Printf("Job failed, retry in %v: %s (job_id=%d, attempt %d/%d): %v",
+				delay, lj.Name, lj.ID, lj.Attempts, lj.MaxAttempts, cause)
+		}
+	}
+	return err
+}
+
+// retryDelay는 attempts번째 실패 이후의 재시도 대기 시간(지수 백오프)을 반환합니다.
+func retryDelay(attempts int) time.Duration {
+	delay := time.Duration(consts.JOB_RETRY_BASE_DELAY) * time.Second
+	maxDelay := time.Duration(consts.JOB_RETRY_MAX_DELAY) * time.Second
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// purgeDoneJobs는 보관 기간이 지난 완료 작업을 삭제합니다. dead letter 작업은 남겨 둡니다.
+func purgeDoneJobs(ctx context.Context) {
+	res, err := DB.ExecContext(ctx, `
+		DELETE FROM job_table
+		WHERE status = $1 AND updated_at < now() - $2 * interval '1 hour'`,
+		consts.JOB_STATUS_DONE, consts.JOB_RETENTION_HOURS)
+	if err != nil {
+		log.Printf("완료 작업 정리 오류: %v", err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("완료 작업 %d건 정리", n)
 	}
 }
 
 // StartJobWorker는 백그라운드에서 큐의 작업을 처리하는 워커를 시작합니다.
 func StartJobWorker() {
-	go func() {
-		for job := range jobQueue {
-			processJob(job)
-		}
-	}()
+	StartJobWorkers(1)
 }
 
-// 워커 수를 구성 가능하게 만듦
+// StartJobWorkers는 workerCount개의 워커를 시작합니다.
+// 각 워커는 우선순위(Job.Priority)가 높은 작업부터 임대하여 처리합니다.
 func StartJobWorkers(workerCount int) {
 	for i := 0; i < workerCount; i++ {
 		go func(id int) {
 			log.Printf("Worker %d started", id)
-			for job := range jobQueue {
-				processJob(job)
-			}
+			runJobWorker()
 		}(i)
 	}
+
+	// 완료 작업 정리는 워커 수와 관계없이 한 번만 주기 실행합니다.
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			timeout := time.Duration(consts.LONG_QUERY_TIMEOUT) * time.Second
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			purgeDoneJobs(ctx)
+			cancel()
+		}
+	}()
+}
+
+// runJobWorker는 작업을 임대하여 처리하고, 처리할 작업이 없으면 새 작업이나 다음 폴링 시점까지 대기합니다.
+func runJobWorker() {
+	poll := time.Duration(consts.JOB_POLL_INTERVAL) * time.Second
+	for {
+		timeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		lj, err := leaseJob(ctx)
+		cancel()
+
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Printf("Job lease failed: %v", err)
+			}
+			select {
+			case <-jobWakeup:
+			case <-time.After(poll):
+			}
+			continue
+		}
+		processJob(lj)
+	}
+}
+
+// JobInfo는 실행 중인 작업의 job_table 정보입니다.
+type JobInfo struct {
+	ID          int64
+	Attempt     int // 현재 시도 횟수 (1부터)
+	MaxAttempts int
+}
+
+// jobInfoContextKey는 실행 중인 작업 정보를 처리 함수의 컨텍스트에 저장할 때 사용하는 키입니다.
+type jobInfoContextKey struct{}
+
+// JobInfoFromContext는 처리 함수의 컨텍스트에서 실행 중인 작업 정보를 반환합니다.
+func JobInfoFromContext(ctx context.Context) (JobInfo, bool) {
+	info, ok := ctx.Value(jobInfoContextKey{}).(JobInfo)
+	return info, ok
 }
 
-// processJob은 작업을 처리합니다. (여기서는 단순 로그 출력과 1초 Sleep으로 시뮬레이션)
-func processJob(job Job) {
+// runJobHandler는 등록된 처리 함수를 워커 고루틴에서 직접 실행합니다.
+// 처리 함수에는 DEFAULT_WORK_TIMEOUT이 지나면 취소되는 컨텍스트가 전달되며,
+// 시간 초과 후 반환된 결과는 성공하더라도 실패로 처리합니다.
+func runJobHandler(lj leasedJob) error {
+	job := lj.Job
+	handler, ok := jobHandler(job.Name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoJobHandler, job.Name)
+	}
+
 	timeout := time.Duration(consts.DEFAULT_WORK_TIMEOUT) * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	ctx = context.WithValue(ctx, jobInfoContextKey{}, JobInfo{ID: lj.ID, Attempt: lj.Attempts, MaxAttempts: lj.MaxAttempts})
 
-	done := make(chan bool)
-	go func() {
-		// 실제 작업 처리
-		log.Printf("Processing job: %s", job.Name)
-		// ... 작업 로직
-		done <- true
-	}()
+	// 실제 작업 처리
+	err := handler(ctx, job)
+	if ctxErr := ctx.Err(); ctxErr != nil && err == nil {
+		err = fmt.Errorf("작업 시간 초과: %w", ctxErr)
+	}
+	return err
+}
 
-	select {
-	case <-done:
-		log.Printf("Job processed: %s", job.Name)
-	case <-ctx.Done():
-		log.Printf("Job timed out: %s", job.Name)
+// processJob은 등록된 처리 함수로 임대한 작업을 처리하고 결과를 job_table에 기록합니다.
+func processJob(lj leasedJob) {
+	job := lj.Job
+
+	// 워커가 중단되어 임대가 만료된 작업도 시도 횟수에 포함되므로, 한도를 넘겼으면 실행하지 않습니다.
+	if lj.Attempts > lj.MaxAttempts {
+		recordTimeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+		recordCtx, recordCancel := context.WithTimeout(context.Background(), recordTimeout)
+		defer recordCancel()
+		if err := failJob(recordCtx, lj, fmt.Errorf("임대 만료가 반복되어 최대 시도 횟수를 넘었습니다")); err != nil {
+			log.Printf("Job failure record failed: %s (job_id=%d): %v", job.Name, lj.ID, err)
+		}
+		return
+	}
+
+	log.Printf("Processing job: %s (job_id=%d, priority=%d, attempt %d)", job.Name, lj.ID, job.Priority, lj.Attempts)
+	jobErr := runJobHandler(lj)
+
+	// 작업 컨텍스트가 만료되었을 수 있으므로 결과 기록은 별도 컨텍스트로 처리합니다.
+	recordTimeout := time.Duration(consts.SHORT_QUERY_TIMEOUT) * time.Second
+	recordCtx, recordCancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer recordCancel()
+
+	if jobErr != nil {
+		if err := failJob(recordCtx, lj, jobErr); err != nil {
+			log.Printf("Job failure record failed: %s (job_id=%d): %v", job.Name, lj.ID, err)
+		}
+		return
+	}
+	if err := completeJob(recordCtx, lj); err != nil {
+		log.Printf("Job completion record failed: %s (job_id=%d): %v", job.Name, lj.ID, err)
+		return
 	}
+	log.Printf("Job processed: %s (job_id=%d)", job.Name, lj.ID)
 }
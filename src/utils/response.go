@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"AllinB/src/consts"
+)
+
+// ErrorInfo는 오류 코드 카탈로그의 항목입니다.
+type ErrorInfo struct {
+	Code   string `json:"code"`
+	Status int    `json:"status"`
+	Title  string `json:"title"`
+}
+
+// errorCatalog는 API가 응답하는 오류 코드 목록입니다.
+// 코드는 바뀌지 않으므로 프론트엔드는 title/message 대신 code로 분기하고 메시지를 현지화합니다.
+var errorCatalog = []ErrorInfo{
+	{consts.ERR_INVALID_BODY, http.StatusBadRequest, "요청 본문을 해석할 수 없습니다"},
+	{consts.ERR_INVALID_PARAMETER, http.StatusBadRequest, "잘못된 요청 파라미터입니다"},
+	{consts.ERR_DUPLICATE_CODE, http.StatusBadRequest, "이미 존재하는 코드입니다"},
+	{consts.ERR_INVALID_REQUEST, http.StatusBadRequest, "요청 값이 올바르지 않습니다"},
+	{consts.ERR_UNAUTHENTICATED, http.StatusUnauthorized, "인증이 필요합니다"},
+	{consts.ERR_FORBIDDEN, http.StatusForbidden, "권한이 없습니다"},
+	{consts.ERR_GENDER_MISMATCH, http.StatusForbidden, "성별 제한과 회원 성별이 일치하지 않습니다"},
+	{consts.ERR_MEMBER_INACTIVE, http.StatusForbidden, "이용할 수 없는 회원 상태입니다"},
+	{consts.ERR_PASS_UNAVAILABLE, http.StatusForbidden, "사용할 수 있는 이용권이 없습니다"},
+	{consts.ERR_NOT_FOUND, http.StatusNotFound, "대상을 찾을 수 없습니다"},
+	{consts.ERR_CONFLICT, http.StatusConflict, "현재 상태와 충돌합니다"},
+	{consts.ERR_NO_OPEN_SESSION, http.StatusConflict, "사용 중인 세션이 없습니다"},
+	{consts.ERR_SEAT_OCCUPIED, http.StatusConflict, "이미 사용 중인 좌석입니다"},
+	{consts.ERR_SEAT_RESERVED, http.StatusConflict, "다른 회원에게 예약/배정된 좌석입니다"},
+	{consts.ERR_SEAT_IN_USE, http.StatusConflict, "사용 중이거나 예약/고정석 이용권이 있는 좌석은 삭제할 수 없습니다"},
+	{consts.ERR_LAYOUT_CONFLICT, http.StatusConflict, "좌석 배치가 겹치거나 room 영역을 벗어납니다"},
+	{consts.ERR_ROOM_NOT_EMPTY, http.StatusConflict, "좌석이 배정된 room은 삭제할 수 없습니다"},
+	{consts.ERR_ROOM_NOT_FOUND, http.StatusConflict, "seat이 가리키는 room이 없습니다"},
+	{consts.ERR_PATCH_TEST_FAILED, http.StatusConflict, "patch test 연산이 실패했습니다"},
+	{consts.ERR_PRECONDITION_FAILED, http.StatusPreconditionFailed, "다른 요청이 먼저 변경했습니다"},
+	{consts.ERR_UNSUPPORTED_MEDIA_TYPE, http.StatusUnsupportedMediaType, "지원하지 않는 Content-Type입니다"},
+	{consts.ERR_INVALID_FIELDS, http.StatusUnprocessableEntity, "필드 값이 올바르지 않습니다"},
+	{consts.ERR_PATCH_FAILED, http.StatusUnprocessableEntity, "patch를 적용할 수 없습니다"},
+	{consts.ERR_UPGRADE_REQUIRED, http.StatusUpgradeRequired, "지원하지 않는 WebSocket 버전입니다"},
+	{consts.ERR_INTERNAL, http.StatusInternalServerError, "서버 내부 오류입니다"},
+}
+
+// ErrorCatalog는 오류 코드 카탈로그 전체를 반환합니다.
+func ErrorCatalog() []ErrorInfo {
+	return append([]ErrorInfo(nil), errorCatalog...)
+}
+
+// LookupError는 카탈로그에서 오류 코드를 찾습니다.
+func LookupError(code string) (ErrorInfo, bool) {
+	for _, info := range errorCatalog {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return ErrorInfo{}, false
+}
+
+// Problem은 RFC 7807 problem details 문서입니다.
+// Code는 카탈로그의 오류 코드이며, Errors는 필드 검사 실패 시 필드별 원인입니다.
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Code   string       `json:"code"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError는 필드 하나의 검사 실패 원인입니다. Code는 consts의 FIELD_* 값입니다.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewProblem은 카탈로그의 상태 코드와 제목으로 problem 문서를 만듭니다.
+// 카탈로그에 없는 코드는 500으로 응답합니다.
+func NewProblem(code, detail string) Problem {
+	info, ok := LookupError(code)
+	if !ok {
+		info = ErrorInfo{Code: code, Status: http.StatusInternalServerError, Title: code}
+	}
+	return Problem{
+		Type:   "/errors/" + code,
+		Title:  info.Title,
+		Status: info.Status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// WriteError는 오류 코드에 해당하는 problem 문서로 응답합니다.
+func WriteError(w http.ResponseWriter, code, detail string) {
+	WriteProblem(w, NewProblem(code, detail))
+}
+
+// WriteProblem은 problem 문서를 application/problem+json으로 응답합니다.
+func WriteProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"AllinB/src/consts"
+)
+
+// ErrUnauthenticated는 요청에서 호출자(테넌트)를 확인할 수 없을 때 반환됩니다.
+var ErrUnauthenticated = errors.New("인증 정보가 없습니다")
+
+// TenantResolver는 요청의 인증 정보로 호출자의 company_code를 결정합니다.
+// 인증 과정에서 컨텍스트에 값을 추가할 수 있도록 갱신된 요청을 함께 반환합니다.
+type TenantResolver func(r *http.Request) (*http.Request, int, error)
+
+// tenantContextKey는 company_code를 요청 컨텍스트에 저장할 때 사용하는 키입니다.
+type tenantContextKey struct{}
+
+// WithCompanyCode는 company_code가 저장된 컨텍스트를 반환합니다.
+func WithCompanyCode(ctx context.Context, companyCode int) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, companyCode)
+}
+
+// CompanyCode는 TenantMiddleware가 저장한 호출자의 company_code를 반환합니다. (없으면 0)
+func CompanyCode(ctx context.Context) int {
+	code, _ := ctx.Value(tenantContextKey{}).(int)
+	return code
+}
+
+// TenantMiddleware: 호출자의 company_code를 결정하여 요청 컨텍스트에 저장하는 미들웨어
+// 테넌트를 확인할 수 없는 요청은 401을 problem 문서로 응답합니다.
+func TenantMiddleware(resolve TenantResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r, companyCode, err := resolve(r)
+			if err != nil {
+				if errors.Is(err, ErrUnauthenticated) {
+					WriteError(w, consts.ERR_UNAUTHENTICATED, err.Error())
+				} else {
+					log.Printf("테넌트 확인 오류: %v", err)
+					WriteError(w, consts.ERR_INTERNAL, "")
+				}
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithCompanyCode(r.Context(), companyCode)))
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// websocket.go
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"AllinB/src/consts"
+)
+
+// RFC 6455 opcode
+const (
+	wsOpText  byte = 0x1
+	wsOpClose byte = 0x8
+	wsOpPing  byte = 0x9
+	wsOpPong  byte = 0xA
+)
+
+// wsAcceptGUID는 Sec-WebSocket-Accept 계산에 쓰는 고정 GUID입니다.
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsMaxFrameSize는 클라이언트가 보내는 프레임의 최대 크기입니다.
+// 이벤트 스트림은 서버에서 클라이언트로만 데이터를 보내므로 작게 제한합니다.
+const wsMaxFrameSize = 64 * 1024
+
+// WebSocketConn은 서버 → 클라이언트 전송용 최소 WebSocket 연결입니다.
+// 클라이언트가 보내는 ping/close만 처리하고, 그 밖의 데이터 프레임은 무시합니다.
+type WebSocketConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+
+	mu     sync.Mutex // 쓰기 직렬화
+	closed chan struct{}
+	once   sync.Once
+}
+
+// IsWebSocketUpgrade는 요청이 WebSocket 업그레이드 요청인지 확인합니다.
+func IsWebSocketUpgrade(r *http.Request) bool {
+	return headerContainsToken(r.Header, "Connection", "upgrade") &&
+		headerContainsToken(r.Header, "Upgrade", "websocket")
+}
+
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpgradeWebSocket은 핸드셰이크를 수행하고 연결을 넘겨받습니다.
+// 실패하면 오류 응답을 이미 보낸 상태로 오류를 반환합니다.
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request) (*WebSocketConn, error) {
+	if r.Method != http.MethodGet || !IsWebSocketUpgrade(r) {
+		WriteError(w, consts.ERR_INVALID_REQUEST, "WebSocket 업그레이드 요청이 아닙니다.")
+		return nil, errors.New("websocket: 업그레이드 요청이 아닙니다")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		WriteError(w, consts.ERR_UPGRADE_REQUIRED, "지원하지 않는 WebSocket 버전입니다.")
+		return nil, errors.New("websocket: 지원하지 않는 버전")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		WriteError(w, consts.ERR_INVALID_REQUEST, "Sec-WebSocket-Key가 없습니다.")
+		return nil, errors.New("websocket: Sec-WebSocket-Key 없음")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		WriteError(w, consts.ERR_INTERNAL, "WebSocket을 지원하지 않는 연결입니다.")
+		return nil, fmt.Errorf("websocket: hijack 실패: %w", err)
+	}
+	// 핸드셰이크 전까지 남아 있을 수 있는 서버 타임아웃을 해제합니다.
+	conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: 핸드셰이크 전송 실패: %w", err)
+	}
+
+	ws := &WebSocketConn{conn: conn, rw: rw, closed: make(chan struct{})}
+	go ws.readLoop()
+	return ws, nil
+}
+
+// Closed는 클라이언트가 연결을 닫았거나 읽기 오류가 나면 닫히는 채널을 반환합니다.
+func (c *WebSocketConn) Closed() <-chan struct{} {
+	return c.closed
+}
+
+// WriteText는 텍스트 메시지를 보냅니다.
+func (c *WebSocketConn) WriteText(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// Ping은 연결 유지를 위한 ping 프레임을 보냅니다.
+func (c *WebSocketConn) Ping() error {
+	return c.writeFrame(wsOpPing, nil)
+}
+
+// Close는 close 프레임(1000, 정상 종료)을 보내고 연결을 닫습니다.
+func (c *WebSocketConn) Close() error {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, 1000)
+	c.writeFrame(wsOpClose, payload)
+	c.shutdown()
+	return c.conn.Close()
+}
+
+func (c *WebSocketConn) shutdown() {
+	c.once.Do(func() { close(c.closed) })
+}
+
+// writeFrame은 마스킹하지 않은 단일(FIN) 프레임을 보냅니다.
+func (c *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// readLoop는 클라이언트 프레임을 읽어 ping에는 pong으로, close에는 연결 종료로 응답합니다.
+func (c *WebSocketConn) readLoop() {
+	defer c.shutdown()
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return
+			}
+		case wsOpClose:
+			c.writeFrame(wsOpClose, payload)
+			c.conn.Close()
+			return
+		}
+	}
+}
+
+// readFrame은 클라이언트 프레임 하나를 읽고 마스킹을 해제합니다.
+func (c *WebSocketConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if !masked {
+		return 0, nil, errors.New("websocket: 마스킹되지 않은 클라이언트 프레임")
+	}
+	if length > wsMaxFrameSize {
+		return 0, nil, errors.New("websocket: 프레임이 너무 큽니다")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}